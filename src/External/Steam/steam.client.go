@@ -0,0 +1,268 @@
+package Steam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultAPIBaseURL   = "https://api.steampowered.com"
+	DefaultStoreBaseURL = "https://store.steampowered.com"
+
+	defaultTimeout     = 10 * time.Second
+	defaultMaxRetries  = 3
+	defaultBaseBackoff = 500 * time.Millisecond
+	defaultMaxBackoff  = 30 * time.Second
+
+	//the store API allows roughly 200 requests every 5 minutes per IP
+	defaultStoreRequests = 200
+	defaultStoreWindow   = 5 * time.Minute
+)
+
+//ErrSteamStatus is returned when Steam answers with a status we cannot do anything with
+type ErrSteamStatus struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *ErrSteamStatus) Error() string {
+	return fmt.Sprintf("steam responded with status %d", e.StatusCode)
+}
+
+//SteamClientConfig holds everything needed to talk to Steam. Zero values are replaced by sensible defaults.
+type SteamClientConfig struct {
+	APIBaseURL   string
+	StoreBaseURL string
+	APIKey       string
+	Timeout      time.Duration
+	//MaxRetries is left nil for the default, zero turns retries off
+	MaxRetries    *int
+	BaseBackoff   time.Duration
+	MaxBackoff    time.Duration
+	StoreRequests int
+	StoreWindow   time.Duration
+}
+
+//ConfigFromEnv reads the Steam configuration from the environment:
+//STEAMKEY, STEAM_API_URL, STEAM_STORE_URL, STEAM_TIMEOUT (duration), STEAM_MAX_RETRIES,
+//STEAM_STORE_REQUESTS and STEAM_STORE_WINDOW (duration). STEAM_MAX_RETRIES=0 turns retries off, the default is used when it is not set.
+func ConfigFromEnv() SteamClientConfig {
+	config := SteamClientConfig{
+		APIBaseURL:   os.Getenv("STEAM_API_URL"),
+		StoreBaseURL: os.Getenv("STEAM_STORE_URL"),
+		APIKey:       os.Getenv("STEAMKEY"),
+	}
+	if timeout, err := time.ParseDuration(os.Getenv("STEAM_TIMEOUT")); err == nil {
+		config.Timeout = timeout
+	}
+	if retries, err := strconv.Atoi(os.Getenv("STEAM_MAX_RETRIES")); err == nil {
+		config.MaxRetries = &retries
+	}
+	if requests, err := strconv.Atoi(os.Getenv("STEAM_STORE_REQUESTS")); err == nil {
+		config.StoreRequests = requests
+	}
+	if window, err := time.ParseDuration(os.Getenv("STEAM_STORE_WINDOW")); err == nil {
+		config.StoreWindow = window
+	}
+	return config
+}
+
+//SteamClient does the HTTP calls to the Steam web and store APIs.
+//Calls are retried with an exponential backoff when Steam is throttling us (429) or failing (5xx),
+//and store calls go through a token bucket so we stay under Steam's limits.
+type SteamClient struct {
+	httpClient   *http.Client
+	apiBaseURL   string
+	storeBaseURL string
+	apiKey       string
+	maxRetries   int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	storeLimiter *TokenBucket
+}
+
+//Constructor
+func NewSteamClient(config SteamClientConfig) *SteamClient {
+	if config.APIBaseURL == "" {
+		config.APIBaseURL = DefaultAPIBaseURL
+	}
+	if config.StoreBaseURL == "" {
+		config.StoreBaseURL = DefaultStoreBaseURL
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	maxRetries := defaultMaxRetries
+	if config.MaxRetries != nil {
+		maxRetries = *config.MaxRetries
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaultBaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.StoreRequests <= 0 {
+		config.StoreRequests = defaultStoreRequests
+	}
+	if config.StoreWindow <= 0 {
+		config.StoreWindow = defaultStoreWindow
+	}
+
+	return &SteamClient{
+		httpClient:   &http.Client{Timeout: config.Timeout},
+		apiBaseURL:   strings.TrimSuffix(config.APIBaseURL, "/"),
+		storeBaseURL: strings.TrimSuffix(config.StoreBaseURL, "/"),
+		apiKey:       config.APIKey,
+		maxRetries:   maxRetries,
+		baseBackoff:  config.BaseBackoff,
+		maxBackoff:   config.MaxBackoff,
+		storeLimiter: NewTokenBucket(config.StoreRequests, config.StoreWindow),
+	}
+}
+
+//GetAPI calls the Steam web API (api.steampowered.com), the key is added to the query for us
+func (c *SteamClient) GetAPI(ctx context.Context, path string, query map[string]string) ([]byte, error) {
+	params := map[string]string{"key": c.apiKey}
+	for k, v := range query {
+		params[k] = v
+	}
+	return c.get(ctx, c.apiBaseURL+path, params)
+}
+
+//GetStore calls the Steam store API (store.steampowered.com), waiting for the rate limiter if needed
+func (c *SteamClient) GetStore(ctx context.Context, path string, query map[string]string) ([]byte, error) {
+	if err := c.storeLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.get(ctx, c.storeBaseURL+path, query)
+}
+
+func (c *SteamClient) get(ctx context.Context, requestURL string, query map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	for k, v := range query {
+		q.Set(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.backoff(attempt, lastErr)); err != nil {
+				return nil, err
+			}
+		}
+
+		body, err := c.do(req)
+		if err == nil {
+			return body, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		lastErr = err
+		if !isRetryable(err) {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+//retryAfterError carries the delay Steam asked us to wait before retrying
+type retryAfterError struct {
+	*ErrSteamStatus
+	retryAfter time.Duration
+}
+
+func (c *SteamClient) do(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		//the error quotes the URL, which holds the API key, and ends up in the sync jobs shown to users
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return nil, &url.Error{Op: urlErr.Op, URL: redactedURL(req.URL), Err: urlErr.Err}
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, nil
+	}
+	statusErr := &ErrSteamStatus{StatusCode: resp.StatusCode, URL: req.URL.Path}
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+		return nil, &retryAfterError{ErrSteamStatus: statusErr, retryAfter: time.Duration(seconds) * time.Second}
+	}
+	return nil, statusErr
+}
+
+//redactedURL is u without the value of its key parameter
+func redactedURL(u *url.URL) string {
+	redacted := *u
+	q := redacted.Query()
+	if q.Get("key") != "" {
+		q.Set("key", "REDACTED")
+		redacted.RawQuery = q.Encode()
+	}
+	return redacted.String()
+}
+
+//backoff doubles the wait on every attempt, unless Steam told us how long to wait
+func (c *SteamClient) backoff(attempt int, lastErr error) time.Duration {
+	var retryAfter *retryAfterError
+	if errors.As(lastErr, &retryAfter) {
+		if retryAfter.retryAfter > c.maxBackoff {
+			return c.maxBackoff
+		}
+		return retryAfter.retryAfter
+	}
+
+	wait := c.baseBackoff << uint(attempt-1)
+	if wait <= 0 || wait > c.maxBackoff {
+		return c.maxBackoff
+	}
+	return wait
+}
+
+//only throttling and server errors are worth retrying, along with network errors
+func isRetryable(err error) bool {
+	var statusErr *ErrSteamStatus
+	var retryAfter *retryAfterError
+	if errors.As(err, &retryAfter) {
+		statusErr = retryAfter.ErrSteamStatus
+	} else if !errors.As(err, &statusErr) {
+		return true
+	}
+	return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -2,13 +2,12 @@ package Steam
 
 import (
 	"GamesAPI/src/domain"
+	"context"
 	"encoding/json"
 	"errors"
-	"io/ioutil"
-	"net/http"
-	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,30 +15,53 @@ var (
 	ExternalSteamUserService ExternalSteamUserServiceInterface = &externalSteamUserService{}
 )
 
-type externalSteamUserService struct{}
+//externalSteamUserService talks to Steam through its client.
+//When none was given, the client is built from the environment on first use.
+type externalSteamUserService struct {
+	client     *SteamClient
+	clientOnce sync.Once
+}
 
 type ExternalSteamUserServiceInterface interface {
-	GetUserID(personalURL string) (string, error)
-	GetUserOwnedGames(userID string) ([]string, error)
-	GetGameInfo(gameID string) (domain.Game, error)
+	GetUserID(ctx context.Context, personalURL string) (string, error)
+	GetUserOwnedGames(ctx context.Context, userID string) ([]OwnedGame, error)
+	GetGameInfo(ctx context.Context, gameID string) (domain.Game, error)
+}
+
+//Constructor - lets us point the service at another Steam (a local fake server in tests for instance)
+func NewExternalSteamUserService(client *SteamClient) ExternalSteamUserServiceInterface {
+	return &externalSteamUserService{client: client}
 }
 
-func getFromSteam(requestURL string) ([]byte, error) {
-	resp, err := http.Get(requestURL)
-	bodyBytes, _ := ioutil.ReadAll(resp.Body)
-	err = resp.Body.Close()
+//OwnedGame is a game found in a Steam user's library, along with its playtime (in minutes)
+type OwnedGame struct {
+	AppId                  string
+	PlaytimeForever        int
+	PlaytimeWindowsForever int
+	PlaytimeMacForever     int
+	PlaytimeLinuxForever   int
+}
 
-	return bodyBytes, err
+func (e *externalSteamUserService) steam() *SteamClient {
+	e.clientOnce.Do(func() {
+		if e.client == nil {
+			e.client = NewSteamClient(ConfigFromEnv())
+		}
+	})
+	return e.client
 }
 
-func (e externalSteamUserService) GetUserID(personalURL string) (string, error) {
-	key := os.Getenv("STEAMKEY")
-	steamID, err := getFromSteam("http://api.steampowered.com/ISteamUser/ResolveVanityURL/v0001/?key=" + key + "&vanityurl=" + personalURL)
+func (e *externalSteamUserService) GetUserID(ctx context.Context, personalURL string) (string, error) {
+	steamID, err := e.steam().GetAPI(ctx, "/ISteamUser/ResolveVanityURL/v0001/", map[string]string{
+		"vanityurl": personalURL,
+	})
 	if err != nil {
 		return "", err
 	}
 	var userinfo basicUserSteamType
-	json.Unmarshal(steamID, &userinfo)
+	if err := json.Unmarshal(steamID, &userinfo); err != nil {
+		return "", err
+	}
 
 	if userinfo.Response.Success == 1 {
 		return userinfo.Response.Steamid, nil
@@ -48,26 +70,36 @@ func (e externalSteamUserService) GetUserID(personalURL string) (string, error)
 	}
 }
 
-func (e externalSteamUserService) GetUserOwnedGames(userID string) ([]string, error){
-	key := os.Getenv("STEAMKEY")
-	ownedGamesInfo, err := getFromSteam("http://api.steampowered.com/IPlayerService/GetOwnedGames/v0001/?key=" + key + "&steamid=" + userID + "&format=json")
+func (e *externalSteamUserService) GetUserOwnedGames(ctx context.Context, userID string) ([]OwnedGame, error) {
+	ownedGamesInfo, err := e.steam().GetAPI(ctx, "/IPlayerService/GetOwnedGames/v0001/", map[string]string{
+		"steamid": userID,
+		"format":  "json",
+	})
 	if err != nil {
-		return []string{""}, err
+		return nil, err
 	}
 
 	var userOwnedGames ownedGamesSteamType
-	json.Unmarshal(ownedGamesInfo, &userOwnedGames)
+	if err := json.Unmarshal(ownedGamesInfo, &userOwnedGames); err != nil {
+		return nil, err
+	}
 
-	var usableSteamGameIDs []string
-	for _, games := range userOwnedGames.Response.Games {
-		usableSteamGameIDs = append(usableSteamGameIDs, strconv.Itoa(games.Appid))
+	var ownedGames []OwnedGame
+	for _, game := range userOwnedGames.Response.Games {
+		ownedGames = append(ownedGames, OwnedGame{
+			AppId:                  strconv.Itoa(game.Appid),
+			PlaytimeForever:        game.Playtime_forever,
+			PlaytimeWindowsForever: game.Playtime_windows_forever,
+			PlaytimeMacForever:     game.Playtime_mac_forever,
+			PlaytimeLinuxForever:   game.Playtime_linux_forever,
+		})
 	}
 
-	return usableSteamGameIDs, nil
+	return ownedGames, nil
 }
 
-func (e externalSteamUserService) GetGameInfo(gameID string) (domain.Game, error){
-	gameInfo, err := getFromSteam("https://store.steampowered.com/api/appdetails?appids="+gameID)
+func (e *externalSteamUserService) GetGameInfo(ctx context.Context, gameID string) (domain.Game, error) {
+	gameInfo, err := e.steam().GetStore(ctx, "/api/appdetails", map[string]string{"appids": gameID})
 	if err != nil {
 		return domain.Game{}, err
 	}
@@ -0,0 +1,59 @@
+package Steam
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//TokenBucket lets through at most `capacity` calls per `window`, refilling continuously.
+//Callers wait for a token instead of being rejected.
+type TokenBucket struct {
+	mutex    sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 //tokens per second
+	last     time.Time
+}
+
+//Constructor - the bucket starts full
+func NewTokenBucket(capacity int, window time.Duration) *TokenBucket {
+	return &TokenBucket{
+		capacity: float64(capacity),
+		tokens:   float64(capacity),
+		rate:     float64(capacity) / window.Seconds(),
+		last:     time.Now(),
+	}
+}
+
+//Wait blocks until a token is available or the context is done
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		wait := b.reserve()
+		if wait == 0 {
+			return nil
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+//reserve takes a token if there is one, otherwise returns how long until the next one
+func (b *TokenBucket) reserve() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/middleware"
+	"GamesAPI/src/router"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"os"
+	"strings"
+)
+
+const commandsUsage = `usage: gamesapi [command]
+
+Without a command, the API is served.
+
+commands:
+  rbac lint [file]	checks the role-based access policy against the routes, file defaults to RBAC_FILEPATH
+`
+
+//RunCommand runs the command given on the command line and returns the exit code
+func RunCommand(args []string, out io.Writer) int {
+	if len(args) >= 2 && args[0] == "rbac" && args[1] == "lint" {
+		return RbacLintCommand(args[2:], out)
+	}
+	_, _ = fmt.Fprint(out, commandsUsage)
+	return 2
+}
+
+//RbacLintCommand prints the issues of the policy. It exits with 1 when there are errors, warnings alone pass.
+func RbacLintCommand(args []string, out io.Writer) int {
+	path := os.Getenv("RBAC_FILEPATH")
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" {
+		_, _ = fmt.Fprint(out, commandsUsage)
+		return 2
+	}
+
+	issues, err := lintRbacPolicy(path, exposedRoutes())
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "%s: %s\n", path, err.Error())
+		return 1
+	}
+	errorCount := 0
+	for _, issue := range issues {
+		if issue.Severity == domain.RbacIssueError {
+			errorCount++
+		}
+		_, _ = fmt.Fprintf(out, "%-8s %s\n", issue.Severity, issue.String())
+	}
+	_, _ = fmt.Fprintf(out, "%s: %d errors, %d warnings\n", path, errorCount, len(issues)-errorCount)
+	if errorCount > 0 {
+		return 1
+	}
+	return 0
+}
+
+//exposedRoutes registers the routes on an engine of its own, nothing is served
+func exposedRoutes() gin.RoutesInfo {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	router.InitAllRoutes(r)
+	return r.Routes()
+}
+
+func lintRbacPolicy(path string, routes gin.RoutesInfo) ([]domain.RbacIssue, error) {
+	return domain.LintRbacFile(path, middleware.RbacSurface(routes))
+}
+
+//checkRbacPolicy refuses to start with a policy that has errors, warnings are only logged.
+//Name is where content was read from, the policy file or the database.
+func checkRbacPolicy(name string, content []byte, routes gin.RoutesInfo) {
+	issues, err := domain.LintRbacBytes(content, middleware.RbacSurface(routes))
+	if err != nil {
+		panic(fmt.Errorf("rbac policy %s could not be loaded %s", name, err.Error()))
+	}
+	var errors []string
+	for _, issue := range issues {
+		if issue.Severity == domain.RbacIssueError {
+			errors = append(errors, issue.String())
+			continue
+		}
+		fmt.Printf("rbac policy %s warning - %s\n", name, issue.String())
+	}
+	if len(errors) > 0 {
+		panic(fmt.Errorf("rbac policy %s has errors, run gamesapi rbac lint:\n%s", name, strings.Join(errors, "\n")))
+	}
+}
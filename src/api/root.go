@@ -3,14 +3,27 @@ package api
 import (
 	"GamesAPI/src/database"
 	"GamesAPI/src/domain"
+	"GamesAPI/src/middleware"
 	"GamesAPI/src/router"
 	"GamesAPI/src/services"
 	"GamesAPI/src/utils/authUtils"
+	"GamesAPI/src/utils/errorUtils"
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 )
 
+const defaultSyncWorkers = 4
+
+const defaultShutdownTimeout = 10 * time.Second
+
 func Bootstrap(r *gin.Engine) {
 	var dbInstance, dbErr = database.Setup(domain.InitRepositories)
 	if dbErr != nil {
@@ -51,12 +64,182 @@ func Bootstrap(r *gin.Engine) {
 	}
 	_, _ = fmt.Printf("This is the bypass session key : %s\n", sessionKey)
 	_, _ = fmt.Printf("This is the master email : %s\n", masterEmail)
+	if os.Getenv("DEV_API_KEY") == "true" {
+		apiKey, existing, apiKeyErr := issueDevApiKey()
+		switch {
+		case apiKeyErr != nil:
+			_, _ = fmt.Printf("The dev API key could not be issued : %s\n", apiKeyErr.Message())
+		case existing != nil:
+			_, _ = fmt.Printf("The master already has the API key %s, revoke it through /api-keys to get a new one on the next boot\n", existing.Prefix)
+		default:
+			_, _ = fmt.Printf("This is the dev API key : %s\n", apiKey)
+		}
+	}
 	//END : NOT FOR PROD
 
+	jwtService, jwtErr := services.NewJwtServiceFromEnv()
+	if jwtErr != nil {
+		panic(fmt.Errorf("jwt keys could not be loaded %s", jwtErr.Error()))
+	}
+	services.JwtService = jwtService
+
+	rateLimitService, rateLimitErr := services.NewRateLimitServiceFromEnv()
+	if rateLimitErr != nil {
+		panic(fmt.Errorf("rate limits could not be loaded %s", rateLimitErr.Error()))
+	}
+	services.RateLimitService = rateLimitService
+
+	trustedProxies, proxiesErr := middleware.TrustedProxiesFromEnv()
+	if proxiesErr != nil {
+		panic(fmt.Errorf("trusted proxies could not be loaded %s", proxiesErr.Error()))
+	}
+	middleware.TrustedProxies = trustedProxies
+
+	services.LoginAttemptService = services.NewLoginAttemptService(services.LoginAttemptConfigFromEnv())
+	services.RefreshTokenService = services.NewRefreshTokenServiceFromEnv()
+	stopReaper := services.StartSessionReaper(sessionReapInterval())
+	defer stopReaper()
+
+	syncJobsService, syncErr := services.NewSyncJobsServiceFromEnv()
+	if syncErr != nil {
+		panic(fmt.Errorf("sync jobs could not be set up %s", syncErr.Error()))
+	}
+	services.SyncJobsService = syncJobsService
+	services.SyncJobsService.Start(syncWorkerCount())
+	defer services.SyncJobsService.Stop()
+
 	router.InitAllRoutes(r)
+	services.AuthorizationService = rbacAuthorizationService(r.Routes())
+	stopRbacWatch := services.AuthorizationService.Watch(rbacReloadInterval())
+	defer stopRbacWatch()
+
+	serve(r)
+}
+
+//serve answers requests until SIGINT or SIGTERM, then lets the requests being handled finish.
+//The services are stopped by the caller once it returns, a signal would otherwise kill the process without unwinding.
+func serve(r *gin.Engine) {
+	server := &http.Server{Addr: serverAddress(), Handler: r}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	select {
+	case err := <-serverErr:
+		HandleErrors(err)
+	case received := <-signals:
+		log.Printf("%s received, shutting down", received.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout())
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("requests still running after the shutdown timeout were dropped: %s", err.Error())
+	}
+}
+
+//the port to listen on, PORT like gin's Run does, 8080 by default
+func serverAddress() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ":8080"
+}
+
+//how long the requests being handled are given to finish on shutdown, configurable through SHUTDOWN_TIMEOUT
+func shutdownTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("SHUTDOWN_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		return defaultShutdownTimeout
+	}
+	return timeout
+}
+
+//issueDevApiKey hands out an API key to the master user when it has no active one, existing is that key otherwise.
+//Only called when DEV_API_KEY is true.
+//NOT FOR PROD
+func issueDevApiKey() (rawKey string, existing *domain.ApiKey, err errorUtils.EntityError) {
+	keys, err := services.ApiKeyService.GetKeys(1)
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now()
+	for i := range keys {
+		if keys[i].IsActive(now) {
+			return "", &keys[i], nil
+		}
+	}
+	rawKey, _, err = services.ApiKeyService.IssueKey(&domain.ApiKey{OwnerId: 1, Label: "dev bootstrap"})
+	if err != nil {
+		return "", nil, err
+	}
+	return rawKey, nil, nil
+}
+
+//number of background workers running Steam synchronizations, configurable through SYNC_WORKERS
+func syncWorkerCount() int {
+	workers, err := strconv.Atoi(os.Getenv("SYNC_WORKERS"))
+	if err != nil || workers < 1 {
+		return defaultSyncWorkers
+	}
+	return workers
+}
 
-	err := r.Run()
+//how often expired sessions are freed from the session store, configurable through SESSION_REAP_INTERVAL
+func sessionReapInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("SESSION_REAP_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return services.DefaultSessionReapInterval
+	}
+	return interval
+}
+
+//how often the RBAC file is checked for changes, configurable through RBAC_RELOAD_INTERVAL. SIGHUP reloads it right away.
+func rbacReloadInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("RBAC_RELOAD_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return services.DefaultRbacReloadInterval
+	}
+	return interval
+}
+
+//how the permissions of a user holding several roles are combined, configurable through RBAC_ROLE_STRATEGY.
+//A typo must not silently change who gets access, so an unknown strategy is fatal.
+func rbacRoleStrategy() domain.RoleStrategy {
+	strategy, err := domain.ParseRoleStrategy(os.Getenv("RBAC_ROLE_STRATEGY"))
 	HandleErrors(err)
+	return strategy
+}
+
+//rbacAuthorizationService reads the policy from the source chosen by RBAC_SOURCE, RBAC_FILEPATH by default.
+//The database is given the policy file as its first version, when it holds none yet.
+func rbacAuthorizationService(routes gin.RoutesInfo) services.AuthorizationServiceInterface {
+	path := os.Getenv("RBAC_FILEPATH")
+	surface := middleware.RbacSurface(routes)
+	var source services.RbacPolicySource
+	switch os.Getenv("RBAC_SOURCE") {
+	case "", services.RbacSourceFile:
+		source = services.NewRbacFileSource(path)
+	case services.RbacSourceDatabase:
+		services.RbacPolicyService = services.NewRbacPolicyService(surface)
+		if err := services.RbacPolicyService.Import(path); err != nil {
+			panic(fmt.Errorf("rbac policy %s could not be imported %s", path, err.Message()))
+		}
+		source = services.NewRbacDatabaseSource()
+	default:
+		panic(fmt.Errorf("unknown RBAC_SOURCE '%s', expected %s or %s", os.Getenv("RBAC_SOURCE"), services.RbacSourceFile, services.RbacSourceDatabase))
+	}
+
+	content, err := source.Read()
+	if err != nil {
+		panic(fmt.Errorf("rbac policy %s could not be loaded %s", source.String(), err.Error()))
+	}
+	checkRbacPolicy(source.String(), content, routes)
+	return services.NewAuthorizationServiceFromSource(source, rbacRoleStrategy(), surface)
 }
 
 func HandleErrors(err error) {
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type inputApiKey struct {
+	OwnerId   uint64     `json:"owner_id"`
+	Label     string     `json:"label"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func getApiKeyId(keyIdParam string) (uint64, errorUtils.EntityError) {
+	keyId, keyError := strconv.ParseUint(keyIdParam, 10, 64)
+	if keyError != nil {
+		return 0, errorUtils.NewBadRequestError("api key id should be a number")
+	}
+	return keyId, nil
+}
+
+//CreateApiKey issues a key for a client. The full key is only ever sent in this response.
+func CreateApiKey(c *gin.Context) {
+	input := inputApiKey{}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		errorUtils.IsEntityError(c, errorUtils.NewUnprocessableEntityError("invalid json body"))
+		return
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		errorUtils.IsEntityError(c, errorUtils.NewUnprocessableEntityError("expires_at should be in the future"))
+		return
+	}
+
+	rawKey, key, err := services.ApiKeyService.IssueKey(&domain.ApiKey{
+		OwnerId:   input.OwnerId,
+		Label:     input.Label,
+		Scopes:    input.Scopes,
+		ExpiresAt: input.ExpiresAt,
+	})
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"key": rawKey, "api_key": key})
+}
+
+//GetApiKeys lists the keys, optionally only those of ?owner_id
+func GetApiKeys(c *gin.Context) {
+	var ownerId uint64
+	if ownerParam := c.Query("owner_id"); ownerParam != "" {
+		var ownerErr errorUtils.EntityError
+		ownerId, ownerErr = getUserId(ownerParam)
+		if errorUtils.IsEntityError(c, ownerErr) {
+			return
+		}
+	}
+
+	keys, err := services.ApiKeyService.GetKeys(ownerId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func RevokeApiKey(c *gin.Context) {
+	keyId, keyErr := getApiKeyId(c.Param("id"))
+	if errorUtils.IsEntityError(c, keyErr) {
+		return
+	}
+
+	if err := services.ApiKeyService.RevokeKey(keyId); errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+}
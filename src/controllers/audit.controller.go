@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//recordAudit appends the change the request made to the audit log. The change is made by then, so a failure is only logged.
+func recordAudit(c *gin.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) {
+	err := services.AuditService.Record(c.Request.Context(), resource, action, entityId, before, after)
+	if err != nil {
+		log.Printf("could not audit %s %s %d: %s", resource, action, entityId, err.Message())
+	}
+}
+
+func getAuditQuery(c *gin.Context) (*domain.AuditQuery, errorUtils.EntityError) {
+	query := domain.NewAuditQuery()
+	numbers := []struct {
+		name  string
+		value *uint64
+	}{
+		{"page", &query.Page},
+		{"page_size", &query.PageSize},
+		{"actor_id", &query.ActorId},
+		{"api_key_id", &query.ApiKeyId},
+		{"entity_id", &query.EntityId},
+	}
+	for _, number := range numbers {
+		raw := c.Query(number.name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, errorUtils.NewBadRequestError(number.name + " should be a number")
+		}
+		*number.value = value
+	}
+	dates := []struct {
+		name  string
+		value **time.Time
+	}{
+		{"since", &query.Since},
+		{"until", &query.Until},
+	}
+	for _, date := range dates {
+		raw := c.Query(date.name)
+		if raw == "" {
+			continue
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, errorUtils.NewBadRequestError(date.name + " should be formatted as RFC 3339, e.g. 2026-10-18T12:00:00Z")
+		}
+		*date.value = &value
+	}
+	query.Resource = c.Query("resource")
+	query.Action = c.Query("action")
+	query.RequestId = c.Query("request_id")
+	return query, nil
+}
+
+//GetAudit lists the changes made through the API, most recent first
+func GetAudit(c *gin.Context) {
+	query, err := getAuditQuery(c)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	page, err := services.AuditService.GetEntries(query)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+//ExportAudit streams the entries matching the same filters as GetAudit as JSON lines, ignoring the pages
+func ExportAudit(c *gin.Context) {
+	query, err := getAuditQuery(c)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="audit.jsonl"`)
+	c.Status(http.StatusOK)
+	err = services.AuditService.Export(query, c.Writer)
+	if err == nil {
+		return
+	}
+	//the status is sent with the first line, a failure past it can only cut the export short
+	if c.Writer.Written() {
+		log.Printf("audit export interrupted: %s", err.Message())
+		return
+	}
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+	errorUtils.IsEntityError(c, err)
+}
@@ -7,8 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"time"
 )
 
+const gameQueryDateFormat = "2006-01-02"
+
 func getGameId(gameIdParam string) (uint64, errorUtils.EntityError) {
 	gameId, gameError := strconv.ParseUint(gameIdParam, 10, 64)
 	if gameError != nil {
@@ -31,13 +34,80 @@ func GetGame(c *gin.Context) {
 	c.JSON(http.StatusOK, game)
 }
 
+func getGameQuery(c *gin.Context) (*domain.GameQuery, errorUtils.EntityError) {
+	query := domain.NewGameQuery()
+	if page := c.Query("page"); page != "" {
+		p, err := strconv.ParseUint(page, 10, 64)
+		if err != nil {
+			return nil, errorUtils.NewBadRequestError("page should be a number")
+		}
+		query.Page = p
+	}
+	if pageSize := c.Query("page_size"); pageSize != "" {
+		p, err := strconv.ParseUint(pageSize, 10, 64)
+		if err != nil {
+			return nil, errorUtils.NewBadRequestError("page_size should be a number")
+		}
+		query.PageSize = p
+	}
+	if releasedAfter := c.Query("released_after"); releasedAfter != "" {
+		date, err := time.Parse(gameQueryDateFormat, releasedAfter)
+		if err != nil {
+			return nil, errorUtils.NewBadRequestError("released_after should be formatted as YYYY-MM-DD")
+		}
+		query.ReleasedAfter = &date
+	}
+	if releasedBefore := c.Query("released_before"); releasedBefore != "" {
+		date, err := time.Parse(gameQueryDateFormat, releasedBefore)
+		if err != nil {
+			return nil, errorUtils.NewBadRequestError("released_before should be formatted as YYYY-MM-DD")
+		}
+		query.ReleasedBefore = &date
+	}
+	if sort := c.Query("sort"); sort != "" {
+		query.Sort = sort
+	}
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+		query.Descending = false
+	case "desc":
+		query.Descending = true
+	default:
+		return nil, errorUtils.NewBadRequestError("order should be either asc or desc")
+	}
+	query.Developer = c.Query("developer")
+	query.Publisher = c.Query("publisher")
+	query.SteamId = c.Query("steam_id")
+	return query, nil
+}
+
+//builds the link to another page of the same listing, keeping every other query parameter as is
+func gamePageLink(c *gin.Context, page uint64) string {
+	link := *c.Request.URL
+	q := link.Query()
+	q.Set("page", strconv.FormatUint(page, 10))
+	link.RawQuery = q.Encode()
+	return link.RequestURI()
+}
+
 func GetAllGames(c *gin.Context) {
-	games, err := services.GamesService.GetAllGames()
+	query, queryErr := getGameQuery(c)
+	if errorUtils.IsEntityError(c, queryErr) {
+		return
+	}
+
+	page, err := services.GamesService.GetAllGames(query)
 	if errorUtils.IsEntityError(c, err) {
 		return
 	}
 
-	c.JSON(http.StatusOK, games)
+	if page.HasNext() {
+		page.Next = gamePageLink(c, page.Page+1)
+	}
+	if page.HasPrev() {
+		page.Prev = gamePageLink(c, page.Page-1)
+	}
+	c.JSON(http.StatusOK, page)
 }
 
 func CreateGame(c *gin.Context) {
@@ -52,6 +122,7 @@ func CreateGame(c *gin.Context) {
 	if errorUtils.IsEntityError(c, err) {
 		return
 	}
+	recordAudit(c, "game", "create", g.ID, nil, g)
 
 	c.JSON(http.StatusCreated, g)
 }
@@ -69,10 +140,13 @@ func UpdateGame(c *gin.Context) {
 		return
 	}
 	game.ID = gameId
+	//a game that does not exist is reported by the update
+	before, _ := services.GamesService.GetGame(gameId)
 	g, err := services.GamesService.UpdateGame(&game)
 	if errorUtils.IsEntityError(c, err) {
 		return
 	}
+	recordAudit(c, "game", "update", g.ID, before, g)
 
 	c.JSON(http.StatusOK, g)
 }
@@ -82,8 +156,10 @@ func DeleteGame(c *gin.Context) {
 	if errorUtils.IsEntityError(c, err) {
 		return
 	}
+	before, _ := services.GamesService.GetGame(gameId)
 	if err := services.GamesService.DeleteGame(gameId); errorUtils.IsEntityError(c, err) {
 		return
 	}
+	recordAudit(c, "game", "delete", gameId, before, nil)
 	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
@@ -0,0 +1,13 @@
+package controllers
+
+import (
+	"GamesAPI/src/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+//JWKS exposes the public keys verifying our JWTs, so other services can check them on their own
+func JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, services.JwtService.JWKS())
+}
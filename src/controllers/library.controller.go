@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func getLibrarySource(c *gin.Context) string {
+	return c.DefaultQuery("source", domain.UserGameSourceSteam)
+}
+
+func GetUserLibrary(c *gin.Context) {
+	userId, userErr := getUserId(c.Param("id"))
+	if errorUtils.IsEntityError(c, userErr) {
+		return
+	}
+
+	library, err := services.UserGamesService.GetLibrary(userId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, library)
+}
+
+func GetUserLibraryGame(c *gin.Context) {
+	userId, userErr := getUserId(c.Param("id"))
+	if errorUtils.IsEntityError(c, userErr) {
+		return
+	}
+	gameId, gameErr := getGameId(c.Param("gameId"))
+	if errorUtils.IsEntityError(c, gameErr) {
+		return
+	}
+
+	entry, err := services.UserGamesService.GetLibraryEntry(userId, gameId, getLibrarySource(c))
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func DeleteUserLibraryGame(c *gin.Context) {
+	userId, userErr := getUserId(c.Param("id"))
+	if errorUtils.IsEntityError(c, userErr) {
+		return
+	}
+	gameId, gameErr := getGameId(c.Param("gameId"))
+	if errorUtils.IsEntityError(c, gameErr) {
+		return
+	}
+
+	if err := services.UserGamesService.DeleteLibraryEntry(userId, gameId, getLibrarySource(c)); errorUtils.IsEntityError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
@@ -34,7 +34,7 @@ func LinkSteamUser(c *gin.Context) {
 		userSteamId = strings.Split(steamUrl, "/")[4]
 	}
 	if strings.Contains(steamUrl, "/id/") {
-		userSteamId, err2 = Steam.ExternalSteamUserService.GetUserID(strings.Split(steamUrl, "/")[4])
+		userSteamId, err2 = Steam.ExternalSteamUserService.GetUserID(c.Request.Context(), strings.Split(steamUrl, "/")[4])
 
 		if err2 != nil {
 			ErrorMessageTypeCode(c, 500, "Could not get the user Steam id from Steam Url")
@@ -47,12 +47,14 @@ func LinkSteamUser(c *gin.Context) {
 		ErrorMessageTypeCode(c, errget.Status(), errget.Message())
 		return
 	}
+	before := *user
 	user.SteamUserId = userSteamId
-	_, errorUpdate := services.UsersService.UpdateUser(user)
+	updated, errorUpdate := services.UsersService.UpdateUser(user, "steam_user_id")
 	if errorUpdate != nil {
 		ErrorMessageTypeCode(c, errorUpdate.Status(), errorUpdate.Message())
 		return
 	}
+	recordAudit(c, "user", "link", updated.ID, &before, updated)
 	c.JSON(200, gin.H{"Message": "Success"})
 }
 
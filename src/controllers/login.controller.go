@@ -1,12 +1,13 @@
 package controllers
 
 import (
-	"GamesAPI/src/domain"
 	"GamesAPI/src/services"
+	"GamesAPI/src/utils/authUtils"
+	"GamesAPI/src/utils/errorUtils"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 )
 
@@ -14,77 +15,100 @@ func abortWithError(c *gin.Context, code int, message string) {
 	c.AbortWithStatusJSON(code, gin.H{"Error": message})
 }
 
+type inputLogin struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+//LoginController trades an email and a password for an access token and a refresh token.
+//Failed attempts are counted per account and per IP, too many of them lock the login out for a while.
 func LoginController(c *gin.Context) {
-	authenticateHeader := strings.Trim(c.Request.Header.Get("Authorization"), " ")
-	parts := strings.Split(authenticateHeader, ";")
-	if authenticateHeader == "" || len(parts) != 2 {
-		abortWithError(c, http.StatusBadRequest, "Www-Authenticate header was not set properly")
+	input := inputLogin{}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		abortWithError(c, http.StatusBadRequest, "email and password are required")
 		return
 	}
 
-	email, password := parts[0], []byte(parts[1])
-
-	//try to find user with email
-
-	users, err := services.UsersService.GetAllUsers()
-
+	ip := c.ClientIP()
+	user, err := services.AuthenticationService.Authenticate(input.Email, []byte(input.Password), ip)
 	if err != nil {
+		if err.Status() == http.StatusTooManyRequests {
+			wait := services.LoginAttemptService.LockedFor(input.Email, ip, time.Now())
+			c.Header("Retry-After", strconv.Itoa(services.RetryAfterSeconds(wait)))
+		}
 		abortWithError(c, err.Status(), err.Message())
 		return
 	}
 
-	var potentialUser *domain.User = nil
-
-	for _, user := range users {
-		if user.Email == email {
-			potentialUser = &user
-			break
-		}
-	}
-
-	if potentialUser == nil {
-		//no user has been found for email
-		abortWithError(c, http.StatusUnauthorized, "Bad username/password combination")
+	//the access token is short-lived (ACCESS_TOKEN_LIFETIME), the refresh token is traded for a new pair on /auth/refresh
+	tokens, tokenErr := services.RefreshTokenService.IssueTokens(user.ID)
+	if tokenErr != nil {
+		abortWithError(c, tokenErr.Status(), tokenErr.Message())
 		return
 	}
 
-	validPasswordHash := potentialUser.PasswordHash
+	c.Header("Authorization", tokens.AccessToken)
+	c.Header("Refresh-Token", tokens.RefreshToken)
+
+	c.JSON(http.StatusOK, gin.H{
+		"Message": fmt.Sprintf("User with email '%s' ID '%v' Successfully authenticated. "+
+			"Session token was sent in response's 'Authorization' header ", user.Email, user.ID),
+		"access_token":       tokens.AccessToken,
+		"access_expires_at":  tokens.AccessExpiresAt,
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+	})
+}
 
-	isPasswordValid, authErr := services.AuthenticationService.ValidatePassword(password, validPasswordHash)
+type inputRefresh struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
 
-	if authErr != nil || !isPasswordValid {
-		//password doesn't match
-		abortWithError(c, http.StatusUnauthorized, "Bad username/password combination")
+//RefreshController trades a refresh token for a new access token and a new refresh token.
+//The refresh token sent can never be used again.
+func RefreshController(c *gin.Context) {
+	input := inputRefresh{}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		abortWithError(c, http.StatusBadRequest, "refresh_token is required")
 		return
 	}
 
-	//To be documented : a typical session lasts 10 minutes.
-	//					 if a refresh is issued after the 10-minute mark, the user will have to authenticate again.
-	expireAt := time.Now().Add(time.Minute * 10)
-	token, tokenErr := services.UserSessionService.GenerateSessionToken(potentialUser.ID, expireAt)
-
-	if tokenErr != nil {
-		abortWithError(c, http.StatusInternalServerError, fmt.Sprintf("Token couldn't be generated by server - %s", tokenErr.Error()))
+	tokens, err := services.RefreshTokenService.Refresh(input.RefreshToken)
+	if err != nil {
+		abortWithError(c, err.Status(), err.Message())
 		return
 	}
 
-	session := &domain.UserSession{
-		Token:     token,
-		UserId:    potentialUser.ID,
-		ExpiresAt: expireAt.UnixNano(),
-	}
-
-	_, createSessionError := services.UserSessionService.CreateSession(session)
+	c.Header("Authorization", tokens.AccessToken)
+	c.Header("Refresh-Token", tokens.RefreshToken)
+	c.JSON(http.StatusOK, tokens)
+}
 
-	//TODO: delete older session if present, to prevent same user from having many session tokens at a time.
+type inputLogout struct {
+	RefreshToken string `json:"refresh_token"`
+}
 
-	if createSessionError != nil {
-		abortWithError(c, createSessionError.Status(), createSessionError.Message())
+//LogoutController ends the caller's session along with its refresh token.
+//Sending the refresh token is optional with session tokens, but it is the only way to end a login made with a JWT.
+func LogoutController(c *gin.Context) {
+	userId, userErr := getContextUserId(c)
+	if errorUtils.IsEntityError(c, userErr) {
 		return
 	}
 
-	c.Header("Authorization", token)
+	input := inputLogout{}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			abortWithError(c, http.StatusBadRequest, "body should be a json object with an optional refresh_token")
+			return
+		}
+	}
+
+	accessToken := authUtils.BearerToken(c.Request.Header.Get("Authorization"))
+	if err := services.RefreshTokenService.Logout(userId, accessToken, input.RefreshToken); err != nil {
+		abortWithError(c, err.Status(), err.Message())
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"Message": fmt.Sprintf("User with email '%s' ID '%v' Successfully authenticated. "+
-		"Session token was sent in response's 'Authorization' header ", potentialUser.Email, potentialUser.ID)})
+	c.JSON(http.StatusOK, gin.H{"status": "logged out"})
 }
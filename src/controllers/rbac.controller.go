@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/middleware"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type inputRbacExplain struct {
+	UserId  uint64            `json:"user_id"`
+	Roles   []string          `json:"roles"`
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+func getRbacVersion(versionParam string) (uint64, errorUtils.EntityError) {
+	version, versionErr := strconv.ParseUint(versionParam, 10, 64)
+	if versionErr != nil {
+		return 0, errorUtils.NewBadRequestError("policy version should be a number")
+	}
+	return version, nil
+}
+
+//GetRbacPolicy tells which version of the policy is enforced, and why the last reload failed if it did
+func GetRbacPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, services.AuthorizationService.PolicyInfo())
+}
+
+//GetRbacRoles lists the stored roles, as declared: inherited permissions are not repeated
+func GetRbacRoles(c *gin.Context) {
+	policy, err := services.RbacPolicyService.GetPolicy()
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func GetRbacRole(c *gin.Context) {
+	policy, err := services.RbacPolicyService.GetPolicy()
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+	role, exists := policy[c.Param("role")]
+	if !exists {
+		errorUtils.IsEntityError(c, errorUtils.NewNotFoundError(fmt.Sprintf("role %s does not exist", c.Param("role"))))
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+}
+
+//PutRbacRole creates the role, or replaces all of it
+func PutRbacRole(c *gin.Context) {
+	authorId, authorErr := getContextUserId(c)
+	if errorUtils.IsEntityError(c, authorErr) {
+		return
+	}
+	role := domain.RbacPolicyRole{}
+	if err := c.ShouldBindJSON(&role); err != nil {
+		errorUtils.IsEntityError(c, errorUtils.NewUnprocessableEntityError("invalid json body"))
+		return
+	}
+
+	update, err := services.RbacPolicyService.PutRole(c.Param("role"), role, authorId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, update)
+}
+
+func DeleteRbacRole(c *gin.Context) {
+	authorId, authorErr := getContextUserId(c)
+	if errorUtils.IsEntityError(c, authorErr) {
+		return
+	}
+
+	update, err := services.RbacPolicyService.DeleteRole(c.Param("role"), authorId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, update)
+}
+
+//PutRbacPermission creates or replaces what the role may do on one action of a resource, * standing for any
+func PutRbacPermission(c *gin.Context) {
+	authorId, authorErr := getContextUserId(c)
+	if errorUtils.IsEntityError(c, authorErr) {
+		return
+	}
+	permission := domain.Permission{}
+	if err := c.ShouldBindJSON(&permission); err != nil {
+		errorUtils.IsEntityError(c, errorUtils.NewUnprocessableEntityError("invalid json body"))
+		return
+	}
+
+	update, err := services.RbacPolicyService.PutPermission(c.Param("role"), c.Param("resource"), c.Param("action"), permission, authorId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, update)
+}
+
+func DeleteRbacPermission(c *gin.Context) {
+	authorId, authorErr := getContextUserId(c)
+	if errorUtils.IsEntityError(c, authorErr) {
+		return
+	}
+
+	update, err := services.RbacPolicyService.DeletePermission(c.Param("role"), c.Param("resource"), c.Param("action"), authorId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, update)
+}
+
+//GetRbacVersions lists the stored versions of the policy, latest first, without their content
+func GetRbacVersions(c *gin.Context) {
+	versions, err := services.RbacPolicyService.GetVersions()
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+//GetRbacVersion returns a version of the policy along with its YAML snapshot
+func GetRbacVersion(c *gin.Context) {
+	version, versionErr := getRbacVersion(c.Param("version"))
+	if errorUtils.IsEntityError(c, versionErr) {
+		return
+	}
+
+	snapshot, err := services.RbacPolicyService.GetVersion(version)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+//RollbackRbacPolicy stores the snapshot of a version as the latest version, history is never rewritten
+func RollbackRbacPolicy(c *gin.Context) {
+	authorId, authorErr := getContextUserId(c)
+	if errorUtils.IsEntityError(c, authorErr) {
+		return
+	}
+	version, versionErr := getRbacVersion(c.Param("version"))
+	if errorUtils.IsEntityError(c, versionErr) {
+		return
+	}
+
+	update, err := services.RbacPolicyService.Rollback(version, authorId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, update)
+}
+
+//ExplainRbac tells how a request would be authorized, rule by rule, without serving it
+func ExplainRbac(c *gin.Context) {
+	input := inputRbacExplain{}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		errorUtils.IsEntityError(c, errorUtils.NewUnprocessableEntityError("invalid json body"))
+		return
+	}
+	if input.Method == "" || input.Path == "" {
+		errorUtils.IsEntityError(c, errorUtils.NewUnprocessableEntityError("method and path are required"))
+		return
+	}
+	if input.UserId == 0 && len(input.Roles) == 0 {
+		errorUtils.IsEntityError(c, errorUtils.NewUnprocessableEntityError("a user_id or roles are required"))
+		return
+	}
+	headers := http.Header{}
+	for name, value := range input.Headers {
+		headers.Set(name, value)
+	}
+
+	trace, err := middleware.ExplainAuthorization(middleware.RbacExplainRequest{
+		UserId:  input.UserId,
+		Roles:   input.Roles,
+		Method:  input.Method,
+		Path:    input.Path,
+		Headers: headers,
+		Body:    input.Body,
+	})
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, trace)
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type inputUserRole struct {
+	Name string `json:"name"`
+}
+
+func getUserRoleId(roleIdParam string) (uint64, errorUtils.EntityError) {
+	roleId, roleError := strconv.ParseUint(roleIdParam, 10, 64)
+	if roleError != nil {
+		return 0, errorUtils.NewBadRequestError("role id should be a number")
+	}
+	return roleId, nil
+}
+
+//GetRoles lists the roles given to users, optionally only those named ?name
+func GetRoles(c *gin.Context) {
+	var roles []domain.UserRole
+	var err errorUtils.EntityError
+	if name := c.Query("name"); name != "" {
+		roles, err = services.UserRoleService.GetRolesByRoleName(name)
+	} else {
+		roles, err = services.UserRoleService.GetAllRoles()
+	}
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+//UpdateRole gives the role another name of the RBAC policy
+func UpdateRole(c *gin.Context) {
+	roleId, roleErr := getUserRoleId(c.Param("id"))
+	if errorUtils.IsEntityError(c, roleErr) {
+		return
+	}
+	input := inputUserRole{}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		errorUtils.IsEntityError(c, errorUtils.NewUnprocessableEntityError("invalid json body"))
+		return
+	}
+
+	//a role that does not exist is reported by the change
+	before, _ := services.UserRoleService.GetRole(roleId)
+	role, err := services.UserRoleService.ChangeRole(roleId, input.Name)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+	recordAudit(c, "role", "update", role.ID, before, role)
+
+	c.JSON(http.StatusOK, role)
+}
+
+func DeleteRole(c *gin.Context) {
+	roleId, roleErr := getUserRoleId(c.Param("id"))
+	if errorUtils.IsEntityError(c, roleErr) {
+		return
+	}
+
+	before, _ := services.UserRoleService.GetRole(roleId)
+	if err := services.UserRoleService.RevokeRole(roleId); errorUtils.IsEntityError(c, err) {
+		return
+	}
+	recordAudit(c, "role", "delete", roleId, before, nil)
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+func GetUserRoles(c *gin.Context) {
+	userId, userErr := getUserId(c.Param("id"))
+	if errorUtils.IsEntityError(c, userErr) {
+		return
+	}
+
+	roles, err := services.UserRoleService.GetRolesByUserID(userId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+//AddUserRole gives the user one more role, which has to be a role of the RBAC policy
+func AddUserRole(c *gin.Context) {
+	userId, userErr := getUserId(c.Param("id"))
+	if errorUtils.IsEntityError(c, userErr) {
+		return
+	}
+	input := inputUserRole{}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		errorUtils.IsEntityError(c, errorUtils.NewUnprocessableEntityError("invalid json body"))
+		return
+	}
+
+	role, err := services.UserRoleService.AssignRole(userId, input.Name)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+	recordAudit(c, "role", "create", role.ID, nil, role)
+
+	c.JSON(http.StatusCreated, role)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+//getContextUserId returns the id of the authenticated user, set in the request context by the session handler
+func getContextUserId(c *gin.Context) (uint64, errorUtils.EntityError) {
+	userId, ok := c.Request.Context().Value(domain.RbacUserId()).(uint64)
+	if !ok {
+		return 0, errorUtils.NewInternalServerError("no user id could be found in context")
+	}
+	return userId, nil
+}
+
+func GetUserSessions(c *gin.Context) {
+	userId, userErr := getUserId(c.Param("id"))
+	if errorUtils.IsEntityError(c, userErr) {
+		return
+	}
+
+	sessions, err := services.UserSessionService.GetUserSessions(userId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+//DeleteUserSessions logs the user out everywhere
+func DeleteUserSessions(c *gin.Context) {
+	userId, userErr := getUserId(c.Param("id"))
+	if errorUtils.IsEntityError(c, userErr) {
+		return
+	}
+
+	revoked, err := services.UserSessionService.RevokeUserSessions(userId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted", "sessions": revoked})
+}
+
+//DeleteSession revokes one of the authenticated user's sessions, using the id given by GET /users/:id/sessions
+func DeleteSession(c *gin.Context) {
+	userId, userErr := getContextUserId(c)
+	if errorUtils.IsEntityError(c, userErr) {
+		return
+	}
+
+	if err := services.UserSessionService.RevokeSession(userId, c.Param("token")); errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+//ForceLogoutUser is the admin's way of kicking a user out, every session and refresh token of the user is deleted
+func ForceLogoutUser(c *gin.Context) {
+	userId, userErr := getUserId(c.Param("id"))
+	if errorUtils.IsEntityError(c, userErr) {
+		return
+	}
+
+	revoked, err := services.UserSessionService.RevokeUserSessions(userId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "logged out", "sessions": revoked})
+}
@@ -1,9 +1,9 @@
 package controllers
 
 import (
-	"GamesAPI/src/External/Steam"
 	"GamesAPI/src/services"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -14,12 +14,15 @@ type inputSyncGames struct{
 /*	1. 	trouver userid dans json, parse en uint64 -> si err , bad request
 	2. 	aller chercher le user associé en BD -> si pas trouvé, bad request
 	3. 	extraire steamId -> si vide, not found + message custom
-	4. 	aller chercher tous les game ids -> si err, 500 + err
-	5. 	pour chacun des game ids :
+	4. 	mettre la synchronisation en file -> si la file est pleine, 503
+	5. 	202 + la job, dont on peut suivre la progression via GET /sync-jobs/:id
+	La synchronisation elle-même (voir services.SyncJobsService) :
+	1. 	aller chercher tous les game ids -> si err, job failed
+	2. 	pour chacun des game ids :
 		.1 si un jeu n'existe pas avec game id :
-			obtenir le jeu steam associé (domain.Game) -> si erreur, 500
-			créer le jeu en BD -> si erreur, forward error
-	6. 	200
+			obtenir le jeu steam associé (domain.Game) -> si erreur, errored
+			créer le jeu en BD -> si erreur, errored
+		.2 ajouter le jeu à la bibliothèque de l'usager (ou MAJ son temps de jeu) -> si erreur, errored
 */
 func SyncGamesHandler(c *gin.Context) {
 	input := inputSyncGames{}
@@ -41,39 +44,17 @@ func SyncGamesHandler(c *gin.Context) {
 		return
 	}
 
-	gameIds, err := Steam.ExternalSteamUserService.GetUserOwnedGames(steamUserId)
-	if err != nil {
-		AbortWithStatusError(c, http.StatusInternalServerError, err)
-	}
-
-	gameCount := 0
-	errCount := 0
-	for _, gameId := range gameIds {
-		existsGameWithSteamId, errExists := services.GamesService.ExistsWithSteamID(gameId)
-		if errExists != nil {
-			AbortWithStatusError(c, errExists.Status(), errExists)
-			return
-		}
-		if !existsGameWithSteamId {
-			g, err := Steam.ExternalSteamUserService.GetGameInfo(gameId)
-			if err != nil {
-				errCount += 1
-				continue
-			}
-			_, errCreate := services.GamesService.CreateGame(&g)
-			if errCreate != nil {
-				println(gameCount)
-				AbortWithStatusError(c, errCreate.Status(), errCreate)
-				return
-			} else {
-				gameCount += 1
-			}
-		}
+	job, errEnqueue := services.SyncJobsService.EnqueueSync(user)
+	if errEnqueue != nil {
+		AbortWithStatusError(c, errEnqueue.Status(), errEnqueue)
+		return
 	}
+	//the library is the user's, the job is what the request added
+	recordAudit(c, "library", "sync", user.ID, nil, job)
 
-	c.JSON(http.StatusOK, gin.H{"number of games inserted" : gameCount,
-								"number of games errored"  : errCount,
-								"number of games skipped"  : len(gameIds)-gameCount-errCount})
+	//users can only follow their own jobs, under their own path
+	c.Header("Location", fmt.Sprintf("/users/%d/sync-jobs/%d", user.ID, job.ID))
+	c.JSON(http.StatusAccepted, job)
 }
 
 func AbortWithStatusError(c *gin.Context, code int, err error) {
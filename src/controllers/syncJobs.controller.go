@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+func getSyncJobId(jobIdParam string) (uint64, errorUtils.EntityError) {
+	jobId, jobError := strconv.ParseUint(jobIdParam, 10, 64)
+	if jobError != nil {
+		return 0, errorUtils.NewBadRequestError("sync job id should be a number")
+	}
+	return jobId, nil
+}
+
+func GetSyncJob(c *gin.Context) {
+	jobId, jobErr := getSyncJobId(c.Param("id"))
+	if errorUtils.IsEntityError(c, jobErr) {
+		return
+	}
+
+	job, err := services.SyncJobsService.GetJob(jobId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func CancelSyncJob(c *gin.Context) {
+	jobId, jobErr := getSyncJobId(c.Param("id"))
+	if errorUtils.IsEntityError(c, jobErr) {
+		return
+	}
+
+	job, err := services.SyncJobsService.CancelJob(jobId)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	//the worker running the job will mark it as cancelled as soon as it sees the request
+	c.JSON(http.StatusAccepted, job)
+}
+
+//GetUserSyncJob is GetSyncJob for the jobs of the user in the path
+func GetUserSyncJob(c *gin.Context) {
+	job, ok := userSyncJob(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+//CancelUserSyncJob is CancelSyncJob for the jobs of the user in the path
+func CancelUserSyncJob(c *gin.Context) {
+	job, ok := userSyncJob(c)
+	if !ok {
+		return
+	}
+
+	job, err := services.SyncJobsService.CancelJob(job.ID)
+	if errorUtils.IsEntityError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+//userSyncJob finds the job of the path, the jobs of other users are not found
+func userSyncJob(c *gin.Context) (*domain.SyncJob, bool) {
+	userId, userErr := getUserId(c.Param("id"))
+	if errorUtils.IsEntityError(c, userErr) {
+		return nil, false
+	}
+	jobId, jobErr := getSyncJobId(c.Param("jobId"))
+	if errorUtils.IsEntityError(c, jobErr) {
+		return nil, false
+	}
+
+	job, err := services.SyncJobsService.GetJob(jobId)
+	if errorUtils.IsEntityError(c, err) {
+		return nil, false
+	}
+	if job.UserID != userId {
+		notFound := errorUtils.NewNotFoundError(fmt.Sprintf("sync job %d of user %d not found", jobId, userId))
+		c.JSON(notFound.Status(), notFound)
+		return nil, false
+	}
+	return job, true
+}
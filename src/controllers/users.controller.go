@@ -5,8 +5,10 @@ import (
 	"GamesAPI/src/services"
 	"GamesAPI/src/utils/authUtils"
 	"GamesAPI/src/utils/errorUtils"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"sort"
 	"strconv"
 )
 
@@ -18,6 +20,21 @@ func getUserId(userIdParam string) (uint64, errorUtils.EntityError) {
 	return userId, nil
 }
 
+//visibleJSON writes value with only the fields the role granting access may see, see domain.RbacFields
+func visibleJSON(c *gin.Context, status int, value interface{}) {
+	decision, authorized := domain.RbacDecisionFromContext(c.Request.Context())
+	if !authorized {
+		c.JSON(status, value)
+		return
+	}
+	projected, err := decision.Fields.Project(c.Request.Context(), value)
+	if err != nil {
+		errorUtils.IsEntityError(c, errorUtils.NewInternalServerError("could not filter the response fields: "+err.Error()))
+		return
+	}
+	c.JSON(status, projected)
+}
+
 func GetUser(c *gin.Context) {
 	userId, userErr := getUserId(c.Param("id"))
 	if errorUtils.IsEntityError(c, userErr) {
@@ -29,7 +46,7 @@ func GetUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	visibleJSON(c, http.StatusOK, user)
 }
 
 func GetAllUsers(c *gin.Context) {
@@ -38,7 +55,7 @@ func GetAllUsers(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
+	visibleJSON(c, http.StatusOK, users)
 }
 
 func CreateUser(c *gin.Context) {
@@ -65,7 +82,11 @@ func CreateUser(c *gin.Context) {
 	name := body["name"]
 	email := body["email"]
 	password := body["password"]
-	role := body["role"]
+	//a role missing from the policy is refused before the user is created
+	role, roleErr := services.UserRoleService.PolicyRole(body["role"])
+	if errorUtils.IsEntityError(c, roleErr) {
+		return
+	}
 	passwordHash, hashErr := authUtils.HashAndSalt([]byte(password))
 
 	if hashErr != nil {
@@ -89,10 +110,7 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
-	r, err := services.UserRoleService.CreateRole(&domain.UserRole{
-		UserID: user.ID,
-		Name:   role,
-	})
+	r, err := services.UserRoleService.AssignRole(u.ID, role)
 
 	if err != nil {
 		c.JSON(err.Status(), gin.H{"Error": err.Error()})
@@ -104,8 +122,10 @@ func CreateUser(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"Error": "Could not create role for user"})
 		return
 	}
+	recordAudit(c, "user", "create", u.ID, nil, u)
+	recordAudit(c, "role", "create", r.ID, nil, r)
 
-	c.JSON(http.StatusCreated, u)
+	visibleJSON(c, http.StatusCreated, u)
 }
 
 func UpdateUser(c *gin.Context) {
@@ -114,19 +134,30 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	//only the fields sent are updated, the others keep their value
 	var user domain.User
-	if err := c.ShouldBindJSON(&user); user.Validate() != nil || err != nil {
+	var sent map[string]json.RawMessage
+	body, readErr := c.GetRawData()
+	if readErr != nil || json.Unmarshal(body, &sent) != nil || json.Unmarshal(body, &user) != nil {
 		userErr := errorUtils.NewUnprocessableEntityError("invalid json body")
 		c.JSON(userErr.Status(), userErr)
 		return
 	}
+	fields := make([]string, 0, len(sent))
+	for field := range sent {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
 	user.ID = userId
-	u, err := services.UsersService.UpdateUser(&user)
+	//a user who does not exist is reported by the update
+	before, _ := services.UsersService.GetUser(userId)
+	u, err := services.UsersService.UpdateUser(&user, fields...)
 	if errorUtils.IsEntityError(c, err) {
 		return
 	}
+	recordAudit(c, "user", "update", u.ID, before, u)
 
-	c.JSON(http.StatusOK, u)
+	visibleJSON(c, http.StatusOK, u)
 }
 
 func DeleteUser(c *gin.Context) {
@@ -134,8 +165,10 @@ func DeleteUser(c *gin.Context) {
 	if errorUtils.IsEntityError(c, err) {
 		return
 	}
+	before, _ := services.UsersService.GetUser(userId)
 	if err := services.UsersService.DeleteUser(userId); errorUtils.IsEntityError(c, err) {
 		return
 	}
+	recordAudit(c, "user", "delete", userId, before, nil)
 	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+var (
+	ApiKeyRepo ApiKeyRepoInterface = &apiKeyRepo{}
+)
+
+type ApiKeyRepoInterface interface {
+	Get(uint64) (*ApiKey, errorUtils.EntityError)
+	GetByPrefix(prefix string) (*ApiKey, errorUtils.EntityError)
+	GetAll() ([]ApiKey, errorUtils.EntityError)
+	GetByOwner(ownerId uint64) ([]ApiKey, errorUtils.EntityError)
+	Create(*ApiKey) (*ApiKey, errorUtils.EntityError)
+	Revoke(id uint64, at time.Time) errorUtils.EntityError
+	TouchLastUsed(id uint64, at time.Time) errorUtils.EntityError
+	Initialize(*gorm.DB)
+}
+
+type apiKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewApiKeyRepository(db *gorm.DB) ApiKeyRepoInterface {
+	return &apiKeyRepo{db: db}
+}
+
+func (a *apiKeyRepo) Initialize(db *gorm.DB) {
+	a.db = db
+	db.AutoMigrate(&ApiKey{})
+}
+
+func (a *apiKeyRepo) Get(id uint64) (*ApiKey, errorUtils.EntityError) {
+	var key ApiKey
+	if err := a.db.Where("id = ?", id).First(&key).Error; err != nil {
+		return nil, errorUtils.NewNotFoundError(err.Error())
+	}
+	return &key, nil
+}
+
+//GetByPrefix goes through the unique index on prefix
+func (a *apiKeyRepo) GetByPrefix(prefix string) (*ApiKey, errorUtils.EntityError) {
+	var key ApiKey
+	if err := a.db.Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errorUtils.NewNotFoundError(err.Error())
+		}
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return &key, nil
+}
+
+func (a *apiKeyRepo) GetAll() ([]ApiKey, errorUtils.EntityError) {
+	keys := []ApiKey{}
+	if err := a.db.Order("created_at desc").Find(&keys).Error; err != nil {
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return keys, nil
+}
+
+func (a *apiKeyRepo) GetByOwner(ownerId uint64) ([]ApiKey, errorUtils.EntityError) {
+	keys := []ApiKey{}
+	if err := a.db.Where("owner_id = ?", ownerId).Order("created_at desc").Find(&keys).Error; err != nil {
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return keys, nil
+}
+
+func (a *apiKeyRepo) Create(key *ApiKey) (*ApiKey, errorUtils.EntityError) {
+	if dbc := a.db.Create(key); dbc.Error != nil {
+		return nil, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return key, nil
+}
+
+func (a *apiKeyRepo) Revoke(id uint64, at time.Time) errorUtils.EntityError {
+	dbc := a.db.Model(&ApiKey{}).Where("id = ?", id).Updates(map[string]interface{}{"revoked": true, "revoked_at": at})
+	if dbc.Error != nil {
+		return errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	if dbc.RowsAffected == 0 {
+		return errorUtils.NewNotFoundError("api key does not exist")
+	}
+	return nil
+}
+
+func (a *apiKeyRepo) TouchLastUsed(id uint64, at time.Time) errorUtils.EntityError {
+	if dbc := a.db.Model(&ApiKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at); dbc.Error != nil {
+		return errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return nil
+}
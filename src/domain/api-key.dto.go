@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+//ApiKey identifies a client of the API. Only the hash of the secret is stored, the prefix is
+//the public part of the key used to find it back.
+type ApiKey struct {
+	ID         uint64     `gorm:"primary_key" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	OwnerId    uint64     `gorm:"column:owner_id;not null;index" json:"owner_id"`
+	Label      string     `gorm:"column:label;not null" json:"label"`
+	Prefix     string     `gorm:"column:prefix;not null;unique_index" json:"prefix"`
+	SecretHash string     `gorm:"column:secret_hash;not null" json:"-"`
+	Scopes     ApiScopes  `gorm:"column:scopes;type:varchar(255)" json:"scopes"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at" json:"expires_at"`
+	Revoked    bool       `gorm:"column:revoked;not null;default:0" json:"revoked"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+}
+
+func (k *ApiKey) Validate() errorUtils.EntityError {
+	if k.OwnerId <= 0 {
+		return errorUtils.NewUnprocessableEntityError("Api key owner_id is invalid")
+	}
+	if strings.TrimSpace(k.Label) == "" {
+		return errorUtils.NewUnprocessableEntityError("Api key label cannot be empty")
+	}
+	for _, scope := range k.Scopes {
+		if scope == "" || strings.ContainsAny(scope, ", ") {
+			return errorUtils.NewUnprocessableEntityError(fmt.Sprintf("Api key scope '%s' is invalid", scope))
+		}
+	}
+	return nil
+}
+
+//IsActive tells whether the key can still be used
+func (k *ApiKey) IsActive(now time.Time) bool {
+	return !k.Revoked && (k.ExpiresAt == nil || k.ExpiresAt.After(now))
+}
+
+//Client is the identity attached to the requests made with the key
+func (k *ApiKey) Client() *ApiClient {
+	return &ApiClient{
+		KeyId:   k.ID,
+		Prefix:  k.Prefix,
+		OwnerId: k.OwnerId,
+		Label:   k.Label,
+		Scopes:  k.Scopes,
+	}
+}
+
+//ApiClient is what the rest of the API knows about the client behind a request, see ApiClientKey
+type ApiClient struct {
+	KeyId   uint64
+	Prefix  string
+	OwnerId uint64
+	Label   string
+	Scopes  ApiScopes
+}
+
+//ApiScopes are stored as a comma-separated column
+type ApiScopes []string
+
+func (s ApiScopes) Value() (driver.Value, error) {
+	return strings.Join(s, ","), nil
+}
+
+func (s *ApiScopes) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into api scopes", value)
+	}
+	*s = ApiScopes{}
+	for _, scope := range strings.Split(raw, ",") {
+		if scope != "" {
+			*s = append(*s, scope)
+		}
+	}
+	return nil
+}
+
+//Allows tells whether a key may be used on the action of an rbac resource, on top of what its owner's roles allow.
+//A scope is a resource, e.g. "game", or a single action of it, e.g. "game:read". A key without scopes is not restricted.
+func (s ApiScopes) Allows(resource, action string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	return s.Has(resource) || s.Has(resource+":"+action)
+}
+
+func (s ApiScopes) Has(scope string) bool {
+	for _, candidate := range s {
+		if candidate == scope {
+			return true
+		}
+	}
+	return false
+}
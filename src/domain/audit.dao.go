@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	AuditRepo AuditRepoInterface = &auditRepo{}
+)
+
+//AuditRepoInterface has no way to change or remove an entry, the audit log is append-only
+type AuditRepoInterface interface {
+	Append(*AuditEntry) (*AuditEntry, errorUtils.EntityError)
+	Find(query *AuditQuery) ([]AuditEntry, uint64, errorUtils.EntityError)
+	Initialize(*gorm.DB)
+}
+
+type auditRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepoInterface {
+	return &auditRepo{db: db}
+}
+
+func (a *auditRepo) Initialize(db *gorm.DB) {
+	a.db = db
+	db.AutoMigrate(&AuditEntry{})
+}
+
+func (a *auditRepo) Append(entry *AuditEntry) (*AuditEntry, errorUtils.EntityError) {
+	if entry.ID != 0 {
+		return nil, errorUtils.NewConflictError("audit entries cannot be changed once appended")
+	}
+	if dbc := a.db.Create(entry); dbc.Error != nil {
+		return nil, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return entry, nil
+}
+
+func (a *auditRepo) Find(query *AuditQuery) ([]AuditEntry, uint64, errorUtils.EntityError) {
+	entries := []AuditEntry{}
+	var total uint64
+	filtered := applyAuditFilters(a.db.Model(&AuditEntry{}), query)
+	if err := filtered.Count(&total).Error; err != nil {
+		return nil, 0, errorUtils.NewInternalServerError(err.Error())
+	}
+
+	err := filtered.Order("id desc").
+		Offset(query.Offset()).
+		Limit(query.PageSize).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, errorUtils.NewInternalServerError(err.Error())
+	}
+	return entries, total, nil
+}
+
+func applyAuditFilters(db *gorm.DB, query *AuditQuery) *gorm.DB {
+	if query.ActorId != 0 {
+		db = db.Where("actor_id = ?", query.ActorId)
+	}
+	if query.ApiKeyId != 0 {
+		db = db.Where("api_key_id = ?", query.ApiKeyId)
+	}
+	if query.Resource != "" {
+		db = db.Where("resource = ?", query.Resource)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.EntityId != 0 {
+		db = db.Where("entity_id = ?", query.EntityId)
+	}
+	if query.RequestId != "" {
+		db = db.Where("request_id = ?", query.RequestId)
+	}
+	if query.Since != nil {
+		db = db.Where("created_at >= ?", *query.Since)
+	}
+	if query.Until != nil {
+		db = db.Where("created_at <= ?", *query.Until)
+	}
+	if query.BeforeId != 0 {
+		db = db.Where("id < ?", query.BeforeId)
+	}
+	return db
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	DefaultAuditPageSize uint64 = 50
+	MaxAuditPageSize     uint64 = 500
+)
+
+//auditRedacted are fields whose values never make it to the audit log, only the fact they changed
+var auditRedacted = map[string]bool{"password_hash": true}
+
+//auditSkipped are fields every change touches, the entry has a timestamp of its own
+var auditSkipped = map[string]bool{"created_at": true, "updated_at": true}
+
+//AuditEntry records a change made through the API: who made it, with which API key, and what it changed.
+//Entries are only ever appended, see AuditRepo.
+type AuditEntry struct {
+	ID        uint64    `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	//ActorId is the user making the change, ApiKeyId and ApiKeyPrefix the key the request was made with
+	ActorId      uint64 `gorm:"column:actor_id;index" json:"actor_id"`
+	ApiKeyId     uint64 `gorm:"column:api_key_id" json:"api_key_id"`
+	ApiKeyPrefix string `gorm:"column:api_key_prefix" json:"api_key_prefix"`
+	//Resource and Action are named as in the RBAC policy, e.g. game and update, or library and sync
+	Resource  string       `gorm:"column:resource;not null;index" json:"resource"`
+	Action    string       `gorm:"column:action;not null" json:"action"`
+	EntityId  uint64       `gorm:"column:entity_id;index" json:"entity_id"`
+	Changes   AuditChanges `gorm:"column:changes;type:text" json:"changes"`
+	RequestId string       `gorm:"column:request_id;index" json:"request_id"`
+}
+
+//AuditChange is the value of a field before and after the change, nil when it did not exist
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+//AuditChanges are the fields that changed, stored as a JSON object
+type AuditChanges map[string]AuditChange
+
+func (a AuditChanges) Value() (driver.Value, error) {
+	encoded, err := json.Marshal(a)
+	return string(encoded), err
+}
+
+func (a *AuditChanges) Scan(value interface{}) error {
+	raw, err := scanString(value)
+	if err != nil {
+		return fmt.Errorf("cannot scan %T into audit changes", value)
+	}
+	*a = AuditChanges{}
+	if raw == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(raw), (*map[string]AuditChange)(a))
+}
+
+//AuditDiff compares the fields of the entity before and after the change, as written in JSON.
+//Either can be nil, for a creation or a deletion.
+func AuditDiff(before interface{}, after interface{}) (AuditChanges, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := AuditChanges{}
+	for name, value := range beforeFields {
+		if next, exists := afterFields[name]; !exists || !reflect.DeepEqual(value, next) {
+			changes[name] = AuditChange{Before: value, After: next}
+		}
+	}
+	for name, value := range afterFields {
+		if _, exists := beforeFields[name]; !exists {
+			changes[name] = AuditChange{After: value}
+		}
+	}
+	for name, change := range changes {
+		if auditSkipped[name] {
+			delete(changes, name)
+		} else if auditRedacted[name] {
+			changes[name] = AuditChange{Before: redacted(change.Before), After: redacted(change.After)}
+		}
+	}
+	return changes, nil
+}
+
+func redacted(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return "[redacted]"
+}
+
+//auditFields are the top-level fields of entity once written as JSON
+func auditFields(entity interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if entity == nil || (reflect.ValueOf(entity).Kind() == reflect.Ptr && reflect.ValueOf(entity).IsNil()) {
+		return fields, nil
+	}
+	raw, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	//numbers are kept as written, a float64 would round large ids
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("cannot audit %T, it is not written as a JSON object", entity)
+	}
+	return fields, nil
+}
+
+//AuditQuery filters the audit log, most recent entries first
+type AuditQuery struct {
+	Page      uint64
+	PageSize  uint64
+	ActorId   uint64
+	ApiKeyId  uint64
+	Resource  string
+	Action    string
+	EntityId  uint64
+	RequestId string
+	Since     *time.Time
+	Until     *time.Time
+	//BeforeId only keeps the entries older than this one, so the log can be walked while entries are added
+	BeforeId uint64
+}
+
+//NewAuditQuery returns a query for the first page of the whole audit log
+func NewAuditQuery() *AuditQuery {
+	return &AuditQuery{
+		Page:     1,
+		PageSize: DefaultAuditPageSize,
+	}
+}
+
+func (q *AuditQuery) Validate() errorUtils.EntityError {
+	if q.Page < 1 {
+		return errorUtils.NewBadRequestError("page should be greater than 0")
+	}
+	if q.PageSize < 1 || q.PageSize > MaxAuditPageSize {
+		return errorUtils.NewBadRequestError(fmt.Sprintf("page_size should be between 1 and %d", MaxAuditPageSize))
+	}
+	if q.Since != nil && q.Until != nil && q.Since.After(*q.Until) {
+		return errorUtils.NewBadRequestError("since cannot be later than until")
+	}
+	return nil
+}
+
+//Offset is the number of entries to skip to reach the requested page
+func (q *AuditQuery) Offset() uint64 {
+	return (q.Page - 1) * q.PageSize
+}
+
+//AuditPage is one page of the audit log
+type AuditPage struct {
+	Items    []AuditEntry `json:"items"`
+	Total    uint64       `json:"total"`
+	Page     uint64       `json:"page"`
+	PageSize uint64       `json:"page_size"`
+}
@@ -3,9 +3,31 @@ package domain
 type contextKey string
 
 var (
-	contextKeyRbacUserId = contextKey("userId")
+	contextKeyRbacUserId      = contextKey("userId")
+	contextKeyRbacRouteParams = contextKey("routeParams")
+	contextKeyRbacUserRoles   = contextKey("userRoles")
+	contextKeyApiClient       = contextKey("apiClient")
+	contextKeyRequestHeaders  = contextKey("requestHeaders")
+	contextKeyRequestBody     = contextKey("requestBody")
+	contextKeyRbacDecision    = contextKey("rbacDecision")
+	contextKeyRbacTrace       = contextKey("rbacTrace")
+	contextKeyRequestId       = contextKey("requestId")
 )
 
+//RbacContextKeys lists the context values the API sets, which rules can refer to as ctx.<name>.
+//The decision and the trace are left out, it is only stored once the rules have been checked,
+//and so is the request id, which no rule has a use for.
+func RbacContextKeys() []string {
+	return []string{
+		contextKeyRbacUserId.String(),
+		contextKeyRbacRouteParams.String(),
+		contextKeyRbacUserRoles.String(),
+		contextKeyApiClient.String(),
+		contextKeyRequestHeaders.String(),
+		contextKeyRequestBody.String(),
+	}
+}
+
 func (c contextKey) String() string {
 	return string(c)
 }
@@ -17,3 +39,45 @@ func ContextKey(s string) string {
 func RbacUserId() string {
 	return contextKeyRbacUserId.String()
 }
+
+//RbacRouteParams is the context key under which the named parameters of the matched route are stored (map[string]string)
+func RbacRouteParams() string {
+	return contextKeyRbacRouteParams.String()
+}
+
+//RbacRequestHeaders is the context key under which the headers of the request are stored (http.Header),
+//so header rules can be checked, and enforced ones set on the request itself
+func RbacRequestHeaders() string {
+	return contextKeyRequestHeaders.String()
+}
+
+//RbacRequestBody is the context key under which the buffered body of the request is stored ([]byte), for body rules
+func RbacRequestBody() string {
+	return contextKeyRequestBody.String()
+}
+
+//RbacUserRoles is the context key under which the roles carried by a JWT are stored ([]string),
+//when present the authorization layer trusts them instead of looking the roles up
+func RbacUserRoles() string {
+	return contextKeyRbacUserRoles.String()
+}
+
+//ApiClientKey is the context key under which the client identified by the x-api-key header is stored (*ApiClient)
+func ApiClientKey() string {
+	return contextKeyApiClient.String()
+}
+
+//RbacDecisionKey is the context key under which the authorization layer stores its decision (*RbacDecision)
+func RbacDecisionKey() string {
+	return contextKeyRbacDecision.String()
+}
+
+//RbacTraceKey is the context key under which rules record how they were checked (*RbacTrace), see WithRbacTrace
+func RbacTraceKey() string {
+	return contextKeyRbacTrace.String()
+}
+
+//RequestIdKey is the context key under which the id of the request is stored (string), see the X-Request-Id header
+func RequestIdKey() string {
+	return contextKeyRequestId.String()
+}
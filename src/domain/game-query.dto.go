@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultGamePageSize uint64 = 50
+	MaxGamePageSize     uint64 = 500
+	//MaxGamePage keeps the offset, and the page count of the links, far from overflowing
+	MaxGamePage uint64 = 1000000
+)
+
+//maps the sort keys accepted by the API to their column in the games table
+var gameSortColumns = map[string]string{
+	"id":          "id",
+	"title":       "title",
+	"releaseDate": "releaseDate",
+	"created_at":  "created_at",
+}
+
+//GameQuery describes which page of the game catalog we want, and how it should be filtered and sorted
+type GameQuery struct {
+	Page           uint64
+	PageSize       uint64
+	Developer      string
+	Publisher      string
+	SteamId        string
+	ReleasedAfter  *time.Time
+	//ReleasedBefore is the last release day, the games released on that day are included
+	ReleasedBefore *time.Time
+	Sort           string
+	Descending     bool
+}
+
+//NewGameQuery returns a query for the first page of the catalog, sorted by id
+func NewGameQuery() *GameQuery {
+	return &GameQuery{
+		Page:     1,
+		PageSize: DefaultGamePageSize,
+		Sort:     "id",
+	}
+}
+
+func (q *GameQuery) Validate() errorUtils.EntityError {
+	if q.Page < 1 || q.Page > MaxGamePage {
+		return errorUtils.NewBadRequestError(fmt.Sprintf("page should be between 1 and %d", MaxGamePage))
+	}
+
+	if q.PageSize < 1 || q.PageSize > MaxGamePageSize {
+		return errorUtils.NewBadRequestError(fmt.Sprintf("page_size should be between 1 and %d", MaxGamePageSize))
+	}
+
+	if _, ok := gameSortColumns[q.Sort]; !ok {
+		return errorUtils.NewBadRequestError("sort should be one of id, title, releaseDate, created_at")
+	}
+
+	if q.ReleasedAfter != nil && q.ReleasedBefore != nil && q.ReleasedAfter.After(*q.ReleasedBefore) {
+		return errorUtils.NewBadRequestError("released_after cannot be later than released_before")
+	}
+	return nil
+}
+
+//Offset is the number of rows to skip to reach the requested page
+func (q *GameQuery) Offset() uint64 {
+	return (q.Page - 1) * q.PageSize
+}
+
+//OrderBy returns the ORDER BY clause matching the requested sort.
+//id is always used as a tie-breaker so pages stay stable.
+func (q *GameQuery) OrderBy() string {
+	direction := "asc"
+	if q.Descending {
+		direction = "desc"
+	}
+	column := gameSortColumns[q.Sort]
+	if column == "" || column == "id" {
+		return fmt.Sprintf("id %s", direction)
+	}
+	return fmt.Sprintf("%s %s, id asc", column, direction)
+}
+
+//GamePage is one page of the game catalog, along with what's needed to navigate to its neighbours
+type GamePage struct {
+	Items    []Game `json:"items"`
+	Total    uint64 `json:"total"`
+	Page     uint64 `json:"page"`
+	PageSize uint64 `json:"page_size"`
+	Next     string `json:"next,omitempty"`
+	Prev     string `json:"prev,omitempty"`
+}
+
+func (p *GamePage) HasNext() bool {
+	return p.Page*p.PageSize < p.Total
+}
+
+func (p *GamePage) HasPrev() bool {
+	return p.Page > 1
+}
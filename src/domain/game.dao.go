@@ -3,6 +3,7 @@ package domain
 import (
 	"GamesAPI/src/utils/errorUtils"
 	"github.com/jinzhu/gorm"
+	"strings"
 )
 
 var (
@@ -14,7 +15,7 @@ type GameRepoInterface interface {
 	Create(*Game) (*Game, errorUtils.EntityError)
 	Update(*Game) (*Game, errorUtils.EntityError)
 	Delete(uint64) errorUtils.EntityError
-	GetAll() ([]Game, errorUtils.EntityError)
+	GetAll(query *GameQuery) ([]Game, uint64, errorUtils.EntityError)
 	Initialize(*gorm.DB)
 }
 
@@ -64,8 +65,47 @@ func (g *gameRepo) Delete(gameId uint64) errorUtils.EntityError {
 	return errorUtils.NewEntityError(dbc.Error)
 }
 
-func (g *gameRepo) GetAll() ([]Game, errorUtils.EntityError) {
+func (g *gameRepo) GetAll(query *GameQuery) ([]Game, uint64, errorUtils.EntityError) {
 	var games []Game
-	g.db.Find(&games)
-	return games, nil
+	var total uint64
+	filtered := applyGameFilters(g.db.Model(&Game{}), query)
+	if err := filtered.Count(&total).Error; err != nil {
+		return nil, 0, errorUtils.NewInternalServerError(err.Error())
+	}
+
+	err := filtered.Order(query.OrderBy()).
+		Offset(query.Offset()).
+		Limit(query.PageSize).
+		Find(&games).Error
+	if err != nil {
+		return nil, 0, errorUtils.NewInternalServerError(err.Error())
+	}
+	return games, total, nil
+}
+
+func applyGameFilters(db *gorm.DB, query *GameQuery) *gorm.DB {
+	if query.Developer != "" {
+		db = db.Where(`developer LIKE ? ESCAPE '\'`, likeContains(query.Developer))
+	}
+	if query.Publisher != "" {
+		db = db.Where(`publisher LIKE ? ESCAPE '\'`, likeContains(query.Publisher))
+	}
+	if query.SteamId != "" {
+		db = db.Where("steam_id = ?", query.SteamId)
+	}
+	if query.ReleasedAfter != nil {
+		db = db.Where("releaseDate >= ?", *query.ReleasedAfter)
+	}
+	if query.ReleasedBefore != nil {
+		db = db.Where("releaseDate < ?", query.ReleasedBefore.AddDate(0, 0, 1))
+	}
+	return db
+}
+
+//likeEscaper escapes the wildcards of LIKE patterns, [ included since SQL Server reads it as a character range
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "[", `\[`)
+
+//likeContains is the LIKE pattern matching the values containing s as is
+func likeContains(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
 }
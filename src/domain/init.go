@@ -1,9 +1,25 @@
 package domain
 
-import "github.com/jinzhu/gorm"
+import (
+	"github.com/jinzhu/gorm"
+	"os"
+)
 
 func InitRepositories(db *gorm.DB) {
 	UserRepo.Initialize(db)
 	GameRepo.Initialize(db)
 	UserRoleRepo.Initialize(db)
+	UserGameRepo.Initialize(db)
+	SyncJobRepo.Initialize(db)
+	ApiKeyRepo.Initialize(db)
+	RbacPolicyRepo.Initialize(db)
+	AuditRepo.Initialize(db)
+
+	//sessions and refresh tokens are kept in memory unless SESSION_STORE asks for the database
+	if os.Getenv("SESSION_STORE") == SessionStoreDatabase {
+		UserSessionRepo = NewUserSessionDBRepository(db)
+		RefreshTokenRepo = NewRefreshTokenDBRepository(db)
+	}
+	UserSessionRepo.Initialize(db)
+	RefreshTokenRepo.Initialize(db)
 }
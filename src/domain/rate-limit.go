@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"time"
+)
+
+//DefaultRateLimitGroup applies to the route groups that have no limit of their own
+const DefaultRateLimitGroup = "default"
+
+//RateLimit lets a client make Requests per Period, refilled continuously.
+//Burst is how many requests can be made at once after a quiet spell, Requests when not set.
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst,omitempty"`
+}
+
+//RateLimits maps a route group to its limit
+type RateLimits map[string]RateLimit
+
+func RateLimitsFromFile(path string) (RateLimits, error) {
+	f, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := RateLimits{}
+	err = yaml.Unmarshal(f, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	for group, limit := range limits {
+		if err := limit.Validate(); err != nil {
+			return nil, fmt.Errorf("rate limit of %s: %s", group, err.Error())
+		}
+	}
+	return limits, nil
+}
+
+func (r RateLimit) Validate() error {
+	if r.Requests <= 0 {
+		return fmt.Errorf("requests should be positive, got %d", r.Requests)
+	}
+	if r.Period <= 0 {
+		return fmt.Errorf("period should be a positive duration, got %s", r.Period)
+	}
+	if r.Burst < 0 {
+		return fmt.Errorf("burst should not be negative, got %d", r.Burst)
+	}
+	return nil
+}
+
+//Capacity is the size of the bucket, the most requests a client can make at once
+func (r RateLimit) Capacity() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Requests
+}
+
+//For returns the limit of the route group, or the default one. No limit at all means the group is not throttled.
+func (r RateLimits) For(group string) (RateLimit, bool) {
+	if limit, exists := r[group]; exists {
+		return limit, true
+	}
+	limit, exists := r[DefaultRateLimitGroup]
+	return limit, exists
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+//RoleStrategy is how the permissions of a user holding several roles are combined
+type RoleStrategy string
+
+const (
+	//RoleStrategyAnyAllow grants access as soon as one of the roles grants it
+	RoleStrategyAnyAllow RoleStrategy = "any-allow"
+	//RoleStrategyDenyOverrides refuses access when one of the roles explicitly denies it (allow: false),
+	//even if another role grants it. Roles with no permission for the endpoint do not deny anything.
+	RoleStrategyDenyOverrides RoleStrategy = "deny-overrides"
+
+	DefaultRoleStrategy = RoleStrategyAnyAllow
+)
+
+//ParseRoleStrategy falls back on DefaultRoleStrategy when s is empty
+func ParseRoleStrategy(s string) (RoleStrategy, error) {
+	switch strategy := RoleStrategy(s); strategy {
+	case "":
+		return DefaultRoleStrategy, nil
+	case RoleStrategyAnyAllow, RoleStrategyDenyOverrides:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown role strategy '%s', expected %s or %s", s, RoleStrategyAnyAllow, RoleStrategyDenyOverrides)
+}
+
+//RbacDecision records how a request was authorized, so handlers can tell which role granted access
+type RbacDecision struct {
+	//Role granted access, empty when access was refused
+	Role     string       `json:"role,omitempty"`
+	Roles    []string     `json:"roles"`
+	Strategy RoleStrategy `json:"strategy"`
+	Resource string       `json:"resource"`
+	Endpoint string       `json:"endpoint"`
+	//Fields are those the responses are projected on, see RbacFields.Visible
+	Fields *RbacFields `json:"fields,omitempty"`
+}
+
+//RbacDecisionFromContext returns the decision the authorization layer stored in the request context
+func RbacDecisionFromContext(ctx context.Context) (*RbacDecision, bool) {
+	decision, ok := ctx.Value(RbacDecisionKey()).(*RbacDecision)
+	return decision, ok
+}
@@ -2,55 +2,215 @@ package domain
 
 import (
 	"GamesAPI/src/utils/errorUtils"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
-	"path"
+	"strings"
 )
 
 type Ensurer struct {
-	Query  []Rule `yaml:"query"`
-	Header []Rule `yaml:"header"`
-	Path   []Rule `yaml:"path"`
+	Query  []Rule `yaml:"query,omitempty" json:"query,omitempty"`
+	Header []Rule `yaml:"header,omitempty" json:"header,omitempty"`
+	Path   []Rule `yaml:"path,omitempty" json:"path,omitempty"`
+	//Body rules check fields of a JSON body, nested ones being reached with dots (e.g. owner.id)
+	Body []Rule `yaml:"body,omitempty" json:"body,omitempty"`
 }
 
 type Enforcer Ensurer
 
-// QueryComplies checks whether query request complies with rules
+// QueryComplies checks whether query request complies with rules.
+// A missing query parameter is an empty value, route parameters are checked by PathComplies.
 func (ens Ensurer) QueryComplies(ctx context.Context, url *url.URL) error {
 	if ens.Query == nil || len(ens.Query) <= 0 {
 		return nil
 	}
 
+	query := url.Query()
 	for _, rule := range ens.Query {
-		actual := url.Query().Get(rule.Key)
-		if actual == "" {
-			actual = path.Base(url.Path)
+		actual := query.Get(rule.Key)
+		expected, err := rule.FromContext(ctx)
+		if err == nil {
+			err = ruleViolation("query", rule, expected, actual)
+		}
+		traceRule(ctx, "ensure", "query", rule, expected, actual, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//ruleViolation tells why actual does not comply with the rule, nil when it does
+func ruleViolation(kind string, rule Rule, expected interface{}, actual interface{}) error {
+	complies, err := rule.Check(expected, actual)
+	if err != nil {
+		return fmt.Errorf("%s rule violation: ensure '%s' %s '%v', but %s", kind, rule.Key, rule.Operator, expected, err.Error())
+	}
+	if !complies {
+		return fmt.Errorf("%s rule violation: ensure '%s' %s '%v', instead got: '%v'",
+			kind, rule.Key, rule.Operator, expected, actual)
+	}
+	return nil
+}
+
+// PathComplies checks whether the named parameters of the matched route comply with rules.
+// Route parameters are always strings, they are coerced to the type of the expected value.
+func (ens Ensurer) PathComplies(ctx context.Context) error {
+	if ens.Path == nil || len(ens.Path) <= 0 {
+		return nil
+	}
+
+	params, _ := ctx.Value(RbacRouteParams()).(map[string]string)
+	for _, rule := range ens.Path {
+		actual := params[rule.Key]
+		expected, err := rule.FromContext(ctx)
+		if err == nil {
+			err = ruleViolation("path", rule, expected, actual)
+		}
+		traceRule(ctx, "ensure", "path", rule, expected, actual, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HeaderComplies checks whether the request headers found in the context comply with rules.
+// Header names are case-insensitive, and only the first value of a header is checked.
+func (ens Ensurer) HeaderComplies(ctx context.Context) error {
+	if ens.Header == nil || len(ens.Header) <= 0 {
+		return nil
+	}
+
+	headers, _ := ctx.Value(RbacRequestHeaders()).(http.Header)
+	for _, rule := range ens.Header {
+		actual := headers.Get(rule.Key)
+		expected, err := rule.FromContext(ctx)
+		if err == nil {
+			err = ruleViolation("header", rule, expected, actual)
+		}
+		traceRule(ctx, "ensure", "header", rule, expected, actual, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BodyComplies checks whether the fields of the JSON body found in the context comply with rules.
+// A missing field is an empty value, so is every field of a request without a body.
+// Fields are matched the way encoding/json binds them for the handlers: whatever their case, the last one wins.
+func (ens Ensurer) BodyComplies(ctx context.Context) error {
+	if ens.Body == nil || len(ens.Body) <= 0 {
+		return nil
+	}
+
+	raw, _ := ctx.Value(RbacRequestBody()).([]byte)
+	var body interface{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		//numbers are kept as written, a float64 would round large ids
+		decoder.UseNumber()
+		var err error
+		if body, err = decodeBody(decoder); err != nil {
+			return fmt.Errorf("body rule violation: request body is not valid JSON - %s", err.Error())
 		}
+	}
+	for _, rule := range ens.Body {
+		actual := bodyField(body, rule.Key)
 		expected, err := rule.FromContext(ctx)
+		if err == nil {
+			err = ruleViolation("body", rule, expected, actual)
+		}
+		traceRule(ctx, "ensure", "body", rule, expected, actual, err)
 		if err != nil {
 			return err
 		}
-		if !rule.Comply(expected, actual) {
-			return fmt.Errorf("query rule violation: ensure '%s' %s '%v', instead got: '%s'",
-				rule.Key, rule.Operator, expected, actual)
+	}
+	return nil
+}
+
+//bodyObject keeps the fields of a JSON object in the order they were sent, a map would lose which one came last
+type bodyObject []bodyMember
+
+type bodyMember struct {
+	name  string
+	value interface{}
+}
+
+//get is the value encoding/json would bind to a struct field named name, nil when there is none
+func (o bodyObject) get(name string) interface{} {
+	for i := len(o) - 1; i >= 0; i-- {
+		if strings.EqualFold(o[i].name, name) {
+			return o[i].value
 		}
 	}
 	return nil
 }
 
+//decodeBody reads the next JSON value, objects being read as bodyObject
+func decodeBody(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		object := bodyObject{}
+		for decoder.More() {
+			name, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeBody(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, bodyMember{name: name.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return object, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for decoder.More() {
+			value, err := decodeBody(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = decoder.Token()
+		return list, err
+	}
+	return token, nil
+}
+
+//bodyField follows the dot-separated key through the JSON objects, nil when a part of it is missing
+func bodyField(body interface{}, key string) interface{} {
+	value := body
+	for _, name := range strings.Split(key, ".") {
+		object, isObject := value.(bodyObject)
+		if !isObject {
+			return nil
+		}
+		value = object.get(name)
+	}
+	return value
+}
+
 // QueryComplies enforces query request from rule
 func (enf Enforcer) QueryComplies(ctx context.Context, url *url.URL) error {
 	q := url.Query()
 	for _, rule := range enf.Query {
-		expected, err := rule.FromContext(ctx)
+		valueStr, err := enforcedValue(ctx, rule)
+		traceRule(ctx, "enforce", "query", rule, valueStr, q.Get(rule.Key), err)
 		if err != nil {
 			return err
 		}
-		valueStr, isString := expected.(string)
-		if !isString {
-			return errorUtils.ErrNotString
-		}
 
 		q.Set(rule.Key, valueStr)
 	}
@@ -58,3 +218,38 @@ func (enf Enforcer) QueryComplies(ctx context.Context, url *url.URL) error {
 	// whole enforced with rules
 	return nil
 }
+
+// HeaderComplies enforces the request headers found in the context, replacing whatever value the client sent
+func (enf Enforcer) HeaderComplies(ctx context.Context) error {
+	if enf.Header == nil || len(enf.Header) <= 0 {
+		return nil
+	}
+
+	headers, isHeader := ctx.Value(RbacRequestHeaders()).(http.Header)
+	if !isHeader {
+		return errors.New("no request headers could be found in context")
+	}
+	for _, rule := range enf.Header {
+		valueStr, err := enforcedValue(ctx, rule)
+		traceRule(ctx, "enforce", "header", rule, valueStr, headers.Get(rule.Key), err)
+		if err != nil {
+			return err
+		}
+
+		headers.Set(rule.Key, valueStr)
+	}
+	return nil
+}
+
+//enforcedValue is the value an enforce rule sets. Numbers such as ctx.userId are written out, lists and maps cannot be.
+func enforcedValue(ctx context.Context, rule Rule) (string, error) {
+	expected, err := rule.FromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	valueStr, stringErr := asString(expected)
+	if stringErr != nil || expected == nil {
+		return "", errorUtils.ErrNotString
+	}
+	return valueStr, nil
+}
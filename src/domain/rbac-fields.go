@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//RbacFields restricts the fields of a resource a role sees and writes, by their JSON names.
+//A missing or empty list does not restrict anything, allow: false is how to refuse every write.
+type RbacFields struct {
+	//Visible are the fields of the records in responses, those of the read permission of the resource apply to
+	//the endpoints not listing their own
+	Visible []string `yaml:"visible,omitempty" json:"visible,omitempty"`
+	//Owner is the field holding the id of the user a record belongs to, Own the fields visible on the user's own records too
+	Owner string   `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Own   []string `yaml:"own,omitempty" json:"own,omitempty"`
+	//Writable are the fields a JSON body may set, the request is refused when it sets another one
+	Writable []string `yaml:"writable,omitempty" json:"writable,omitempty"`
+}
+
+// WritableComplies checks that the JSON body found in the context only sets writable fields.
+// Only the top-level fields are checked, a request without a body sets none.
+func (fields *RbacFields) WritableComplies(ctx context.Context) error {
+	if fields == nil || len(fields.Writable) == 0 {
+		return nil
+	}
+
+	raw, _ := ctx.Value(RbacRequestBody()).([]byte)
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return errors.New("field rule violation: request body should be a JSON object")
+	}
+	names := make([]string, 0, len(body))
+	for name := range body {
+		names = append(names, name)
+	}
+	//in order, so the same body is always refused for the same field
+	sort.Strings(names)
+	rule := Rule{Key: "writable", Operator: OperatorIn, Values: fields.Writable}
+	for _, name := range names {
+		var err error
+		if !containsField(fields.Writable, name) {
+			err = fmt.Errorf("field rule violation: '%s' is not writable, expected one of [%s]", name, strings.Join(fields.Writable, ", "))
+		}
+		traceRule(ctx, "writable", "body", rule, fields.Writable, name, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Project keeps the visible fields of value, a record or a list of records, once written as JSON.
+// The user's own records, their owner field being ctx.userId, keep the own fields too.
+func (fields *RbacFields) Project(ctx context.Context, value interface{}) (interface{}, error) {
+	if fields == nil || len(fields.Visible) == 0 {
+		return value, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	//numbers are kept as written, a float64 would round large ids
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	userId := ctx.Value(RbacUserId())
+	switch records := decoded.(type) {
+	case map[string]interface{}:
+		return fields.projectRecord(records, userId), nil
+	case []interface{}:
+		projected := make([]interface{}, 0, len(records))
+		for _, record := range records {
+			if object, isObject := record.(map[string]interface{}); isObject {
+				record = fields.projectRecord(object, userId)
+			}
+			projected = append(projected, record)
+		}
+		return projected, nil
+	}
+	return decoded, nil
+}
+
+func (fields *RbacFields) projectRecord(record map[string]interface{}, userId interface{}) map[string]interface{} {
+	owned := fields.Owner != "" && userId != nil && fmt.Sprint(record[fields.Owner]) == fmt.Sprint(userId)
+	projected := map[string]interface{}{}
+	for name, value := range record {
+		if containsField(fields.Visible, name) || (owned && containsField(fields.Own, name)) {
+			projected[name] = value
+		}
+	}
+	return projected
+}
+
+func containsField(fields []string, name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (fields *RbacFields) lint() []RbacIssue {
+	var issues []RbacIssue
+	if fields == nil {
+		return issues
+	}
+	add := func(severity string, format string, args ...interface{}) {
+		issues = append(issues, RbacIssue{Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+	if len(fields.Own) > 0 && fields.Owner == "" {
+		add(RbacIssueError, "own fields need an owner field to tell the user's records apart")
+	}
+	if fields.Owner != "" && len(fields.Own) == 0 {
+		add(RbacIssueWarning, "owner field %s has no own fields, it is ignored", fields.Owner)
+	}
+	if len(fields.Own) > 0 && len(fields.Visible) == 0 {
+		add(RbacIssueWarning, "own fields are ignored, every field is visible without a visible list")
+	}
+	for _, list := range []struct {
+		kind  string
+		names []string
+	}{{"visible", fields.Visible}, {"own", fields.Own}, {"writable", fields.Writable}} {
+		for _, name := range list.names {
+			if strings.TrimSpace(name) == "" {
+				add(RbacIssueError, "%s fields have an empty name", list.kind)
+			}
+		}
+	}
+	return issues
+}
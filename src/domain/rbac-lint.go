@@ -0,0 +1,275 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	//RbacIssueError makes the policy unusable, it is refused at startup and on reloads, once the routes are known
+	RbacIssueError = "error"
+	//RbacIssueWarning is most likely a mistake, but the policy still does what it says
+	RbacIssueWarning = "warning"
+)
+
+//a context reference is ctx followed by one or more dot-separated names, e.g. ctx.userId
+var ctxReferencePattern = regexp.MustCompile(`^ctx(\.[A-Za-z_][A-Za-z0-9_]*)+$`)
+
+//RbacIssue is something wrong with a policy. Where is role.resource.endpoint, or as much of it as applies.
+type RbacIssue struct {
+	Severity string
+	Where    string
+	Message  string
+}
+
+func (i RbacIssue) String() string {
+	if i.Where == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s: %s", i.Where, i.Message)
+}
+
+//RbacSurface is what the server exposes to a policy: the endpoints of every resource the routes map to,
+//and the context values rules can refer to
+type RbacSurface struct {
+	Resources   map[string]map[string]bool
+	ContextKeys map[string]bool
+}
+
+//Lint lists the issues of the policy, errors first. Without a surface, only the policy itself is checked.
+func (rbac RBAC) Lint(surface *RbacSurface) []RbacIssue {
+	var issues []RbacIssue
+	add := func(severity string, where string, format string, args ...interface{}) {
+		issues = append(issues, RbacIssue{Severity: severity, Where: where, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(rbac) == 0 {
+		add(RbacIssueError, "", "policy has no roles")
+	}
+	for roleName, role := range rbac {
+		if len(role) == 0 {
+			add(RbacIssueError, roleName, "role has no resources")
+		}
+		for resourceName, resource := range role {
+			where := roleName + "." + resourceName
+			endpoints, exposed := map[string]bool{}, true
+			if surface != nil && resourceName != RbacWildcard {
+				endpoints, exposed = surface.Resources[resourceName]
+				if !exposed && declaresAny(resource, roleName) {
+					message := "resource is not exposed by any route"
+					if suggestion := closestName(resourceName, surface.Resources); suggestion != "" {
+						message += fmt.Sprintf(", did you mean %s?", suggestion)
+					}
+					add(RbacIssueError, where, message)
+				}
+			}
+			for endpointName, permission := range resource {
+				where := where + "." + endpointName
+				//inherited permissions are checked under the role declaring them
+				if permission.DeclaredBy != "" && permission.DeclaredBy != roleName {
+					if shadowed := rbac.shadowedWildcard(roleName, resourceName, endpointName); shadowed != "" {
+						add(RbacIssueWarning, where, "inherited from %s, takes precedence over %s.%s", permission.DeclaredBy, roleName, shadowed)
+					}
+					continue
+				}
+				if surface != nil && exposed && resourceName != RbacWildcard && endpointName != RbacWildcard && !endpoints[endpointName] {
+					add(RbacIssueWarning, where, "unreachable, no route of %s is a %s", resourceName, endpointName)
+				}
+				for _, issue := range permission.lint(surface) {
+					add(issue.Severity, where, issue.Message)
+				}
+			}
+		}
+	}
+
+	//every role should say what it may do on every route, a missing permission denies requests without saying so
+	if surface != nil {
+		for roleName := range rbac {
+			for resourceName, endpoints := range surface.Resources {
+				for endpointName := range endpoints {
+					if _, exists := rbac.Permission(roleName, resourceName, endpointName); !exists {
+						add(RbacIssueWarning, roleName+"."+resourceName+"."+endpointName, "missing, requests are denied")
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Severity != issues[j].Severity {
+			return issues[i].Severity == RbacIssueError
+		}
+		if issues[i].Where != issues[j].Where {
+			return issues[i].Where < issues[j].Where
+		}
+		return issues[i].Message < issues[j].Message
+	})
+	return issues
+}
+
+//declaresAny tells whether role declares some of the permissions on resource itself, rather than inheriting them all
+func declaresAny(resource Endpoint, role string) bool {
+	for _, permission := range resource {
+		if permission.DeclaredBy == "" || permission.DeclaredBy == role {
+			return true
+		}
+	}
+	return len(resource) == 0
+}
+
+//shadowedWildcard finds a wildcard entry declared by role itself that the inherited resource.endpoint entry,
+//being more specific, takes precedence over
+func (rbac RBAC) shadowedWildcard(role string, resource string, endpoint string) string {
+	var candidates [][2]string
+	if endpoint != RbacWildcard {
+		candidates = append(candidates, [2]string{resource, RbacWildcard})
+	}
+	if resource != RbacWildcard {
+		candidates = append(candidates, [2]string{RbacWildcard, endpoint})
+	}
+	candidates = append(candidates, [2]string{RbacWildcard, RbacWildcard})
+	for _, candidate := range candidates {
+		if candidate[0] == resource && candidate[1] == endpoint {
+			continue
+		}
+		permission, exists := rbac[role][candidate[0]][candidate[1]]
+		if exists && permission.DeclaredBy == role {
+			return candidate[0] + "." + candidate[1]
+		}
+	}
+	return ""
+}
+
+func (p Permission) lint(surface *RbacSurface) []RbacIssue {
+	var issues []RbacIssue
+	add := func(severity string, format string, args ...interface{}) {
+		issues = append(issues, RbacIssue{Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	checkRules := func(kind string, rules []Rule, needsOperator bool) {
+		for _, rule := range rules {
+			if rule.Key == "" {
+				add(RbacIssueError, "%s rule has no key", kind)
+				continue
+			}
+			if needsOperator && !IsKnownOperator(rule.Operator) {
+				add(RbacIssueError, "%s rule on %s has unknown operator '%s'", kind, rule.Key, rule.Operator)
+			}
+			if needsOperator {
+				for _, message := range rule.lintValue() {
+					add(RbacIssueError, "%s rule on %s %s", kind, rule.Key, message)
+				}
+				if rule.Operator == OperatorExists && (rule.Value != "" || len(rule.Values) > 0) {
+					add(RbacIssueWarning, "%s rule on %s checks that the value exists, its value is ignored", kind, rule.Key)
+				}
+			} else if len(rule.Values) > 0 {
+				add(RbacIssueError, "%s rule on %s sets a single value, not a list", kind, rule.Key)
+			}
+			if !strings.HasPrefix(rule.Value, "ctx") {
+				continue
+			}
+			if !ctxReferencePattern.MatchString(rule.Value) {
+				add(RbacIssueError, "%s rule on %s has malformed context reference '%s', expected ctx.<name>", kind, rule.Key, rule.Value)
+				continue
+			}
+			if surface != nil {
+				name := strings.Split(rule.Value, ".")[1]
+				if !surface.ContextKeys[name] {
+					add(RbacIssueError, "%s rule on %s refers to %s, which is never in the context", kind, rule.Key, rule.Value)
+				}
+			}
+		}
+	}
+	checkRules("ensure query", p.Ensure.Query, true)
+	checkRules("ensure header", p.Ensure.Header, true)
+	checkRules("ensure path", p.Ensure.Path, true)
+	checkRules("ensure body", p.Ensure.Body, true)
+	//enforced rules set a value, they have no operator
+	checkRules("enforce query", p.Enforce.Query, false)
+	checkRules("enforce header", p.Enforce.Header, false)
+	checkRules("enforce path", p.Enforce.Path, false)
+	checkRules("enforce body", p.Enforce.Body, false)
+
+	if len(p.Enforce.Path) > 0 {
+		add(RbacIssueWarning, "route parameters cannot be enforced, enforce path rules are ignored")
+	}
+	if len(p.Enforce.Body) > 0 {
+		add(RbacIssueWarning, "bodies cannot be enforced, enforce body rules are ignored")
+	}
+	issues = append(issues, p.Fields.lint()...)
+	if !p.Allow && (len(p.Ensure.Query)+len(p.Ensure.Header)+len(p.Ensure.Path)+len(p.Ensure.Body) > 0) {
+		add(RbacIssueWarning, "ensure rules of a denied permission are never checked")
+	}
+	return issues
+}
+
+//lintValue checks that a literal value suits the operator, context values are only known when requests come in
+func (rule Rule) lintValue() []string {
+	var messages []string
+	isList := len(rule.Values) > 0
+	isReference := strings.HasPrefix(rule.Value, "ctx")
+	switch {
+	case isList && !IsListOperator(rule.Operator):
+		messages = append(messages, fmt.Sprintf("has a list of values, only %s and %s take one", OperatorIn, OperatorNotIn))
+	case !isList && !isReference && IsListOperator(rule.Operator):
+		messages = append(messages, fmt.Sprintf("should have a list of values for %s", rule.Operator))
+	}
+	if isReference || isList {
+		return messages
+	}
+	switch rule.Operator {
+	case OperatorRegex:
+		if _, err := ruleRegexp(rule.Value); err != nil {
+			messages = append(messages, "has an "+err.Error())
+		}
+	case OperatorLess, OperatorLessOrEqual, OperatorGreater, OperatorGreaterOrEqual:
+		if _, err := asNumber(rule.Value); err != nil {
+			messages = append(messages, fmt.Sprintf("should have a number for %s, %s", rule.Operator, err.Error()))
+		}
+	}
+	return messages
+}
+
+//closestName suggests the name a typo was meant to be, if one is close enough
+func closestName(name string, names map[string]map[string]bool) string {
+	best, bestDistance := "", 3
+	for candidate := range names {
+		if distance := editDistance(name, candidate); distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+//editDistance is the Levenshtein distance between a and b
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+	return min
+}
@@ -1,14 +1,24 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
+	"strings"
 )
 
+//RbacWildcard stands for any resource, or any endpoint of a resource
+const RbacWildcard = "*"
+
 type Permission struct {
-	Allow   bool     `yaml:"allow"`
-	Ensure  Ensurer  `yaml:"ensure,omitempty"`
-	Enforce Enforcer `yaml:"enforce,omitempty"`
+	Allow   bool     `yaml:"allow" json:"allow"`
+	Ensure  Ensurer  `yaml:"ensure,omitempty" json:"ensure"`
+	Enforce Enforcer `yaml:"enforce,omitempty" json:"enforce"`
+	//Fields restricts the fields of the resource the role sees and writes, see RbacFields
+	Fields *RbacFields `yaml:"fields,omitempty" json:"fields,omitempty"`
+	//DeclaredBy is the role the permission is written under, another one than the role holding it when inherited
+	DeclaredBy string `yaml:"-" json:"-"`
 }
 
 type Endpoint map[string]Permission
@@ -17,17 +27,171 @@ type Resource map[string]Endpoint
 
 type RBAC map[string]Resource
 
+//RbacPolicy is the policy as written, before the inherited permissions are resolved into an RBAC
+type RbacPolicy map[string]RbacPolicyRole
+
+//RbacPolicyRole is a role as written in the policy, with the roles it inherits the permissions of next to its resources
+type RbacPolicyRole struct {
+	Inherits  []string `yaml:"inherits,omitempty" json:"inherits"`
+	Resources Resource `yaml:",inline" json:"resources"`
+}
+
 func RbacFromFile(path string) (RBAC, error) {
 	f, err := ioutil.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	return RbacFromBytes(f)
+}
 
-	rbac := RBAC{}
-	err = yaml.Unmarshal(f, rbac)
+//LintRbacFile parses the policy at path and lints it against the surface, see Lint
+func LintRbacFile(path string, surface *RbacSurface) ([]RbacIssue, error) {
+	f, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LintRbacBytes(f, surface)
+}
+
+//LintRbacBytes parses the policy and lints it against the surface, see Lint
+func LintRbacBytes(content []byte, surface *RbacSurface) ([]RbacIssue, error) {
+	rbac, err := ParseRbac(content)
+	if err != nil {
+		return nil, err
+	}
+	return rbac.Lint(surface), nil
+}
+
+//RbacFromBytes parses and validates a policy, the YAML being the content of a role-based access file
+func RbacFromBytes(content []byte) (RBAC, error) {
+	return RbacFromBytesForSurface(content, nil)
+}
+
+//RbacFromBytesForSurface parses a policy and validates it against the surface the routes expose, see ValidateSurface
+func RbacFromBytesForSurface(content []byte, surface *RbacSurface) (RBAC, error) {
+	rbac, err := ParseRbac(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rbac.ValidateSurface(surface); err != nil {
+		return nil, err
+	}
+	return rbac, nil
+}
+
+//ParseRbac only parses a policy and resolves the inherited permissions, see Validate and Lint
+func ParseRbac(content []byte) (RBAC, error) {
+	policy, err := ParseRbacPolicy(content)
 	if err != nil {
 		return nil, err
 	}
+	return policy.Resolve()
+}
 
-	return rbac, err
+//ParseRbacPolicy parses a policy as written
+func ParseRbacPolicy(content []byte) (RbacPolicy, error) {
+	policy := RbacPolicy{}
+	err := yaml.UnmarshalStrict(content, policy)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+//Marshal writes the policy back as YAML, ParseRbacPolicy reads it the same
+func (policy RbacPolicy) Marshal() ([]byte, error) {
+	return yaml.Marshal(policy)
+}
+
+//Resolve copies into every role the permissions of the roles it inherits, its own permissions override them,
+//then those of the roles listed first
+func (policy RbacPolicy) Resolve() (RBAC, error) {
+	rbac := RBAC{}
+	var resolve func(name string, path []string) (Resource, error)
+	resolve = func(name string, path []string) (Resource, error) {
+		if resolved, done := rbac[name]; done {
+			return resolved, nil
+		}
+		for i, visiting := range path {
+			if visiting == name {
+				return nil, fmt.Errorf("role %s inherits itself: %s > %s", name, strings.Join(path[i:], " > "), name)
+			}
+		}
+		role, exists := policy[name]
+		if !exists {
+			return nil, fmt.Errorf("role %s inherits unknown role '%s'", path[len(path)-1], name)
+		}
+
+		resolved := Resource{}
+		for resourceName, endpoints := range role.Resources {
+			resolved[resourceName] = Endpoint{}
+			for endpointName, permission := range endpoints {
+				permission.DeclaredBy = name
+				resolved[resourceName][endpointName] = permission
+			}
+		}
+		path = append(append([]string{}, path...), name)
+		for _, parentName := range role.Inherits {
+			parent, err := resolve(parentName, path)
+			if err != nil {
+				return nil, err
+			}
+			for resourceName, endpoints := range parent {
+				if resolved[resourceName] == nil {
+					resolved[resourceName] = Endpoint{}
+				}
+				for endpointName, permission := range endpoints {
+					if _, exists := resolved[resourceName][endpointName]; !exists {
+						resolved[resourceName][endpointName] = permission
+					}
+				}
+			}
+		}
+		rbac[name] = resolved
+		return resolved, nil
+	}
+
+	//in order, so a broken policy always reports the same error
+	for _, name := range policy.names() {
+		if _, err := resolve(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return rbac, nil
+}
+
+//Permission looks up what role may do on endpoint of resource. The most specific entry applies:
+//resource.endpoint, then resource.*, then *.endpoint, then *.*
+func (rbac RBAC) Permission(role string, resource string, endpoint string) (Permission, bool) {
+	permission, _, exists := rbac.PermissionEntry(role, resource, endpoint)
+	return permission, exists
+}
+
+//PermissionEntry is Permission, along with the resource.endpoint entry that applies
+func (rbac RBAC) PermissionEntry(role string, resource string, endpoint string) (Permission, string, bool) {
+	for _, resourceName := range []string{resource, RbacWildcard} {
+		for _, endpointName := range []string{endpoint, RbacWildcard} {
+			if permission, exists := rbac[role][resourceName][endpointName]; exists {
+				return permission, resourceName + "." + endpointName, true
+			}
+		}
+	}
+	return Permission{}, "", false
+}
+
+//Validate catches the mistakes that would make the policy deny or allow requests by accident, returning the first one.
+//It does not know the routes, ValidateSurface does.
+func (rbac RBAC) Validate() error {
+	return rbac.ValidateSurface(nil)
+}
+
+//ValidateSurface returns the first error Lint finds against the surface, such as a resource no route maps to
+func (rbac RBAC) ValidateSurface(surface *RbacSurface) error {
+	for _, issue := range rbac.Lint(surface) {
+		if issue.Severity == RbacIssueError {
+			return errors.New(issue.String())
+		}
+	}
+	return nil
 }
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"fmt"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	RbacPolicyRepo RbacPolicyRepoInterface = &rbacPolicyRepo{}
+)
+
+type RbacPolicyRepoInterface interface {
+	//GetRoles returns every role along with its resources, actions and rules
+	GetRoles() ([]RbacRole, errorUtils.EntityError)
+	//GetLatestVersion returns the snapshot of the policy in the tables, not found when the policy was never stored
+	GetLatestVersion() (*RbacPolicyVersion, errorUtils.EntityError)
+	GetVersions() ([]RbacPolicyVersion, errorUtils.EntityError)
+	GetVersion(version uint64) (*RbacPolicyVersion, errorUtils.EntityError)
+	//Save replaces the policy in the tables with roles and records version as its snapshot, all at once.
+	//It is a conflict when version is not the one following the latest one, the policy was changed meanwhile.
+	Save(roles []RbacRole, version *RbacPolicyVersion) errorUtils.EntityError
+	Initialize(*gorm.DB)
+}
+
+type rbacPolicyRepo struct {
+	db *gorm.DB
+}
+
+func NewRbacPolicyRepository(db *gorm.DB) RbacPolicyRepoInterface {
+	return &rbacPolicyRepo{db: db}
+}
+
+func (r *rbacPolicyRepo) Initialize(db *gorm.DB) {
+	r.db = db
+	db.AutoMigrate(&RbacRole{}, &RbacResource{}, &RbacAction{}, &RbacRule{}, &RbacPolicyVersion{})
+}
+
+func (r *rbacPolicyRepo) GetRoles() ([]RbacRole, errorUtils.EntityError) {
+	roles := []RbacRole{}
+	err := r.db.Preload("Resources").Preload("Resources.Actions").Preload("Resources.Actions.Rules").
+		Order("name").Find(&roles).Error
+	if err != nil {
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return roles, nil
+}
+
+func (r *rbacPolicyRepo) GetLatestVersion() (*RbacPolicyVersion, errorUtils.EntityError) {
+	var version RbacPolicyVersion
+	if err := r.db.Order("version desc").First(&version).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errorUtils.NewNotFoundError(err.Error())
+		}
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return &version, nil
+}
+
+//GetVersions lists the snapshots, latest first, without their content
+func (r *rbacPolicyRepo) GetVersions() ([]RbacPolicyVersion, errorUtils.EntityError) {
+	versions := []RbacPolicyVersion{}
+	err := r.db.Select("id, version, created_at, author_id, comment, hash").Order("version desc").Find(&versions).Error
+	if err != nil {
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return versions, nil
+}
+
+func (r *rbacPolicyRepo) GetVersion(version uint64) (*RbacPolicyVersion, errorUtils.EntityError) {
+	var ret RbacPolicyVersion
+	if err := r.db.Where("version = ?", version).First(&ret).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errorUtils.NewNotFoundError(err.Error())
+		}
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return &ret, nil
+}
+
+func (r *rbacPolicyRepo) Save(roles []RbacRole, version *RbacPolicyVersion) errorUtils.EntityError {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return errorUtils.NewInternalServerError(tx.Error.Error())
+	}
+	err := r.save(tx, roles, version)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return errorUtils.NewInternalServerError(err.Error())
+	}
+	return nil
+}
+
+func (r *rbacPolicyRepo) save(tx *gorm.DB, roles []RbacRole, version *RbacPolicyVersion) errorUtils.EntityError {
+	var latest struct{ Version uint64 }
+	if err := tx.Model(&RbacPolicyVersion{}).Select("coalesce(max(version), 0) as version").Scan(&latest).Error; err != nil {
+		return errorUtils.NewInternalServerError(err.Error())
+	}
+	if version.Version != latest.Version+1 {
+		return errorUtils.NewConflictError(fmt.Sprintf("rbac policy is at version %d, it was changed meanwhile", latest.Version))
+	}
+
+	for _, model := range []interface{}{&RbacRule{}, &RbacAction{}, &RbacResource{}, &RbacRole{}} {
+		if err := tx.Delete(model).Error; err != nil {
+			return errorUtils.NewInternalServerError(err.Error())
+		}
+	}
+	//the resources, actions and rules of a role are created along with it
+	for i := range roles {
+		if err := tx.Create(&roles[i]).Error; err != nil {
+			return errorUtils.NewInternalServerError(err.Error())
+		}
+	}
+	if err := tx.Create(version).Error; err != nil {
+		return errorUtils.NewInternalServerError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	RbacRuleEnsure  = "ensure"
+	RbacRuleEnforce = "enforce"
+)
+
+//RbacRole is a role of the policy stored in the database, with the resources it has permissions on.
+//Roles, resources, actions and rules are the tables behind an RbacPolicy, see RbacPolicyFromRoles.
+type RbacRole struct {
+	ID        uint64         `gorm:"primary_key" json:"id"`
+	Name      string         `gorm:"column:name;not null;unique_index" json:"name"`
+	Inherits  RbacRoleNames  `gorm:"column:inherits;type:varchar(255)" json:"inherits"`
+	Resources []RbacResource `gorm:"foreignkey:RoleID" json:"resources"`
+}
+
+type RbacResource struct {
+	ID      uint64       `gorm:"primary_key" json:"id"`
+	RoleID  uint64       `gorm:"column:role_id;not null;index" json:"role_id"`
+	Name    string       `gorm:"column:name;not null" json:"name"`
+	Actions []RbacAction `gorm:"foreignkey:ResourceID" json:"actions"`
+}
+
+//RbacAction is the permission of a role on an endpoint of a resource, with the fields it restricts
+type RbacAction struct {
+	ID             uint64         `gorm:"primary_key" json:"id"`
+	ResourceID     uint64         `gorm:"column:resource_id;not null;index" json:"resource_id"`
+	Name           string         `gorm:"column:name;not null" json:"name"`
+	Allow          bool           `gorm:"column:allow;not null;default:0" json:"allow"`
+	Rules          []RbacRule     `gorm:"foreignkey:ActionID" json:"rules"`
+	VisibleFields  RbacRuleValues `gorm:"column:visible_fields;type:varchar(1024)" json:"visible_fields"`
+	OwnerField     string         `gorm:"column:owner_field" json:"owner_field"`
+	OwnFields      RbacRuleValues `gorm:"column:own_fields;type:varchar(1024)" json:"own_fields"`
+	WritableFields RbacRuleValues `gorm:"column:writable_fields;type:varchar(1024)" json:"writable_fields"`
+}
+
+//RbacRule is an ensure or enforce rule on the query, header, path or body, Position keeps them in the order they are written.
+//The columns are prefixed, key and values being reserved words in SQL.
+type RbacRule struct {
+	ID       uint64         `gorm:"primary_key" json:"id"`
+	ActionID uint64         `gorm:"column:action_id;not null;index" json:"action_id"`
+	Position int            `gorm:"column:position;not null" json:"position"`
+	Kind     string         `gorm:"column:kind;not null" json:"kind"`
+	Target   string         `gorm:"column:target;not null" json:"target"`
+	Key      string         `gorm:"column:rule_key;not null" json:"key"`
+	Operator string         `gorm:"column:operator" json:"operator"`
+	Value    string         `gorm:"column:rule_value" json:"value"`
+	Values   RbacRuleValues `gorm:"column:rule_values;type:varchar(1024)" json:"values"`
+}
+
+//RbacPolicyVersion is a snapshot of the policy, taken every time it changes so it can be rolled back to
+type RbacPolicyVersion struct {
+	ID        uint64    `gorm:"primary_key" json:"id"`
+	Version   uint64    `gorm:"column:version;not null;unique_index" json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	//AuthorId is the user who made the change, 0 for the import of the policy file
+	AuthorId uint64 `gorm:"column:author_id" json:"author_id"`
+	Comment  string `gorm:"column:comment" json:"comment"`
+	Hash     string `gorm:"column:hash" json:"hash"`
+	//Content is the policy as YAML, left out of version lists
+	Content string `gorm:"column:content;type:text" json:"content,omitempty"`
+}
+
+//RbacRoleNames are stored as a comma-separated column, role names cannot contain commas
+type RbacRoleNames []string
+
+func (n RbacRoleNames) Value() (driver.Value, error) {
+	return strings.Join(n, ","), nil
+}
+
+func (n *RbacRoleNames) Scan(value interface{}) error {
+	raw, err := scanString(value)
+	if err != nil {
+		return fmt.Errorf("cannot scan %T into role names", value)
+	}
+	*n = RbacRoleNames{}
+	for _, name := range strings.Split(raw, ",") {
+		if name != "" {
+			*n = append(*n, name)
+		}
+	}
+	return nil
+}
+
+//RbacRuleValues are stored as a JSON array, values being free text. So are the field names of an action.
+type RbacRuleValues []string
+
+func (v RbacRuleValues) Value() (driver.Value, error) {
+	if len(v) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal([]string(v))
+	return string(encoded), err
+}
+
+func (v *RbacRuleValues) Scan(value interface{}) error {
+	raw, err := scanString(value)
+	if err != nil {
+		return fmt.Errorf("cannot scan %T into rule values", value)
+	}
+	*v = nil
+	if raw == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(raw), (*[]string)(v))
+}
+
+func scanString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+	return "", fmt.Errorf("cannot scan %T into a string", value)
+}
+
+//RbacRolesFromPolicy lays the policy out as rows, in name order so the same policy always gives the same rows
+func RbacRolesFromPolicy(policy RbacPolicy) []RbacRole {
+	roles := make([]RbacRole, 0, len(policy))
+	for _, roleName := range policy.names() {
+		role := policy[roleName]
+		row := RbacRole{Name: roleName, Inherits: append(RbacRoleNames{}, role.Inherits...)}
+		for _, resourceName := range role.Resources.names() {
+			resource := RbacResource{Name: resourceName}
+			endpoints := role.Resources[resourceName]
+			for _, actionName := range endpoints.names() {
+				permission := endpoints[actionName]
+				action := RbacAction{Name: actionName, Allow: permission.Allow}
+				if fields := permission.Fields; fields != nil {
+					action.VisibleFields, action.OwnerField = fields.Visible, fields.Owner
+					action.OwnFields, action.WritableFields = fields.Own, fields.Writable
+				}
+				addRules := func(kind string, target string, rules []Rule) {
+					for _, rule := range rules {
+						action.Rules = append(action.Rules, RbacRule{
+							Position: len(action.Rules),
+							Kind:     kind,
+							Target:   target,
+							Key:      rule.Key,
+							Operator: rule.Operator,
+							Value:    rule.Value,
+							Values:   rule.Values,
+						})
+					}
+				}
+				addRules(RbacRuleEnsure, "query", permission.Ensure.Query)
+				addRules(RbacRuleEnsure, "header", permission.Ensure.Header)
+				addRules(RbacRuleEnsure, "path", permission.Ensure.Path)
+				addRules(RbacRuleEnsure, "body", permission.Ensure.Body)
+				addRules(RbacRuleEnforce, "query", permission.Enforce.Query)
+				addRules(RbacRuleEnforce, "header", permission.Enforce.Header)
+				addRules(RbacRuleEnforce, "path", permission.Enforce.Path)
+				addRules(RbacRuleEnforce, "body", permission.Enforce.Body)
+				resource.Actions = append(resource.Actions, action)
+			}
+			row.Resources = append(row.Resources, resource)
+		}
+		roles = append(roles, row)
+	}
+	return roles
+}
+
+//RbacPolicyFromRoles puts the rows back together as a policy
+func RbacPolicyFromRoles(roles []RbacRole) (RbacPolicy, error) {
+	policy := RbacPolicy{}
+	for _, row := range roles {
+		role := RbacPolicyRole{Resources: Resource{}}
+		if len(row.Inherits) > 0 {
+			role.Inherits = append([]string{}, row.Inherits...)
+		}
+		for _, resource := range row.Resources {
+			endpoints := Endpoint{}
+			for _, action := range resource.Actions {
+				permission := Permission{Allow: action.Allow}
+				if len(action.VisibleFields)+len(action.OwnFields)+len(action.WritableFields) > 0 || action.OwnerField != "" {
+					permission.Fields = &RbacFields{
+						Visible:  fieldNames(action.VisibleFields),
+						Owner:    action.OwnerField,
+						Own:      fieldNames(action.OwnFields),
+						Writable: fieldNames(action.WritableFields),
+					}
+				}
+				rules := append([]RbacRule{}, action.Rules...)
+				sort.SliceStable(rules, func(i, j int) bool { return rules[i].Position < rules[j].Position })
+				for _, rule := range rules {
+					ruler := &permission.Ensure
+					if rule.Kind == RbacRuleEnforce {
+						ruler = (*Ensurer)(&permission.Enforce)
+					} else if rule.Kind != RbacRuleEnsure {
+						return nil, fmt.Errorf("%s.%s.%s: rule on %s has unknown kind '%s'", row.Name, resource.Name, action.Name, rule.Key, rule.Kind)
+					}
+					target, err := ruler.target(rule.Target)
+					if err != nil {
+						return nil, fmt.Errorf("%s.%s.%s: rule on %s %s", row.Name, resource.Name, action.Name, rule.Key, err.Error())
+					}
+					var values []string
+					if len(rule.Values) > 0 {
+						values = append(values, rule.Values...)
+					}
+					*target = append(*target, Rule{Key: rule.Key, Operator: rule.Operator, Value: rule.Value, Values: values})
+				}
+				endpoints[action.Name] = permission
+			}
+			role.Resources[resource.Name] = endpoints
+		}
+		policy[row.Name] = role
+	}
+	return policy, nil
+}
+
+//fieldNames copies the names, nil when there are none as in a policy parsed from YAML
+func fieldNames(names RbacRuleValues) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	return append([]string{}, names...)
+}
+
+//target is the list of rules on the query, header, path or body
+func (ens *Ensurer) target(name string) (*[]Rule, error) {
+	switch name {
+	case "query":
+		return &ens.Query, nil
+	case "header":
+		return &ens.Header, nil
+	case "path":
+		return &ens.Path, nil
+	case "body":
+		return &ens.Body, nil
+	}
+	return nil, fmt.Errorf("has unknown target '%s'", name)
+}
+
+//names of the roles, in order
+func (policy RbacPolicy) names() []string {
+	names := make([]string, 0, len(policy))
+	for name := range policy {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//names of the resources, in order
+func (r Resource) names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//names of the endpoints, in order
+func (e Endpoint) names() []string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -2,31 +2,353 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 )
 
+const (
+	OperatorEqual          = "="
+	OperatorNotEqual       = "!="
+	OperatorIn             = "in"
+	OperatorNotIn          = "not in"
+	OperatorLess           = "<"
+	OperatorLessOrEqual    = "<="
+	OperatorGreater        = ">"
+	OperatorGreaterOrEqual = ">="
+	OperatorRegex          = "regex"
+	OperatorPrefix         = "prefix"
+	OperatorExists         = "exists"
+)
+
+//compiled regex rules, patterns come from the policy so there are only so many of them
+var ruleRegexps sync.Map
+
+//Rule checks the value found under Key against Value, which is either a literal or a context reference (ctx.<name>).
+//In the YAML, value can also be a list, for the in and not in operators, which is then held by Values.
 type Rule struct {
-	Key      string `yaml:"key"`
-	Operator string `yaml:"operator"`
-	Value    string `yaml:"value"`
+	Key      string   `yaml:"key" json:"key"`
+	Operator string   `yaml:"operator" json:"operator,omitempty"`
+	Value    string   `yaml:"value" json:"value,omitempty"`
+	Values   []string `yaml:"-" json:"values,omitempty"`
+}
+
+//UnmarshalYAML accepts a scalar or a list of scalars as value
+func (rule *Rule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw struct {
+		Key      string      `yaml:"key"`
+		Operator string      `yaml:"operator"`
+		Value    interface{} `yaml:"value"`
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	rule.Key, rule.Operator, rule.Value, rule.Values = raw.Key, raw.Operator, "", nil
+
+	switch value := raw.Value.(type) {
+	case nil:
+	case []interface{}:
+		rule.Values = make([]string, 0, len(value))
+		for _, item := range value {
+			if !isScalar(item) {
+				return fmt.Errorf("rule on %s: list values should be scalars, got %v", raw.Key, item)
+			}
+			rule.Values = append(rule.Values, fmt.Sprint(item))
+		}
+	default:
+		if !isScalar(value) {
+			return fmt.Errorf("rule on %s: value should be a scalar or a list of scalars, got %v", raw.Key, value)
+		}
+		rule.Value = fmt.Sprint(value)
+	}
+	return nil
+}
+
+//MarshalYAML writes Values back as the list value UnmarshalYAML reads them from
+func (rule Rule) MarshalYAML() (interface{}, error) {
+	raw := struct {
+		Key      string      `yaml:"key"`
+		Operator string      `yaml:"operator,omitempty"`
+		Value    interface{} `yaml:"value,omitempty"`
+	}{Key: rule.Key, Operator: rule.Operator}
+	if len(rule.Values) > 0 {
+		raw.Value = rule.Values
+	} else if rule.Value != "" {
+		raw.Value = rule.Value
+	}
+	return raw, nil
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return true
+	}
+	return false
+}
+
+//IsKnownOperator tells whether Comply understands the operator, an unknown one never complies
+func IsKnownOperator(operator string) bool {
+	switch operator {
+	case OperatorEqual, OperatorNotEqual, OperatorIn, OperatorNotIn,
+		OperatorLess, OperatorLessOrEqual, OperatorGreater, OperatorGreaterOrEqual,
+		OperatorRegex, OperatorPrefix, OperatorExists:
+		return true
+	}
+	return false
+}
+
+//IsListOperator tells whether the operator expects a list of values
+func IsListOperator(operator string) bool {
+	return operator == OperatorIn || operator == OperatorNotIn
 }
 
 //Strongly inspired from https://dev.to/bastianrob/rbac-in-rest-api-using-go-5gg0
+//Comply is Check without the reason, a value that cannot be compared does not comply
 func (rule Rule) Comply(expected, actual interface{}) bool {
+	complies, err := rule.Check(expected, actual)
+	return err == nil && complies
+}
+
+//Check tells whether actual complies with expected. Values of different types are coerced before being compared,
+//so the string "3" equals the number 3, and an error tells when they cannot be.
+func (rule Rule) Check(expected, actual interface{}) (bool, error) {
 	switch rule.Operator {
-	case "!=":
-		return !reflect.DeepEqual(expected, actual)
-	case "=":
-		return reflect.DeepEqual(expected, actual)
+	case OperatorEqual:
+		return valuesEqual(expected, actual)
+	case OperatorNotEqual:
+		equal, err := valuesEqual(expected, actual)
+		return !equal, err
+	case OperatorIn:
+		return valueIn(expected, actual)
+	case OperatorNotIn:
+		in, err := valueIn(expected, actual)
+		return !in, err
+	case OperatorLess, OperatorLessOrEqual, OperatorGreater, OperatorGreaterOrEqual:
+		return rule.compareNumbers(expected, actual)
+	case OperatorRegex:
+		pattern, err := ruleRegexp(fmt.Sprint(expected))
+		if err != nil {
+			return false, err
+		}
+		actualString, err := asString(actual)
+		if err != nil {
+			return false, err
+		}
+		return pattern.MatchString(actualString), nil
+	case OperatorPrefix:
+		actualString, err := asString(actual)
+		if err != nil {
+			return false, err
+		}
+		return strings.HasPrefix(actualString, fmt.Sprint(expected)), nil
+	case OperatorExists:
+		return !isAbsent(actual), nil
 	}
 
 	// doesn't comply if we don't recognize the rule operator
+	return false, fmt.Errorf("unknown operator '%s'", rule.Operator)
+}
+
+func (rule Rule) compareNumbers(expected, actual interface{}) (bool, error) {
+	expectedNumber, err := asNumber(expected)
+	if err != nil {
+		return false, err
+	}
+	actualNumber, err := asNumber(actual)
+	if err != nil {
+		return false, err
+	}
+	cmp := actualNumber.Cmp(expectedNumber)
+	switch rule.Operator {
+	case OperatorLess:
+		return cmp < 0, nil
+	case OperatorLessOrEqual:
+		return cmp <= 0, nil
+	case OperatorGreater:
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+func ruleRegexp(pattern string) (*regexp.Regexp, error) {
+	if compiled, exists := ruleRegexps.Load(pattern); exists {
+		return compiled.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex '%s': %s", pattern, err.Error())
+	}
+	ruleRegexps.Store(pattern, compiled)
+	return compiled, nil
+}
+
+//valuesEqual compares strings as strings, and coerces a string compared to a number or a boolean
+func valuesEqual(expected, actual interface{}) (bool, error) {
+	if isAbsent(expected) || isAbsent(actual) {
+		return isAbsent(expected) && isAbsent(actual), nil
+	}
+	expectedString, expectedIsString := expected.(string)
+	actualString, actualIsString := actual.(string)
+	switch {
+	case expectedIsString && actualIsString:
+		return expectedString == actualString, nil
+	case isNumber(expected) || isNumber(actual):
+		expectedNumber, err := asNumber(expected)
+		if err != nil {
+			return false, err
+		}
+		actualNumber, err := asNumber(actual)
+		if err != nil {
+			return false, err
+		}
+		return expectedNumber.Cmp(actualNumber) == 0, nil
+	case isBool(expected) || isBool(actual):
+		expectedBool, err := asBool(expected)
+		if err != nil {
+			return false, err
+		}
+		actualBool, err := asBool(actual)
+		if err != nil {
+			return false, err
+		}
+		return expectedBool == actualBool, nil
+	}
+	return reflect.DeepEqual(expected, actual), nil
+}
+
+//valueIn looks for actual in the expected list. An item that cannot be compared is skipped,
+//its error is only returned when no item matches and no item could be compared.
+func valueIn(expected, actual interface{}) (bool, error) {
+	list := reflect.ValueOf(expected)
+	if expected == nil || (list.Kind() != reflect.Slice && list.Kind() != reflect.Array) {
+		return false, fmt.Errorf("expected a list of values, got '%v'", expected)
+	}
+	var firstErr error
+	compared := false
+	for i := 0; i < list.Len(); i++ {
+		equal, err := valuesEqual(list.Index(i).Interface(), actual)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		compared = true
+		if equal {
+			return true, nil
+		}
+	}
+	if !compared && firstErr != nil {
+		return false, firstErr
+	}
+	return false, nil
+}
+
+func isAbsent(value interface{}) bool {
+	return value == nil || value == ""
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case json.Number, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
 	return false
 }
 
+func isBool(value interface{}) bool {
+	_, ok := value.(bool)
+	return ok
+}
+
+//asNumber is exact for integers of any size, big.Rat never rounds an id
+func asNumber(value interface{}) (*big.Rat, error) {
+	switch v := value.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int8:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int16:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int32:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case int64:
+		return new(big.Rat).SetInt64(v), nil
+	case uint:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(uint64(v))), nil
+	case uint8:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case uint16:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case uint32:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	case uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(v)), nil
+	case float32:
+		return ratFromFloat(float64(v), value)
+	case float64:
+		return ratFromFloat(v, value)
+	case json.Number:
+		return asNumber(string(v))
+	case string:
+		//decimal only, 010 is ten and not an octal eight
+		text := strings.TrimSpace(v)
+		if integer, ok := new(big.Int).SetString(text, 10); ok {
+			return new(big.Rat).SetInt(integer), nil
+		}
+		if float, err := strconv.ParseFloat(text, 64); err == nil && !strings.ContainsAny(text, "xXpPnN") {
+			return ratFromFloat(float, value)
+		}
+		return nil, fmt.Errorf("cannot compare '%s' as a number", v)
+	}
+	return nil, fmt.Errorf("cannot compare '%v' (%T) as a number", value, value)
+}
+
+func ratFromFloat(float float64, value interface{}) (*big.Rat, error) {
+	number := new(big.Rat).SetFloat64(float)
+	if number == nil {
+		return nil, fmt.Errorf("cannot compare '%v' as a number", value)
+	}
+	return number, nil
+}
+
+func asBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			return parsed, nil
+		}
+	}
+	return false, fmt.Errorf("cannot compare '%v' as a boolean", value)
+}
+
+func asString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	}
+	if isNumber(value) || isBool(value) {
+		return fmt.Sprint(value), nil
+	}
+	return "", fmt.Errorf("cannot compare '%v' (%T) as a string", value, value)
+}
+
+//FromContext resolves the expected value: the list of values, the literal value, or the context value it refers to
 func (rule Rule) FromContext(ctx context.Context) (interface{}, error) {
+	if len(rule.Values) > 0 {
+		return rule.Values, nil
+	}
 	if !strings.HasPrefix(rule.Value, "ctx") {
 		return rule.Value, nil
 	}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	//RbacOutcomeGranted means the role's permission allows the request and its ensure rules hold
+	RbacOutcomeGranted = "granted"
+	//RbacOutcomeDenied means the role's permission says allow: false
+	RbacOutcomeDenied = "denied"
+	//RbacOutcomeViolated means one of the role's ensure rules does not hold, checking stops at the first one
+	RbacOutcomeViolated = "violated"
+	//RbacOutcomeNoPermission means the role says nothing about the endpoint, not even through a wildcard
+	RbacOutcomeNoPermission = "no permission"
+	//RbacOutcomeSkipped means the decision was made before getting to the role
+	RbacOutcomeSkipped = "skipped"
+)
+
+//RbacTrace records how the authorization service came to its decision, see AuthorizationService.Explain
+type RbacTrace struct {
+	Resource string          `json:"resource"`
+	Endpoint string          `json:"endpoint"`
+	Strategy RoleStrategy    `json:"strategy"`
+	Roles    []RbacRoleTrace `json:"roles"`
+	//Enforced are the values set by the enforce rules of the role granting access
+	Enforced []RbacRuleCheck `json:"enforced"`
+	Allowed  bool            `json:"allowed"`
+	//Role granted access, empty when access was refused
+	Role  string `json:"role,omitempty"`
+	Error string `json:"error,omitempty"`
+
+	//checks recorded since the last TakeChecks
+	checks []RbacRuleCheck
+}
+
+//RbacRoleTrace is how one of the user's roles was evaluated
+type RbacRoleTrace struct {
+	Role string `json:"role"`
+	//Entry is the resource.endpoint entry of the policy that applied, possibly a wildcard one
+	Entry string `json:"entry,omitempty"`
+	//DeclaredBy is the role the entry comes from, when inherited
+	DeclaredBy string          `json:"declared_by,omitempty"`
+	Allow      bool            `json:"allow"`
+	Checks     []RbacRuleCheck `json:"checks"`
+	Outcome    string          `json:"outcome"`
+	Error      string          `json:"error,omitempty"`
+}
+
+//RbacRuleCheck is one ensure rule checked, one enforce rule applied, or one field of the body checked against the writable fields
+type RbacRuleCheck struct {
+	//Kind is ensure, enforce or writable, Target is query, header, path or body
+	Kind     string `json:"kind"`
+	Target   string `json:"target"`
+	Key      string `json:"key"`
+	Operator string `json:"operator,omitempty"`
+	//Value is the rule's value as written in the policy, Expected what it resolved to
+	Value    string      `json:"value"`
+	Expected interface{} `json:"expected"`
+	//Actual is the value found in the request, the one replaced for enforce rules
+	Actual   interface{} `json:"actual"`
+	Complies bool        `json:"complies"`
+	Error    string      `json:"error,omitempty"`
+}
+
+//WithRbacTrace has the rules checked with ctx recorded into trace
+func WithRbacTrace(ctx context.Context, trace *RbacTrace) context.Context {
+	return context.WithValue(ctx, RbacTraceKey(), trace)
+}
+
+//RbacTraceFromContext returns the trace to record into, nil for the requests that are only authorized
+func RbacTraceFromContext(ctx context.Context) *RbacTrace {
+	trace, _ := ctx.Value(RbacTraceKey()).(*RbacTrace)
+	return trace
+}
+
+//AddRole records the evaluation of a role, nothing is recorded without a trace
+func (t *RbacTrace) AddRole(role RbacRoleTrace) {
+	if t != nil {
+		t.Roles = append(t.Roles, role)
+	}
+}
+
+//TakeChecks returns the rules checked since it was last called
+func (t *RbacTrace) TakeChecks() []RbacRuleCheck {
+	if t == nil {
+		return nil
+	}
+	checks := t.checks
+	t.checks = nil
+	if checks == nil {
+		checks = []RbacRuleCheck{}
+	}
+	return checks
+}
+
+//traceRule records a rule checked with ctx, if it carries a trace
+func traceRule(ctx context.Context, kind string, target string, rule Rule, expected interface{}, actual interface{}, err error) {
+	trace := RbacTraceFromContext(ctx)
+	if trace == nil {
+		return
+	}
+	check := RbacRuleCheck{
+		Kind:     kind,
+		Target:   target,
+		Key:      rule.Key,
+		Operator: rule.Operator,
+		Value:    rule.Value,
+		Expected: expected,
+		Actual:   actual,
+		Complies: err == nil,
+	}
+	if len(rule.Values) > 0 {
+		check.Value = "[" + strings.Join(rule.Values, ", ") + "]"
+	}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	trace.checks = append(trace.checks, check)
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+	"sync"
+	"time"
+)
+
+var (
+	RefreshTokenRepo = NewRefreshTokenRepository()
+)
+
+type RefreshTokenRepoInterface interface {
+	Get(key string) (*RefreshToken, errorUtils.EntityError)
+	Create(token *RefreshToken) (*RefreshToken, errorUtils.EntityError)
+	//MarkUsed flags the token as used, and returns false if it already was. This is the reuse detection point,
+	//so it must be atomic: two concurrent refreshes with the same token cannot both succeed.
+	MarkUsed(key string, at time.Time) (bool, errorUtils.EntityError)
+	DeleteByFamily(familyId string) (int64, errorUtils.EntityError)
+	DeleteByUserID(userId uint64) (int64, errorUtils.EntityError)
+	DeleteExpired(now time.Time) (int64, errorUtils.EntityError)
+	Initialize(*gorm.DB)
+}
+
+//refreshTokenRepo keeps the refresh tokens in memory, alongside the in-memory session store
+type refreshTokenRepo struct {
+	mutex sync.Mutex
+	repo  map[string]*RefreshToken
+}
+
+func NewRefreshTokenRepository() RefreshTokenRepoInterface {
+	return &refreshTokenRepo{repo: map[string]*RefreshToken{}}
+}
+
+func (r *refreshTokenRepo) Initialize(db *gorm.DB) {}
+
+func (r *refreshTokenRepo) Get(key string) (*RefreshToken, errorUtils.EntityError) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	token := r.repo[key]
+	if token == nil {
+		return nil, errorUtils.NewNotFoundError("refresh token does not exist")
+	}
+	//hand out a copy, so callers cannot change the stored token without going through the repository
+	ret := *token
+	return &ret, nil
+}
+
+func (r *refreshTokenRepo) Create(token *RefreshToken) (*RefreshToken, errorUtils.EntityError) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	stored := *token
+	r.repo[token.Token] = &stored
+	return token, nil
+}
+
+func (r *refreshTokenRepo) MarkUsed(key string, at time.Time) (bool, errorUtils.EntityError) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	token := r.repo[key]
+	if token == nil {
+		return false, errorUtils.NewNotFoundError("refresh token does not exist")
+	}
+	if token.UsedAt != nil {
+		return false, nil
+	}
+	token.UsedAt = &at
+	return true, nil
+}
+
+func (r *refreshTokenRepo) DeleteByFamily(familyId string) (int64, errorUtils.EntityError) {
+	return r.deleteWhere(func(token *RefreshToken) bool { return token.FamilyId == familyId }), nil
+}
+
+func (r *refreshTokenRepo) DeleteByUserID(userId uint64) (int64, errorUtils.EntityError) {
+	return r.deleteWhere(func(token *RefreshToken) bool { return token.UserId == userId }), nil
+}
+
+func (r *refreshTokenRepo) DeleteExpired(now time.Time) (int64, errorUtils.EntityError) {
+	return r.deleteWhere(func(token *RefreshToken) bool { return token.ExpiresAt < now.UnixNano() }), nil
+}
+
+func (r *refreshTokenRepo) deleteWhere(matches func(*RefreshToken) bool) int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var deleted int64
+	for key, token := range r.repo {
+		if matches(token) {
+			delete(r.repo, key)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+//refreshTokenDBRepo keeps the refresh tokens in the refresh_tokens table, alongside the database session store
+type refreshTokenDBRepo struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenDBRepository(db *gorm.DB) RefreshTokenRepoInterface {
+	return &refreshTokenDBRepo{db: db}
+}
+
+func (r *refreshTokenDBRepo) Initialize(db *gorm.DB) {
+	r.db = db
+	db.AutoMigrate(&RefreshToken{})
+}
+
+func (r *refreshTokenDBRepo) Get(key string) (*RefreshToken, errorUtils.EntityError) {
+	var token RefreshToken
+	if err := r.db.Where("token = ?", key).First(&token).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errorUtils.NewNotFoundError("refresh token does not exist")
+		}
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenDBRepo) Create(token *RefreshToken) (*RefreshToken, errorUtils.EntityError) {
+	if dbc := r.db.Create(token); dbc.Error != nil {
+		return nil, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return token, nil
+}
+
+func (r *refreshTokenDBRepo) MarkUsed(key string, at time.Time) (bool, errorUtils.EntityError) {
+	dbc := r.db.Model(&RefreshToken{}).
+		Where("token = ? AND used_at IS NULL", key).
+		Update("used_at", at)
+	if dbc.Error != nil {
+		return false, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return dbc.RowsAffected == 1, nil
+}
+
+func (r *refreshTokenDBRepo) DeleteByFamily(familyId string) (int64, errorUtils.EntityError) {
+	return r.deleteWhere("family_id = ?", familyId)
+}
+
+func (r *refreshTokenDBRepo) DeleteByUserID(userId uint64) (int64, errorUtils.EntityError) {
+	return r.deleteWhere("user_id = ?", userId)
+}
+
+func (r *refreshTokenDBRepo) DeleteExpired(now time.Time) (int64, errorUtils.EntityError) {
+	return r.deleteWhere("expires_at < ?", now.UnixNano())
+}
+
+func (r *refreshTokenDBRepo) deleteWhere(query string, value interface{}) (int64, errorUtils.EntityError) {
+	dbc := r.db.Where(query, value).Delete(&RefreshToken{})
+	if dbc.Error != nil {
+		return 0, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return dbc.RowsAffected, nil
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"strings"
+	"time"
+)
+
+//RefreshToken is a long-lived, single-use token that is traded for a new access token.
+//Every refresh token issued from the same login shares a family, so the whole chain can be revoked at once.
+//Like sessions, only the hash of the token is stored.
+type RefreshToken struct {
+	Token     string     `gorm:"primary_key;column:token" json:"-"`
+	FamilyId  string     `gorm:"column:family_id;not null;index" json:"family_id"`
+	UserId    uint64     `gorm:"column:user_id;not null;index" json:"user_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt int64      `gorm:"column:expires_at;index" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at"`
+}
+
+func (t *RefreshToken) Validate() errorUtils.EntityError {
+	if strings.Trim(t.Token, " ") == "" {
+		return errorUtils.NewUnprocessableEntityError("Token cannot be empty")
+	}
+	if strings.Trim(t.FamilyId, " ") == "" {
+		return errorUtils.NewUnprocessableEntityError("Token family cannot be empty")
+	}
+	return nil
+}
+
+//TokenPair is what a successful login or refresh hands back to the client
+type TokenPair struct {
+	AccessToken      string `json:"access_token"`
+	AccessExpiresAt  int64  `json:"access_expires_at"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt int64  `json:"refresh_expires_at"`
+}
@@ -36,7 +36,8 @@ func (u *userRoleRepo) Create(role *UserRole) (*UserRole, errorUtils.EntityError
 }
 
 func (u *userRoleRepo) Update(role *UserRole) (*UserRole, errorUtils.EntityError) {
-	if err := u.db.Where("id = ?", role.ID).First(&role).Error; err != nil {
+	var found UserRole
+	if err := u.db.Where("id = ?", role.ID).First(&found).Error; err != nil {
 		return nil, errorUtils.NewNotFoundError(err.Error())
 	}
 	u.db.Save(*role)
@@ -5,6 +5,9 @@ import (
 	"time"
 )
 
+//AdminRoleName is the role that must always be held by someone, or nobody can manage the roles anymore
+const AdminRoleName = "admin"
+
 type UserRole struct {
 	ID        uint64     `gorm:"primary_key" json:"id"`
 	CreatedAt time.Time  `json:"created_at"`
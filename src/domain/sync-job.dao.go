@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	SyncJobRepo SyncJobRepoInterface = &syncJobRepo{}
+)
+
+type SyncJobRepoInterface interface {
+	Get(uint64) (*SyncJob, errorUtils.EntityError)
+	//GetByInstance lists the jobs of a server instance in one of the statuses
+	GetByInstance(instance string, statuses ...string) ([]SyncJob, errorUtils.EntityError)
+	Create(*SyncJob) (*SyncJob, errorUtils.EntityError)
+	Update(*SyncJob) (*SyncJob, errorUtils.EntityError)
+	AddError(*SyncJobError) errorUtils.EntityError
+	Initialize(*gorm.DB)
+}
+
+type syncJobRepo struct {
+	db *gorm.DB
+}
+
+func NewSyncJobRepository(db *gorm.DB) SyncJobRepoInterface {
+	return &syncJobRepo{db: db}
+}
+
+func (s *syncJobRepo) Initialize(db *gorm.DB) {
+	s.db = db
+	db.AutoMigrate(&SyncJob{}, &SyncJobError{})
+}
+
+func (s *syncJobRepo) Get(jobId uint64) (*SyncJob, errorUtils.EntityError) {
+	var job SyncJob
+	if err := s.db.Preload("Errors").Where("id = ?", jobId).First(&job).Error; err != nil {
+		return nil, errorUtils.NewNotFoundError(err.Error())
+	}
+	return &job, nil
+}
+
+func (s *syncJobRepo) GetByInstance(instance string, statuses ...string) ([]SyncJob, errorUtils.EntityError) {
+	var jobs []SyncJob
+	if err := s.db.Where("instance = ? AND status IN (?)", instance, statuses).Find(&jobs).Error; err != nil {
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return jobs, nil
+}
+
+func (s *syncJobRepo) Create(job *SyncJob) (*SyncJob, errorUtils.EntityError) {
+	if dbc := s.db.Create(job); dbc.Error != nil {
+		return nil, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return job, nil
+}
+
+func (s *syncJobRepo) Update(job *SyncJob) (*SyncJob, errorUtils.EntityError) {
+	if dbc := s.db.Save(job); dbc.Error != nil {
+		return nil, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return job, nil
+}
+
+func (s *syncJobRepo) AddError(jobError *SyncJobError) errorUtils.EntityError {
+	if dbc := s.db.Create(jobError); dbc.Error != nil {
+		return errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"time"
+)
+
+const (
+	SyncJobQueued    = "queued"
+	SyncJobRunning   = "running"
+	SyncJobSucceeded = "succeeded"
+	SyncJobFailed    = "failed"
+	SyncJobCancelled = "cancelled"
+)
+
+//SyncJob tracks the progress of a Steam library synchronization executed in the background
+type SyncJob struct {
+	ID         uint64         `gorm:"primary_key" json:"id"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	UserID     uint64         `gorm:"column:user_id;not null;index" json:"user_id"`
+	Status     string         `gorm:"column:status;not null" json:"status"`
+	//Instance is the server the job was enqueued on, the only one that can run it
+	Instance   string         `gorm:"column:instance;index" json:"-"`
+	Total      int            `gorm:"column:total" json:"total"`
+	Inserted   int            `gorm:"column:inserted" json:"inserted"`
+	Skipped    int            `gorm:"column:skipped" json:"skipped"`
+	Errored    int            `gorm:"column:errored" json:"errored"`
+	Error      string         `gorm:"column:error" json:"error,omitempty"`
+	StartedAt  *time.Time     `gorm:"column:started_at" json:"started_at"`
+	FinishedAt *time.Time     `gorm:"column:finished_at" json:"finished_at"`
+	Errors     []SyncJobError `gorm:"foreignkey:JobID;association_autoupdate:false;association_autocreate:false" json:"errors"`
+}
+
+//SyncJobError is the reason why a single Steam app could not be synchronized
+type SyncJobError struct {
+	ID        uint64    `gorm:"primary_key" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	JobID     uint64    `gorm:"column:job_id;not null;index" json:"-"`
+	AppId     string    `gorm:"column:app_id" json:"app_id"`
+	Message   string    `gorm:"column:message" json:"message"`
+}
+
+func (j *SyncJob) Validate() errorUtils.EntityError {
+	if j.UserID <= 0 {
+		return errorUtils.NewUnprocessableEntityError("Sync job UserID is invalid")
+	}
+	return nil
+}
+
+//Processed is the number of owned apps that were handled so far, whatever the outcome
+func (j *SyncJob) Processed() int {
+	return j.Inserted + j.Skipped + j.Errored
+}
+
+func (j *SyncJob) IsFinished() bool {
+	return j.Status == SyncJobSucceeded || j.Status == SyncJobFailed || j.Status == SyncJobCancelled
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+)
+
+var (
+	UserGameRepo UserGameRepoInterface = &userGameRepo{}
+)
+
+type UserGameRepoInterface interface {
+	GetByUserID(userId uint64) ([]UserGame, errorUtils.EntityError)
+	GetByUserAndGame(userId uint64, gameId uint64, source string) (*UserGame, errorUtils.EntityError)
+	Create(*UserGame) (*UserGame, errorUtils.EntityError)
+	Update(*UserGame) (*UserGame, errorUtils.EntityError)
+	Delete(uint64) errorUtils.EntityError
+	Initialize(*gorm.DB)
+}
+
+type userGameRepo struct {
+	db *gorm.DB
+}
+
+func NewUserGameRepository(db *gorm.DB) UserGameRepoInterface {
+	return &userGameRepo{db: db}
+}
+
+func (u *userGameRepo) Initialize(db *gorm.DB) {
+	u.db = db
+	db.AutoMigrate(&UserGame{})
+	db.Model(&UserGame{}).AddUniqueIndex("idx_user_games_user_game_source", "user_id", "game_id", "source")
+}
+
+func (u *userGameRepo) GetByUserID(userId uint64) ([]UserGame, errorUtils.EntityError) {
+	var userGames []UserGame
+	if err := u.db.Preload("Game").Where("user_id = ?", userId).Find(&userGames).Error; err != nil {
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return userGames, nil
+}
+
+func (u *userGameRepo) GetByUserAndGame(userId uint64, gameId uint64, source string) (*UserGame, errorUtils.EntityError) {
+	var userGame UserGame
+	err := u.db.Preload("Game").
+		Where("user_id = ? AND game_id = ? AND source = ?", userId, gameId, source).
+		First(&userGame).Error
+	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errorUtils.NewNotFoundError(err.Error())
+		}
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return &userGame, nil
+}
+
+func (u *userGameRepo) Create(userGame *UserGame) (*UserGame, errorUtils.EntityError) {
+	if dbc := u.db.Create(userGame); dbc.Error != nil {
+		return nil, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return userGame, nil
+}
+
+func (u *userGameRepo) Update(userGame *UserGame) (*UserGame, errorUtils.EntityError) {
+	var current UserGame
+	if err := u.db.Where("id = ?", userGame.ID).First(&current).Error; err != nil {
+		return nil, errorUtils.NewNotFoundError(err.Error())
+	}
+	if dbc := u.db.Save(userGame); dbc.Error != nil {
+		return nil, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return userGame, nil
+}
+
+func (u *userGameRepo) Delete(userGameId uint64) errorUtils.EntityError {
+	var userGame UserGame
+	if err := u.db.Where("id = ?", userGameId).First(&userGame).Error; err != nil {
+		return errorUtils.NewNotFoundError(err.Error())
+	}
+	dbc := u.db.Delete(&userGame)
+	return errorUtils.NewEntityError(dbc.Error)
+}
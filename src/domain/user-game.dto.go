@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"time"
+)
+
+const (
+	UserGameSourceSteam = "steam"
+)
+
+//UserGame is an entry of a user's library: a game owned by the user on a given platform (source).
+//Entries are deleted for good, the game is synced again as a new entry.
+type UserGame struct {
+	ID                     uint64     `gorm:"primary_key" json:"id"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+	UserID                 uint64     `gorm:"column:user_id;not null;index" json:"user_id"`
+	GameID                 uint64     `gorm:"column:game_id;not null" json:"game_id"`
+	Game                   *Game      `gorm:"foreignkey:GameID;association_autoupdate:false;association_autocreate:false" json:"game,omitempty"`
+	Source                 string     `gorm:"column:source;not null" json:"source"`
+	PlaytimeForever        int        `gorm:"column:playtime_forever" json:"playtime_forever"`
+	PlaytimeWindowsForever int        `gorm:"column:playtime_windows_forever" json:"playtime_windows_forever"`
+	PlaytimeMacForever     int        `gorm:"column:playtime_mac_forever" json:"playtime_mac_forever"`
+	PlaytimeLinuxForever   int        `gorm:"column:playtime_linux_forever" json:"playtime_linux_forever"`
+	LastSyncedAt           *time.Time `gorm:"column:last_synced_at" json:"last_synced_at"`
+}
+
+func (u *UserGame) Validate() errorUtils.EntityError {
+	if u.UserID <= 0 {
+		return errorUtils.NewUnprocessableEntityError("Library entry UserID is invalid")
+	}
+
+	if u.GameID <= 0 {
+		return errorUtils.NewUnprocessableEntityError("Library entry GameID is invalid")
+	}
+
+	if u.Source == "" {
+		return errorUtils.NewUnprocessableEntityError("Library entry Source cannot be empty")
+	}
+	return nil
+}
@@ -1,6 +1,17 @@
 package domain
 
-import "GamesAPI/src/utils/errorUtils"
+import (
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	SessionStoreMemory   = "memory"
+	SessionStoreDatabase = "database"
+)
 
 var (
 	UserSessionRepo = NewUserAuthTokenRepository()
@@ -11,13 +22,26 @@ type UserSessionRepoInterface interface {
 	Create(key string, token *UserSession) (*UserSession, errorUtils.EntityError)
 	Delete(key string) errorUtils.EntityError
 	Exists(key string) bool
+	GetByUserID(userId uint64) ([]UserSession, errorUtils.EntityError)
+	DeleteByUserID(userId uint64) (int64, errorUtils.EntityError)
+	DeleteByFamily(familyId string) (int64, errorUtils.EntityError)
+	//DeleteExpired frees every session that expired before the given time and returns how many were removed
+	DeleteExpired(now time.Time) (int64, errorUtils.EntityError)
+	Initialize(*gorm.DB)
 }
 
+//userSessionRepo keeps the sessions in memory. They are lost on restart and cannot be shared between replicas,
+//use the database store for that.
 type userSessionRepo struct {
-	repo map[string]*UserSession
+	mutex sync.RWMutex
+	repo  map[string]*UserSession
 }
 
+func (u *userSessionRepo) Initialize(db *gorm.DB) {}
+
 func (u *userSessionRepo) Get(key string) (*UserSession, errorUtils.EntityError) {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
 	user := u.repo[key]
 	var err errorUtils.EntityError = nil
 	if user == nil {
@@ -27,19 +51,160 @@ func (u *userSessionRepo) Get(key string) (*UserSession, errorUtils.EntityError)
 }
 
 func (u *userSessionRepo) Create(key string, token *UserSession) (*UserSession, errorUtils.EntityError) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
 	u.repo[key] = token
 	return token, nil
 }
 
 func (u *userSessionRepo) Delete(key string) errorUtils.EntityError {
-	u.repo[key] = nil
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	delete(u.repo, key)
 	return nil
 }
 
 func (u *userSessionRepo) Exists(key string) bool {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
 	return u.repo[key] != nil
 }
 
+func (u *userSessionRepo) GetByUserID(userId uint64) ([]UserSession, errorUtils.EntityError) {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	sessions := []UserSession{}
+	for _, session := range u.repo {
+		if session.UserId == userId {
+			sessions = append(sessions, *session)
+		}
+	}
+	//most recent first, like the database store
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (u *userSessionRepo) DeleteByUserID(userId uint64) (int64, errorUtils.EntityError) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	var deleted int64
+	for key, session := range u.repo {
+		if session.UserId == userId {
+			delete(u.repo, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (u *userSessionRepo) DeleteByFamily(familyId string) (int64, errorUtils.EntityError) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	var deleted int64
+	for key, session := range u.repo {
+		if session.FamilyId == familyId {
+			delete(u.repo, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (u *userSessionRepo) DeleteExpired(now time.Time) (int64, errorUtils.EntityError) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	var deleted int64
+	for key, session := range u.repo {
+		if session.ExpiresAt < now.UnixNano() {
+			delete(u.repo, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
 func NewUserAuthTokenRepository() UserSessionRepoInterface {
 	return &userSessionRepo{repo: map[string]*UserSession{}}
 }
+
+//userSessionDBRepo keeps the sessions in the user_sessions table, so they survive restarts and are shared between replicas
+type userSessionDBRepo struct {
+	db *gorm.DB
+}
+
+func NewUserSessionDBRepository(db *gorm.DB) UserSessionRepoInterface {
+	return &userSessionDBRepo{db: db}
+}
+
+func (u *userSessionDBRepo) Initialize(db *gorm.DB) {
+	u.db = db
+	db.AutoMigrate(&UserSession{})
+}
+
+func (u *userSessionDBRepo) Get(key string) (*UserSession, errorUtils.EntityError) {
+	var session UserSession
+	if err := u.db.Where("token = ?", key).First(&session).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errorUtils.NewNotFoundError("Token does not exist in repository")
+		}
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return &session, nil
+}
+
+func (u *userSessionDBRepo) Create(key string, token *UserSession) (*UserSession, errorUtils.EntityError) {
+	token.Token = key
+	if dbc := u.db.Create(token); dbc.Error != nil {
+		return nil, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return token, nil
+}
+
+func (u *userSessionDBRepo) Delete(key string) errorUtils.EntityError {
+	if dbc := u.db.Where("token = ?", key).Delete(&UserSession{}); dbc.Error != nil {
+		return errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return nil
+}
+
+func (u *userSessionDBRepo) Exists(key string) bool {
+	var count int64
+	if err := u.db.Model(&UserSession{}).Where("token = ?", key).Count(&count).Error; err != nil {
+		return false
+	}
+	return count > 0
+}
+
+func (u *userSessionDBRepo) GetByUserID(userId uint64) ([]UserSession, errorUtils.EntityError) {
+	sessions := []UserSession{}
+	if err := u.db.Where("user_id = ?", userId).Order("created_at desc").Find(&sessions).Error; err != nil {
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return sessions, nil
+}
+
+func (u *userSessionDBRepo) DeleteByUserID(userId uint64) (int64, errorUtils.EntityError) {
+	dbc := u.db.Where("user_id = ?", userId).Delete(&UserSession{})
+	if dbc.Error != nil {
+		return 0, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return dbc.RowsAffected, nil
+}
+
+func (u *userSessionDBRepo) DeleteByFamily(familyId string) (int64, errorUtils.EntityError) {
+	dbc := u.db.Where("family_id = ?", familyId).Delete(&UserSession{})
+	if dbc.Error != nil {
+		return 0, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return dbc.RowsAffected, nil
+}
+
+func (u *userSessionDBRepo) DeleteExpired(now time.Time) (int64, errorUtils.EntityError) {
+	dbc := u.db.Where("expires_at < ?", now.UnixNano()).Delete(&UserSession{})
+	if dbc.Error != nil {
+		return 0, errorUtils.NewInternalServerError(dbc.Error.Error())
+	}
+	return dbc.RowsAffected, nil
+}
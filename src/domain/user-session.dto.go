@@ -3,12 +3,17 @@ package domain
 import (
 	"GamesAPI/src/utils/errorUtils"
 	"strings"
+	"time"
 )
 
+//UserSession is stored under the hash of its token, the token itself is only ever known by the client.
+//The hash doubles as the session id exposed by the API.
 type UserSession struct {
-	Token     string `json:"token"`
-	UserId    uint64 `json:"user_id"`
-	ExpiresAt int64  `json:"expires_at"`
+	Token     string    `gorm:"primary_key;column:token" json:"id"`
+	UserId    uint64    `gorm:"column:user_id;index" json:"user_id"`
+	FamilyId  string    `gorm:"column:family_id;index" json:"family_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt int64     `gorm:"column:expires_at;index" json:"expires_at"`
 }
 
 func (t *UserSession) Validate() errorUtils.EntityError {
@@ -7,6 +7,7 @@ import (
 
 type UserRepoInterface interface {
 	Get(uint642 uint64) (*User, errorUtils.EntityError)
+	GetByEmail(email string) (*User, errorUtils.EntityError)
 	Create(*User) (*User, errorUtils.EntityError)
 	Update(*User) (*User, errorUtils.EntityError)
 	Delete(uint64) errorUtils.EntityError
@@ -39,6 +40,18 @@ func (u *userRepo) Get(userId uint64) (*User, errorUtils.EntityError) {
 	return &user, nil
 }
 
+//GetByEmail goes through the unique index on email
+func (u *userRepo) GetByEmail(email string) (*User, errorUtils.EntityError) {
+	var user User
+	if err := u.db.Where("email = ?", email).First(&user).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, errorUtils.NewNotFoundError(err.Error())
+		}
+		return nil, errorUtils.NewInternalServerError(err.Error())
+	}
+	return &user, nil
+}
+
 func (u *userRepo) Create(user *User) (*User, errorUtils.EntityError) {
 	if dbc := u.db.Create(user); dbc.Error != nil {
 		return nil, errorUtils.NewInternalServerError(dbc.Error.Error())
@@ -2,7 +2,9 @@ package domain
 
 import (
 	"GamesAPI/src/utils/errorUtils"
+	"fmt"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -38,3 +40,21 @@ func (u *User) Validate() errorUtils.EntityError {
 
 	return nil
 }
+
+//Patch sets the given fields of u, by their JSON names, to the values they have in from. Names are matched whatever
+//their case, the way encoding/json binds them. Only the name, the email and the Steam account can be written this way.
+func (u *User) Patch(from *User, fields []string) errorUtils.EntityError {
+	for _, field := range fields {
+		switch strings.ToLower(field) {
+		case "name":
+			u.Name = from.Name
+		case "email":
+			u.Email = from.Email
+		case "steam_user_id":
+			u.SteamUserId = from.SteamUserId
+		default:
+			return errorUtils.NewUnprocessableEntityError(fmt.Sprintf("User field '%s' cannot be updated", field))
+		}
+	}
+	return nil
+}
@@ -6,15 +6,22 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"log"
+	"os"
 )
 
 func main() {
 	err := godotenv.Load()
+	//commands such as rbac lint can do without a .env file
+	if len(os.Args) > 1 {
+		os.Exit(api.RunCommand(os.Args[1:], os.Stdout))
+	}
 	if err != nil {
 		log.Fatal("Error loading .env file")
 	}
 
 	fmt.Println("Go Games API")
-	r := gin.Default()
+	//requests are logged by the router, along with who sent them
+	r := gin.New()
+	r.Use(gin.Recovery())
 	api.Bootstrap(r)
 }
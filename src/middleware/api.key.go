@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"GamesAPI/src/domain"
 	"GamesAPI/src/services"
+	"context"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,25 +21,21 @@ func ErrorMessageTypeCode(c *gin.Context, code int, message interface{}) {
 }
 
 //Token  Authentificator  handler
+//The client owning the key is put in the request context (domain.ApiClientKey), for logging and quotas
 func MiddlewareHandler(c *gin.Context) {
-	/// token := c.Request.FormValue("api_token") //.header
-
 	token := c.Request.Header.Get("x-api-key")
 
 	if token == "" {
 		ErrorMessageTypeCode(c, 400, "API token required")
 		return
 	}
-	resultValidate, err := services.TokenService.ValidateToken(token)
-	if err != nil {
-		ErrorMessageTypeCode(c, 500, err.Error())
-		return
-	}
 
-	if !resultValidate {
-		ErrorMessageTypeCode(c, 401, "Invalid API token")
+	key, err := services.ApiKeyService.Authenticate(token)
+	if err != nil {
+		ErrorMessageTypeCode(c, err.Status(), err.Message())
 		return
 	}
 
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), domain.ApiClientKey(), key.Client()))
 	c.Next()
 }
@@ -4,15 +4,24 @@ import (
 	"GamesAPI/src/domain"
 	"GamesAPI/src/services"
 	"GamesAPI/src/utils/errorUtils"
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"io"
+	"io/ioutil"
 	"net/http"
-	"os"
 	"strings"
 )
 
+//bodies are buffered in memory for the body rules, none of our routes takes anything close to this
+const maxAuthorizedBodySize = 1 << 20
+
+var errBodyTooLarge = fmt.Errorf("request body should not be larger than %d bytes", maxAuthorizedBodySize)
+
+//InitAuthorization checks the routes registered on g from here on against services.AuthorizationService
 func InitAuthorization(g *gin.RouterGroup) {
-	services.AuthorizationService = services.NewAuthorizationService(os.Getenv("RBAC_FILEPATH"))
 	g.Use(AuthorizationHandler)
 }
 
@@ -32,80 +41,121 @@ func AuthorizationHandler(c *gin.Context) {
 		return
 	}
 
-	roles, err := services.UserRoleService.GetRolesByUserID(userId.(uint64))
+	roleNames, err := userRoleNames(ctx, userId.(uint64))
 	if err != nil {
 		handleAuthError(c, err.Status(), err)
 		return
 	}
-	if len(roles) < 1 {
+	if len(roleNames) < 1 {
 		handleAuthError(c, 500, errorUtils.ErrNoRole)
 		return
 	}
-	//Typically, each user has only one role, so we'll take the first one we get
-	roleName := roles[0].Name
+	//All the roles are checked, combined according to the strategy the authorization service was configured with
+	roles := make([]string, 0, len(roleNames))
+	for _, roleName := range roleNames {
+		roles = append(roles, strings.ToLower(roleName))
+	}
 
-	//2. Determine which resource we're trying to access
+	//2. Determine which resource we're trying to access, and which endpoint (action) of it, as declared by the matched route
 	url := c.Request.URL
-	resource, resourceErr := extractResource(url.Path)
-	if resourceErr != nil {
-		handleAuthError(c, 400, resourceErr)
+	route, declared := DeclaredRbacRoute(c.Request.Method, c.FullPath())
+	if !declared {
+		handleAuthError(c, 400, errors.New("resource does not exist"))
+		return
+	}
+	resource, endpoint := route.Resource, route.Action
+	if client, ok := ctx.Value(domain.ApiClientKey()).(*domain.ApiClient); ok && !client.Scopes.Allows(resource, endpoint) {
+		handleAuthError(c, http.StatusForbidden, fmt.Errorf("api key %s is not scoped for %s.%s", client.Prefix, resource, endpoint))
 		return
 	}
 
-	//3. Determine which endpoint we're trying to access
-	httpMethod := c.Request.Method
-	endpoint, endpointErr := extractEndpoint(httpMethod)
-	if endpointErr != nil {
-		handleAuthError(c, 400, endpointErr)
+	//3. Expose the named route parameters (e.g. :id), the headers and the body so path, header and body rules can be checked against them.
+	//	 The headers are the request's own, enforced header rules change what the handlers get.
+	params := map[string]string{}
+	for _, param := range c.Params {
+		params[param.Key] = param.Value
+	}
+	body, bodyErr := bufferBody(c.Request)
+	if bodyErr == errBodyTooLarge {
+		handleAuthError(c, http.StatusRequestEntityTooLarge, bodyErr)
 		return
 	}
-	roleName = strings.ToLower(roleName)
-	//4. Authorize the request using all the info provided
-	authErr := services.AuthorizationService.Authorize(ctx, url, roleName, resource, endpoint)
+	if bodyErr != nil {
+		handleAuthError(c, http.StatusBadRequest, bodyErr)
+		return
+	}
+	ctx = authorizationContext(ctx, params, c.Request.Header, body)
+
+	//4. Authorize the request using all the info provided, handlers find the decision in the request context
+	decision, authErr := services.AuthorizationService.AuthorizeRoles(ctx, url, roles, resource, endpoint)
 	if authErr != nil {
 		handleAuthError(c, 403, authErr)
 		return
 	}
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), domain.RbacDecisionKey(), decision))
 
 	c.Next()
 }
 
-func extractResource(urlPath string) (string, error) {
-	if strings.Contains(urlPath, "/games") {
-		return "game", nil
-	}
+//authorizationContext exposes what path, header and body rules are checked against
+func authorizationContext(ctx context.Context, params map[string]string, headers http.Header, body []byte) context.Context {
+	ctx = context.WithValue(ctx, domain.RbacRouteParams(), params)
+	ctx = context.WithValue(ctx, domain.RbacRequestHeaders(), headers)
+	return context.WithValue(ctx, domain.RbacRequestBody(), body)
+}
 
-	if strings.Contains(urlPath, "/users") {
-		return "user", nil
+//bufferBody reads the body so body rules can be checked, and puts it back for the handlers to read
+func bufferBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
 	}
-
-	if strings.Contains(urlPath, "/LinkSteamUser") {
-		return "link_steam_user", nil
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxAuthorizedBodySize+1))
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
 	}
-
-	if strings.Contains(urlPath, "/SyncGames") {
-		return "sync_games", nil
+	if len(body) > maxAuthorizedBodySize {
+		return nil, errBodyTooLarge
 	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
 
-	return "", errors.New("resource does not exist")
+//userRoleNames prefers the roles carried by a JWT, which spares a database lookup on every request
+func userRoleNames(ctx context.Context, userId uint64) ([]string, errorUtils.EntityError) {
+	if roles, ok := ctx.Value(domain.RbacUserRoles()).([]string); ok {
+		return roles, nil
+	}
+	roles, err := services.UserRoleService.GetRolesByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names, nil
 }
 
-func extractEndpoint(httpMethod string) (string, error) {
-	var ret = ""
-	var err error = nil
-	switch httpMethod {
-	case "GET":
-		ret = "read"
-	case "POST":
-		ret = "create"
-	case "PATCH":
-		ret = "update"
-	case "PUT":
-		ret = "update"
-	case "DELETE":
-		ret = "delete"
-	default:
-		err = errors.New("endpoint does not exist")
-	}
-	return ret, err
+//RbacSurface maps the routes to the resources and endpoints (actions) they declare.
+//Routes declaring none, such as the public ones, are left out.
+func RbacSurface(routes gin.RoutesInfo) *domain.RbacSurface {
+	surface := &domain.RbacSurface{
+		Resources:   map[string]map[string]bool{},
+		ContextKeys: map[string]bool{},
+	}
+	for _, route := range routes {
+		declared, exists := DeclaredRbacRoute(route.Method, route.Path)
+		if !exists {
+			continue
+		}
+		if surface.Resources[declared.Resource] == nil {
+			surface.Resources[declared.Resource] = map[string]bool{}
+		}
+		surface.Resources[declared.Resource][declared.Action] = true
+	}
+	for _, key := range domain.RbacContextKeys() {
+		surface.ContextKeys[key] = true
+	}
+	return surface
 }
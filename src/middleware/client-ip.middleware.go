@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+//TrustedProxies are the only peers whose X-Forwarded-For and X-Real-Ip headers are believed, see TrustedProxiesFromEnv
+var TrustedProxies []*net.IPNet
+
+//TrustedProxiesFromEnv reads TRUSTED_PROXIES, comma-separated IPs or CIDR ranges. Nobody is trusted when empty.
+func TrustedProxiesFromEnv() ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, entry := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("trusted proxy '%s' is not an IP or a CIDR range", entry)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			entry = fmt.Sprintf("%s/%d", entry, bits)
+		}
+		_, proxy, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy '%s' is not an IP or a CIDR range", entry)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
+//InitClientIP makes c.ClientIP() trustworthy for every route registered from here on
+func InitClientIP(r *gin.Engine) {
+	r.Use(ClientIPHandler)
+}
+
+//ClientIPHandler drops the forwarding headers a client could forge before gin's ClientIP reads them.
+//A request coming from a trusted proxy keeps, as its only forwarded address, the closest hop which is not one of our proxies.
+//Anything else is known by the address of its connection, so the failed logins and the rate limits cannot be dodged
+//by sending another X-Forwarded-For with each request.
+func ClientIPHandler(c *gin.Context) {
+	header := c.Request.Header
+	if !isTrustedProxy(remoteIP(c.Request)) {
+		header.Del("X-Forwarded-For")
+		header.Del("X-Real-Ip")
+		c.Next()
+		return
+	}
+
+	forwarded := strings.Join(header.Values("X-Forwarded-For"), ",")
+	if strings.TrimSpace(forwarded) == "" {
+		//X-Real-Ip was then set by our proxy
+		c.Next()
+		return
+	}
+	//each proxy appends the peer it got the request from, the hops before the first untrusted one from the end could be forged
+	client := ""
+	hops := strings.Split(forwarded, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			break
+		}
+		client = hop.String()
+		if !isTrustedProxy(hop) {
+			break
+		}
+	}
+	header.Del("X-Real-Ip")
+	if client == "" {
+		header.Del("X-Forwarded-For")
+	} else {
+		header.Set("X-Forwarded-For", client)
+	}
+	c.Next()
+}
+
+func remoteIP(req *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(req.RemoteAddr))
+	if err != nil {
+		host = strings.TrimSpace(req.RemoteAddr)
+	}
+	return net.ParseIP(host)
+}
+
+func isTrustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range TrustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
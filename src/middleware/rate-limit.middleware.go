@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//InitRateLimit throttles the routes registered on g from here on, using the limit of the named route group
+func InitRateLimit(g *gin.RouterGroup, group string) {
+	g.Use(RateLimitHandler(group))
+}
+
+//RateLimitHandler counts every request against the bucket of its API key and, once the session handler ran,
+//against the bucket of its user as well, so neither a user juggling keys nor a key shared by users gets around it.
+//A request refused by one bucket takes nothing from the others.
+//A store failure lets the request through: throttling is not worth an outage.
+func RateLimitHandler(group string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clients := rateLimitClients(c)
+		result, ok, err := services.RateLimitService.Allow(group, clients, time.Now())
+		if err != nil {
+			log.Printf("rate limit of %s could not be checked: %s", strings.Join(clients, ", "), err.Error())
+			c.Next()
+			return
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+
+		setRateLimitHeaders(c, &result)
+		if !result.Allowed {
+			retryAfter := services.RetryAfterSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			ErrorMessageTypeCode(c, http.StatusTooManyRequests, fmt.Sprintf("rate limit exceeded, retry in %d seconds", retryAfter))
+			return
+		}
+		c.Next()
+	}
+}
+
+//rateLimitClients lists the buckets the request is counted against, falling back to the client IP when nothing is known
+func rateLimitClients(c *gin.Context) []string {
+	ctx := c.Request.Context()
+	var clients []string
+	if client, ok := ctx.Value(domain.ApiClientKey()).(*domain.ApiClient); ok {
+		clients = append(clients, fmt.Sprintf("key:%d", client.KeyId))
+	}
+	if userId, ok := ctx.Value(domain.RbacUserId()).(uint64); ok {
+		clients = append(clients, fmt.Sprintf("user:%d", userId))
+	}
+	if len(clients) == 0 {
+		clients = append(clients, "ip:"+c.ClientIP())
+	}
+	return clients
+}
+
+//setRateLimitHeaders follows the IETF RateLimit header fields draft, Reset is in seconds
+func setRateLimitHeaders(c *gin.Context, result *services.RateLimitResult) {
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(services.RetryAfterSeconds(result.Reset)))
+}
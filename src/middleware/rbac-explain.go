@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//RbacExplainRequest is a request to authorize without serving it. The roles are those of the user unless given.
+type RbacExplainRequest struct {
+	UserId  uint64
+	Roles   []string
+	Method  string
+	Path    string
+	Headers http.Header
+	Body    []byte
+}
+
+//ExplainAuthorization authorizes the request the way AuthorizationHandler would, and tells how the decision was made
+func ExplainAuthorization(request RbacExplainRequest) (*domain.RbacTrace, errorUtils.EntityError) {
+	method := strings.ToUpper(request.Method)
+	target, parseErr := url.Parse(request.Path)
+	if parseErr != nil || !strings.HasPrefix(target.Path, "/") {
+		return nil, errorUtils.NewUnprocessableEntityError("path should be an absolute path, e.g. /users/3/library?page=2")
+	}
+	route, params, matched := MatchRbacRoute(method, target.Path)
+	if !matched {
+		return nil, errorUtils.NewNotFoundError(fmt.Sprintf("no route declares a resource for %s %s", method, target.Path))
+	}
+
+	ctx := context.WithValue(context.Background(), domain.RbacUserId(), request.UserId)
+	roleNames := request.Roles
+	if len(roleNames) == 0 {
+		var err errorUtils.EntityError
+		roleNames, err = userRoleNames(ctx, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	roles := make([]string, 0, len(roleNames))
+	for _, roleName := range roleNames {
+		roles = append(roles, strings.ToLower(roleName))
+	}
+	headers := request.Headers
+	if headers == nil {
+		headers = http.Header{}
+	}
+	ctx = authorizationContext(ctx, params, headers, request.Body)
+
+	return services.AuthorizationService.Explain(ctx, target, roles, route.Resource, route.Action), nil
+}
+
+//MatchRbacRoute finds the declared route path would be served by, along with its named parameters.
+//The router refuses routes that would both match a path, so there is at most one.
+func MatchRbacRoute(method string, path string) (RbacRoute, map[string]string, bool) {
+	rbacRoutesMutex.RLock()
+	defer rbacRoutesMutex.RUnlock()
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for key, route := range rbacRoutes {
+		parts := strings.SplitN(key, " ", 2)
+		if parts[0] != method {
+			continue
+		}
+		if params, matches := matchRoutePath(strings.Split(strings.Trim(parts[1], "/"), "/"), segments); matches {
+			return route, params, true
+		}
+	}
+	return RbacRoute{}, nil, false
+}
+
+//matchRoutePath matches the segments of a path against those of a route, :name standing for one segment
+//and *name for the rest of the path
+func matchRoutePath(pattern []string, segments []string) (map[string]string, bool) {
+	params := map[string]string{}
+	for i, part := range pattern {
+		if strings.HasPrefix(part, "*") {
+			params[part[1:]] = "/" + strings.Join(segments[i:], "/")
+			return params, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		switch {
+		case strings.HasPrefix(part, ":"):
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[part[1:]] = segments[i]
+		case part != segments[i]:
+			return nil, false
+		}
+	}
+	return params, len(pattern) == len(segments)
+}
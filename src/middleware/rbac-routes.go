@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"sync"
+)
+
+//RbacRoute is what a route is checked as by the authorization layer
+type RbacRoute struct {
+	Resource string
+	Action   string
+}
+
+var (
+	rbacRoutes      = map[string]RbacRoute{}
+	rbacRoutesMutex sync.RWMutex
+)
+
+//DeclareRbacRoute records the resource and action of the route registered as method and path (the full path, e.g. /users/:id),
+//see router.Rbac, which declares the routes it registers
+func DeclareRbacRoute(method string, path string, resource string, action string) {
+	rbacRoutesMutex.Lock()
+	defer rbacRoutesMutex.Unlock()
+	rbacRoutes[method+" "+path] = RbacRoute{Resource: resource, Action: action}
+}
+
+//DeclaredRbacRoute returns the resource and action declared for the route registered as method and path
+func DeclaredRbacRoute(method string, path string) (RbacRoute, bool) {
+	rbacRoutesMutex.RLock()
+	defer rbacRoutesMutex.RUnlock()
+	route, declared := rbacRoutes[method+" "+path]
+	return route, declared
+}
+
+//DefaultRbacAction is the action a route is checked as when it does not declare one
+func DefaultRbacAction(httpMethod string) (string, error) {
+	var ret = ""
+	var err error = nil
+	switch httpMethod {
+	case "GET":
+		ret = "read"
+	case "POST":
+		ret = "create"
+	case "PATCH":
+		ret = "update"
+	case "PUT":
+		ret = "update"
+	case "DELETE":
+		ret = "delete"
+	default:
+		err = errors.New("endpoint does not exist")
+	}
+	return ret, err
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"GamesAPI/src/domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"regexp"
+)
+
+const RequestIdHeader = "X-Request-Id"
+
+//ids sent by clients end up in logs and in the audit log, anything else than a short token is replaced
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+//InitRequestId gives every request registered from here on an id
+func InitRequestId(r *gin.Engine) {
+	r.Use(RequestIdHandler)
+}
+
+//RequestIdHandler keeps the X-Request-Id the client sent, or makes one up, and sends it back.
+//It is put in the request context (domain.RequestIdKey) so changes can be traced back to their request.
+func RequestIdHandler(c *gin.Context) {
+	requestId := c.GetHeader(RequestIdHeader)
+	if !requestIdPattern.MatchString(requestId) {
+		requestId = newRequestId()
+	}
+	c.Header(RequestIdHeader, requestId)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), domain.RequestIdKey(), requestId))
+	c.Next()
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	//crypto/rand only fails when the system has no entropy source, an id of zeros is still an id
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"GamesAPI/src/domain"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+//InitRequestLog logs every request registered from here on, with who sent it
+func InitRequestLog(r *gin.Engine) {
+	r.Use(gin.LoggerWithFormatter(RequestLogFormatter))
+}
+
+//RequestLogFormatter is gin's access log line, followed by the request id, the user and the API key the request came with.
+//The formatter runs once the request was handled, the context then holds what the other middlewares found out.
+func RequestLogFormatter(param gin.LogFormatterParams) string {
+	ctx := param.Request.Context()
+	requestId, _ := ctx.Value(domain.RequestIdKey()).(string)
+	client := "-"
+	if userId, ok := ctx.Value(domain.RbacUserId()).(uint64); ok {
+		client = fmt.Sprintf("user %d", userId)
+	}
+	if apiClient, ok := ctx.Value(domain.ApiClientKey()).(*domain.ApiClient); ok {
+		client = fmt.Sprintf("user %d key %d (%s)", apiClient.OwnerId, apiClient.KeyId, apiClient.Prefix)
+	}
+	if requestId == "" {
+		requestId = "-"
+	}
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | %s | %s\n%s",
+		param.TimeStamp.Format(time.RFC3339),
+		param.StatusCode,
+		param.Latency,
+		param.ClientIP,
+		param.Method,
+		param.Path,
+		requestId,
+		client,
+		param.ErrorMessage,
+	)
+}
@@ -3,11 +3,10 @@ package middleware
 import (
 	"GamesAPI/src/domain"
 	"GamesAPI/src/services"
+	"GamesAPI/src/utils/authUtils"
 	"GamesAPI/src/utils/errorUtils"
 	"context"
-	"fmt"
 	"github.com/gin-gonic/gin"
-	"strings"
 	"time"
 )
 
@@ -24,18 +23,23 @@ func UserSessionHandler(c *gin.Context) {
 		AbortWithError(c, err.Status(), err.Message())
 		return
 	}
-	reqToken := strings.Trim(c.Request.Header["Authorization"][0], " ")
-	splitToken := strings.Split(reqToken, "Bearer ")
-	if len(splitToken) < 2 {
-		_ = fmt.Sprintf("%v", splitToken)
+	sessionKey := authUtils.BearerToken(authHeader[0])
+	if sessionKey == "" {
 		err := errorUtils.NewBadRequestError("Authorization header was not set properly.")
 		AbortWithError(c, err.Status(), err.Message())
 		return
 	}
-	sessionKey := splitToken[1]
-	if sessionKey == "" {
-		err := errorUtils.NewBadRequestError("Authorization header was not set properly.")
-		AbortWithError(c, err.Status(), err.Message())
+
+	if services.JwtService.Enabled() && authUtils.LooksLikeJwt(sessionKey) {
+		claims, err := services.JwtService.Verify(sessionKey)
+		if err != nil {
+			AbortWithWWWAuthenticate(c, err.Status(), err.Message())
+			return
+		}
+		ctx := context.WithValue(c.Request.Context(), domain.RbacUserId(), claims.UserId)
+		ctx = context.WithValue(ctx, domain.RbacUserRoles(), claims.Roles)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
 		return
 	}
 
@@ -61,7 +65,7 @@ func UserSessionHandler(c *gin.Context) {
 		return
 	}
 
-	c.Request = c.Request.WithContext(context.WithValue(context.Background(), domain.RbacUserId(), session.UserId))
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), domain.RbacUserId(), session.UserId))
 
 	c.Next()
 }
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"GamesAPI/src/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+//admins only, see the api_key resource
+func InitAllApiKeyRoutes(root *gin.RouterGroup) {
+	g := InitApiKeyRouterGroup(root)
+	InitCreateApiKeyRoute(g)
+	InitGetApiKeysRoute(g)
+	InitRevokeApiKeyRoute(g)
+}
+
+func InitApiKeyRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
+	return g.Group("/api-keys")
+}
+
+func InitCreateApiKeyRoute(g *gin.RouterGroup) {
+	Rbac(g, "api_key").POST("", controllers.CreateApiKey)
+}
+
+func InitGetApiKeysRoute(g *gin.RouterGroup) {
+	Rbac(g, "api_key").GET("", controllers.GetApiKeys)
+}
+
+func InitRevokeApiKeyRoute(g *gin.RouterGroup) {
+	Rbac(g, "api_key").DELETE("/:id", controllers.RevokeApiKey)
+}
@@ -0,0 +1,26 @@
+package router
+
+import (
+	"GamesAPI/src/controllers"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+//admins only, see the audit resource
+func InitAllAuditRoutes(root *gin.RouterGroup) {
+	g := InitAuditRouterGroup(root)
+	InitGetAuditRoute(g)
+	InitExportAuditRoute(g)
+}
+
+func InitAuditRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
+	return g.Group("/audit")
+}
+
+func InitGetAuditRoute(g *gin.RouterGroup) {
+	Rbac(g, "audit").GET("", controllers.GetAudit)
+}
+
+func InitExportAuditRoute(g *gin.RouterGroup) {
+	Rbac(g, "audit").Handle(http.MethodGet, "export", "/export", controllers.ExportAudit)
+}
@@ -2,15 +2,24 @@ package router
 
 import (
 	"GamesAPI/src/controllers"
+	"GamesAPI/src/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 func InitLoginRoute(g *gin.RouterGroup) {
-	g.GET("/login", controllers.LoginController)
+	g.POST("/login", controllers.LoginController)
 }
 
-/*
 func InitRefreshRoute(g *gin.RouterGroup) {
-	g.GET("/refresh", controllers.RefreshController)
+	g.POST("/refresh", controllers.RefreshController)
+}
+
+//the auth group is public, logging out is the only route of it needing a session
+func InitLogoutRoute(g *gin.RouterGroup) {
+	g.POST("/logout", middleware.UserSessionHandler, controllers.LogoutController)
+}
+
+//InitJwksRoute must be called before the API token middleware is applied, the JWKS is public
+func InitJwksRoute(r *gin.Engine) {
+	r.GET("/.well-known/jwks.json", controllers.JWKS)
 }
-*/
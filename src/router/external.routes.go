@@ -2,11 +2,16 @@ package router
 
 import (
 	"GamesAPI/src/controllers"
+	"GamesAPI/src/middleware"
 	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
 func InitExternalRoutes(group *gin.RouterGroup) {
 	//Init all routes that make external calls here
-	group.POST("/SyncGames", controllers.SyncGamesHandler)
-	group.POST("/LinkSteamUser", controllers.LinkSteamUser)
-}
\ No newline at end of file
+	//they fan out into Steam calls, so they have a tighter limit of their own on top of the core one
+	external := group.Group("")
+	middleware.InitRateLimit(external, "external")
+	Rbac(external, "library").Handle(http.MethodPost, "sync", "/SyncGames", controllers.SyncGamesHandler)
+	Rbac(external, "user").Handle(http.MethodPost, "link", "/LinkSteamUser", controllers.LinkSteamUser)
+}
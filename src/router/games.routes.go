@@ -19,21 +19,21 @@ func InitGameRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
 }
 
 func InitGetAllGamesRoute(g *gin.RouterGroup) {
-	g.GET("", controllers.GetAllGames)
+	Rbac(g, "game").GET("", controllers.GetAllGames)
 }
 
 func InitGetGameRoute(g *gin.RouterGroup) {
-	g.GET("/:id", controllers.GetGame)
+	Rbac(g, "game").GET("/:id", controllers.GetGame)
 }
 
 func InitCreateGameRoute(g *gin.RouterGroup) {
-	g.POST("", controllers.CreateGame)
+	Rbac(g, "game").POST("", controllers.CreateGame)
 }
 
 func InitUpdateGameRoute(g *gin.RouterGroup) {
-	g.PATCH("/:id", controllers.UpdateGame)
+	Rbac(g, "game").PATCH("/:id", controllers.UpdateGame)
 }
 
 func InitDeleteGameRoute(g *gin.RouterGroup) {
-	g.DELETE("/:id", controllers.DeleteGame)
+	Rbac(g, "game").DELETE("/:id", controllers.DeleteGame)
 }
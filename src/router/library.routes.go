@@ -0,0 +1,29 @@
+package router
+
+import (
+	"GamesAPI/src/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+func InitAllLibraryRoutes(root *gin.RouterGroup) {
+	g := InitLibraryRouterGroup(root)
+	InitGetUserLibraryRoute(g)
+	InitGetUserLibraryGameRoute(g)
+	InitDeleteUserLibraryGameRoute(g)
+}
+
+func InitLibraryRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
+	return g.Group("/users/:id/library")
+}
+
+func InitGetUserLibraryRoute(g *gin.RouterGroup) {
+	Rbac(g, "library").GET("", controllers.GetUserLibrary)
+}
+
+func InitGetUserLibraryGameRoute(g *gin.RouterGroup) {
+	Rbac(g, "library").GET("/:gameId", controllers.GetUserLibraryGame)
+}
+
+func InitDeleteUserLibraryGameRoute(g *gin.RouterGroup) {
+	Rbac(g, "library").DELETE("/:gameId", controllers.DeleteUserLibraryGame)
+}
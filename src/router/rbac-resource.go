@@ -0,0 +1,66 @@
+package router
+
+import (
+	"GamesAPI/src/middleware"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"path"
+)
+
+//RbacResource registers routes the authorization layer checks as endpoints of resource.
+//GET, POST, PATCH, PUT and DELETE routes are the read, create, update and delete endpoints, Handle takes any other action.
+type RbacResource struct {
+	group    *gin.RouterGroup
+	resource string
+}
+
+//Rbac registers the routes of resource on g, e.g. Rbac(g, "game").GET("/:id", controllers.GetGame)
+func Rbac(g *gin.RouterGroup, resource string) RbacResource {
+	return RbacResource{group: g, resource: resource}
+}
+
+func (r RbacResource) GET(relativePath string, handlers ...gin.HandlerFunc) {
+	r.handleDefault(http.MethodGet, relativePath, handlers)
+}
+
+func (r RbacResource) POST(relativePath string, handlers ...gin.HandlerFunc) {
+	r.handleDefault(http.MethodPost, relativePath, handlers)
+}
+
+func (r RbacResource) PATCH(relativePath string, handlers ...gin.HandlerFunc) {
+	r.handleDefault(http.MethodPatch, relativePath, handlers)
+}
+
+func (r RbacResource) PUT(relativePath string, handlers ...gin.HandlerFunc) {
+	r.handleDefault(http.MethodPut, relativePath, handlers)
+}
+
+func (r RbacResource) DELETE(relativePath string, handlers ...gin.HandlerFunc) {
+	r.handleDefault(http.MethodDelete, relativePath, handlers)
+}
+
+//Handle registers a route checked as action, e.g. Rbac(g, "library").Handle(http.MethodPost, "sync", "/SyncGames", ...)
+func (r RbacResource) Handle(httpMethod string, action string, relativePath string, handlers ...gin.HandlerFunc) {
+	middleware.DeclareRbacRoute(httpMethod, joinPaths(r.group.BasePath(), relativePath), r.resource, action)
+	r.group.Handle(httpMethod, relativePath, handlers...)
+}
+
+func (r RbacResource) handleDefault(httpMethod string, relativePath string, handlers []gin.HandlerFunc) {
+	action, err := middleware.DefaultRbacAction(httpMethod)
+	if err != nil {
+		panic(err)
+	}
+	r.Handle(httpMethod, action, relativePath, handlers...)
+}
+
+//joinPaths builds the full path of a route the way gin does, so it matches gin.Context.FullPath
+func joinPaths(absolutePath string, relativePath string) string {
+	if relativePath == "" {
+		return absolutePath
+	}
+	finalPath := path.Join(absolutePath, relativePath)
+	if relativePath[len(relativePath)-1] == '/' && finalPath[len(finalPath)-1] != '/' {
+		return finalPath + "/"
+	}
+	return finalPath
+}
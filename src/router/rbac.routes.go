@@ -0,0 +1,71 @@
+package router
+
+import (
+	"GamesAPI/src/controllers"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+//admins only, see the rbac resource
+func InitAllRbacRoutes(root *gin.RouterGroup) {
+	g := InitRbacRouterGroup(root)
+	InitGetRbacPolicyRoute(g)
+	InitGetRbacRolesRoute(g)
+	InitGetRbacRoleRoute(g)
+	InitPutRbacRoleRoute(g)
+	InitDeleteRbacRoleRoute(g)
+	InitPutRbacPermissionRoute(g)
+	InitDeleteRbacPermissionRoute(g)
+	InitGetRbacVersionsRoute(g)
+	InitGetRbacVersionRoute(g)
+	InitRollbackRbacPolicyRoute(g)
+	InitExplainRbacRoute(g)
+}
+
+func InitRbacRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
+	return g.Group("/rbac")
+}
+
+func InitGetRbacPolicyRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").GET("/policy", controllers.GetRbacPolicy)
+}
+
+func InitGetRbacRolesRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").GET("/roles", controllers.GetRbacRoles)
+}
+
+func InitGetRbacRoleRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").GET("/roles/:role", controllers.GetRbacRole)
+}
+
+func InitPutRbacRoleRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").PUT("/roles/:role", controllers.PutRbacRole)
+}
+
+func InitDeleteRbacRoleRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").DELETE("/roles/:role", controllers.DeleteRbacRole)
+}
+
+func InitPutRbacPermissionRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").PUT("/roles/:role/permissions/:resource/:action", controllers.PutRbacPermission)
+}
+
+func InitDeleteRbacPermissionRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").DELETE("/roles/:role/permissions/:resource/:action", controllers.DeleteRbacPermission)
+}
+
+func InitGetRbacVersionsRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").GET("/versions", controllers.GetRbacVersions)
+}
+
+func InitGetRbacVersionRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").GET("/versions/:version", controllers.GetRbacVersion)
+}
+
+func InitRollbackRbacPolicyRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").Handle(http.MethodPost, "rollback", "/versions/:version/rollback", controllers.RollbackRbacPolicy)
+}
+
+func InitExplainRbacRoute(g *gin.RouterGroup) {
+	Rbac(g, "rbac").Handle(http.MethodPost, "explain", "/explain", controllers.ExplainRbac)
+}
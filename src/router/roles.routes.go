@@ -0,0 +1,40 @@
+package router
+
+import (
+	"GamesAPI/src/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+//see the role resource, users may only read their own roles
+func InitAllRoleRoutes(root *gin.RouterGroup) {
+	g := InitRoleRouterGroup(root)
+	InitGetRolesRoute(g)
+	InitUpdateRoleRoute(g)
+	InitDeleteRoleRoute(g)
+	InitGetUserRolesRoute(root)
+	InitAddUserRoleRoute(root)
+}
+
+func InitRoleRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
+	return g.Group("/roles")
+}
+
+func InitGetRolesRoute(g *gin.RouterGroup) {
+	Rbac(g, "role").GET("", controllers.GetRoles)
+}
+
+func InitUpdateRoleRoute(g *gin.RouterGroup) {
+	Rbac(g, "role").PATCH("/:id", controllers.UpdateRole)
+}
+
+func InitDeleteRoleRoute(g *gin.RouterGroup) {
+	Rbac(g, "role").DELETE("/:id", controllers.DeleteRole)
+}
+
+func InitGetUserRolesRoute(g *gin.RouterGroup) {
+	Rbac(g, "role").GET("/users/:id/roles", controllers.GetUserRoles)
+}
+
+func InitAddUserRoleRoute(g *gin.RouterGroup) {
+	Rbac(g, "role").POST("/users/:id/roles", controllers.AddUserRole)
+}
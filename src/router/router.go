@@ -7,7 +7,11 @@ import (
 
 func InitAllRoutes(r *gin.Engine) {
 
-	middleware.InitApiToken(r) //will apply to all routes
+	middleware.InitClientIP(r)
+	middleware.InitRequestLog(r)
+	middleware.InitRequestId(r)
+	InitJwksRoute(r)
+	middleware.InitApiToken(r) //will apply to all routes registered from here
 	rootGroup := r.Group("")
 	{
 		initAuthGroup(rootGroup)
@@ -20,18 +24,28 @@ func initCoreGroup(r *gin.RouterGroup) {
 	coreGroup := r.Group("")
 	{
 		middleware.InitUserSessionHandler(coreGroup)
+		middleware.InitRateLimit(coreGroup, "core")
 		middleware.InitAuthorization(coreGroup)
 		InitHomeRoutes(coreGroup)
 		InitAllGameRoutes(coreGroup)
 		InitAllUserRoutes(coreGroup)
+		InitAllLibraryRoutes(coreGroup)
+		InitAllSessionRoutes(coreGroup)
 		InitExternalRoutes(coreGroup)
+		InitAllSyncJobRoutes(coreGroup)
+		InitAllApiKeyRoutes(coreGroup)
+		InitAllRbacRoutes(coreGroup)
+		InitAllRoleRoutes(coreGroup)
+		InitAllAuditRoutes(coreGroup)
 	}
 }
 
 func initAuthGroup(g *gin.RouterGroup) {
 	auth := g.Group("/auth")
 	{
+		middleware.InitRateLimit(auth, "auth")
 		InitLoginRoute(auth)
-		//InitRefreshRoute(g)
+		InitRefreshRoute(auth)
+		InitLogoutRoute(auth)
 	}
 }
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"GamesAPI/src/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+func InitAllSessionRoutes(root *gin.RouterGroup) {
+	userSessions := InitUserSessionRouterGroup(root)
+	InitGetUserSessionsRoute(userSessions)
+	InitDeleteUserSessionsRoute(userSessions)
+	InitForceLogoutRoute(root)
+
+	sessions := InitSessionRouterGroup(root)
+	InitDeleteSessionRoute(sessions)
+}
+
+func InitUserSessionRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
+	return g.Group("/users/:id/sessions")
+}
+
+func InitSessionRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
+	return g.Group("/sessions")
+}
+
+func InitGetUserSessionsRoute(g *gin.RouterGroup) {
+	Rbac(g, "user_session").GET("", controllers.GetUserSessions)
+}
+
+//log out everywhere
+func InitDeleteUserSessionsRoute(g *gin.RouterGroup) {
+	Rbac(g, "user_session").DELETE("", controllers.DeleteUserSessions)
+}
+
+func InitDeleteSessionRoute(g *gin.RouterGroup) {
+	Rbac(g, "session").DELETE("/:token", controllers.DeleteSession)
+}
+
+//admins only, see the user_logout resource
+func InitForceLogoutRoute(g *gin.RouterGroup) {
+	Rbac(g, "user_logout").POST("/users/:id/logout", controllers.ForceLogoutUser)
+}
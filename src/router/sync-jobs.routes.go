@@ -0,0 +1,41 @@
+package router
+
+import (
+	"GamesAPI/src/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+func InitAllSyncJobRoutes(root *gin.RouterGroup) {
+	g := InitSyncJobRouterGroup(root)
+	InitGetSyncJobRoute(g)
+	InitCancelSyncJobRoute(g)
+
+	userJobs := InitUserSyncJobRouterGroup(root)
+	InitGetUserSyncJobRoute(userJobs)
+	InitCancelUserSyncJobRoute(userJobs)
+}
+
+func InitSyncJobRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
+	return g.Group("/sync-jobs")
+}
+
+//the jobs of a user, so path rules can restrict users to their own, see the user_sync_job resource
+func InitUserSyncJobRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
+	return g.Group("/users/:id/sync-jobs")
+}
+
+func InitGetSyncJobRoute(g *gin.RouterGroup) {
+	Rbac(g, "sync_job").GET("/:id", controllers.GetSyncJob)
+}
+
+func InitCancelSyncJobRoute(g *gin.RouterGroup) {
+	Rbac(g, "sync_job").DELETE("/:id", controllers.CancelSyncJob)
+}
+
+func InitGetUserSyncJobRoute(g *gin.RouterGroup) {
+	Rbac(g, "user_sync_job").GET("/:jobId", controllers.GetUserSyncJob)
+}
+
+func InitCancelUserSyncJobRoute(g *gin.RouterGroup) {
+	Rbac(g, "user_sync_job").DELETE("/:jobId", controllers.CancelUserSyncJob)
+}
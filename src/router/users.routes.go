@@ -19,21 +19,21 @@ func InitUserRouterGroup(g *gin.RouterGroup) *gin.RouterGroup {
 }
 
 func InitGetAllUsersRoute(g *gin.RouterGroup, handlerFunc gin.HandlerFunc) {
-	g.GET("", handlerFunc)
+	Rbac(g, "user").GET("", handlerFunc)
 }
 
 func InitGetUserRoute(g *gin.RouterGroup, handlerFunc gin.HandlerFunc) {
-	g.GET("/:id", handlerFunc)
+	Rbac(g, "user").GET("/:id", handlerFunc)
 }
 
 func InitCreateUserRoute(g *gin.RouterGroup, handlerFunc gin.HandlerFunc) {
-	g.POST("", handlerFunc)
+	Rbac(g, "user").POST("", handlerFunc)
 }
 
 func InitUpdateUserRoute(g *gin.RouterGroup, handlerFunc gin.HandlerFunc) {
-	g.PATCH("/:id", handlerFunc)
+	Rbac(g, "user").PATCH("/:id", handlerFunc)
 }
 
 func InitDeleteUserRoute(g *gin.RouterGroup, handlerFunc gin.HandlerFunc) {
-	g.DELETE("/:id", handlerFunc)
+	Rbac(g, "user").DELETE("/:id", handlerFunc)
 }
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/authUtils"
+	"GamesAPI/src/utils/errorUtils"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	//ApiKeyTokenPrefix starts every key, so leaked keys are easy to spot (e.g. by secret scanners)
+	ApiKeyTokenPrefix = "gapi"
+
+	apiKeyPrefixBytes = 6
+	//last_used_at is written at most once per interval, sparing a write on every request
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	ApiKeyService ApiKeyServiceInterface = &apiKeyService{}
+)
+
+//ApiKeyServiceInterface manages the keys identifying the clients of the API.
+//A key looks like gapi_<prefix>_<secret>: the prefix finds the key, the secret proves it.
+type ApiKeyServiceInterface interface {
+	IssueKey(key *domain.ApiKey) (string, *domain.ApiKey, errorUtils.EntityError)
+	GetKeys(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError)
+	RevokeKey(id uint64) errorUtils.EntityError
+	Authenticate(rawKey string) (*domain.ApiKey, errorUtils.EntityError)
+}
+
+type apiKeyService struct{}
+
+//IssueKey stores the key and returns it in full. This is the only time the secret is known, it cannot be shown again.
+func (a *apiKeyService) IssueKey(key *domain.ApiKey) (string, *domain.ApiKey, errorUtils.EntityError) {
+	if err := key.Validate(); err != nil {
+		return "", nil, err
+	}
+	if _, err := UsersService.GetUser(key.OwnerId); err != nil {
+		if err.Status() == http.StatusNotFound {
+			return "", nil, errorUtils.NewUnprocessableEntityError(fmt.Sprintf("user %d does not exist", key.OwnerId))
+		}
+		return "", nil, err
+	}
+
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", nil, errorUtils.NewInternalServerError(fmt.Sprintf("Api key couldn't be generated by server - %s", err.Error()))
+	}
+	secret, err := authUtils.NewSessionToken()
+	if err != nil {
+		return "", nil, errorUtils.NewInternalServerError(fmt.Sprintf("Api key couldn't be generated by server - %s", err.Error()))
+	}
+
+	key.ID = 0
+	key.Prefix = hex.EncodeToString(prefixBytes)
+	key.SecretHash = authUtils.HashSessionToken(secret)
+	key.Revoked = false
+	key.RevokedAt = nil
+	key.LastUsedAt = nil
+	created, createErr := domain.ApiKeyRepo.Create(key)
+	if createErr != nil {
+		return "", nil, createErr
+	}
+	return strings.Join([]string{ApiKeyTokenPrefix, created.Prefix, secret}, "_"), created, nil
+}
+
+//GetKeys lists the keys of an owner, or every key when ownerId is 0
+func (a *apiKeyService) GetKeys(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError) {
+	if ownerId == 0 {
+		return domain.ApiKeyRepo.GetAll()
+	}
+	return domain.ApiKeyRepo.GetByOwner(ownerId)
+}
+
+func (a *apiKeyService) RevokeKey(id uint64) errorUtils.EntityError {
+	return domain.ApiKeyRepo.Revoke(id, time.Now())
+}
+
+//Authenticate finds the key matching rawKey, as long as it is neither revoked nor expired
+func (a *apiKeyService) Authenticate(rawKey string) (*domain.ApiKey, errorUtils.EntityError) {
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != ApiKeyTokenPrefix || parts[1] == "" || parts[2] == "" {
+		return nil, errorUtils.NewUnauthorizedError("Invalid API key")
+	}
+
+	key, err := domain.ApiKeyRepo.GetByPrefix(parts[1])
+	if err != nil {
+		if err.Status() == http.StatusNotFound {
+			return nil, errorUtils.NewUnauthorizedError("Invalid API key")
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(authUtils.HashSessionToken(parts[2]))) != 1 {
+		return nil, errorUtils.NewUnauthorizedError("Invalid API key")
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, errorUtils.NewUnauthorizedError("API key is expired or revoked")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if touchErr := domain.ApiKeyRepo.TouchLastUsed(key.ID, now); touchErr != nil {
+			log.Printf("could not update last use of api key %s: %s", key.Prefix, touchErr.Message())
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, nil
+}
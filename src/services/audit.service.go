@@ -0,0 +1,81 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+var (
+	AuditService AuditServiceInterface = &auditService{}
+)
+
+type AuditServiceInterface interface {
+	//Record appends an entry for a change made by the request behind ctx, whose actor, API key and request id it carries.
+	//before is nil for a creation, after for a deletion.
+	Record(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError
+	GetEntries(query *domain.AuditQuery) (*domain.AuditPage, errorUtils.EntityError)
+	//Export writes every entry matching the query as a line of JSON, most recent first, whatever the page asked for
+	Export(query *domain.AuditQuery, w io.Writer) errorUtils.EntityError
+}
+
+type auditService struct{}
+
+func (a *auditService) Record(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+	changes, err := domain.AuditDiff(before, after)
+	if err != nil {
+		return errorUtils.NewInternalServerError(err.Error())
+	}
+	entry := &domain.AuditEntry{
+		Resource: resource,
+		Action:   action,
+		EntityId: entityId,
+		Changes:  changes,
+	}
+	entry.ActorId, _ = ctx.Value(domain.RbacUserId()).(uint64)
+	entry.RequestId, _ = ctx.Value(domain.RequestIdKey()).(string)
+	if client, ok := ctx.Value(domain.ApiClientKey()).(*domain.ApiClient); ok {
+		entry.ApiKeyId, entry.ApiKeyPrefix = client.KeyId, client.Prefix
+	}
+
+	_, appendErr := domain.AuditRepo.Append(entry)
+	return appendErr
+}
+
+func (a *auditService) GetEntries(query *domain.AuditQuery) (*domain.AuditPage, errorUtils.EntityError) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	entries, total, err := domain.AuditRepo.Find(query)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuditPage{Items: entries, Total: total, Page: query.Page, PageSize: query.PageSize}, nil
+}
+
+func (a *auditService) Export(query *domain.AuditQuery, w io.Writer) errorUtils.EntityError {
+	//walked from the most recent entry down, so entries added meanwhile do not shift the pages
+	batch := *query
+	batch.Page, batch.PageSize = 1, domain.MaxAuditPageSize
+	if err := batch.Validate(); err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	for {
+		entries, _, err := domain.AuditRepo.Find(&batch)
+		if err != nil {
+			return err
+		}
+		for i := range entries {
+			if err := encoder.Encode(&entries[i]); err != nil {
+				return errorUtils.NewInternalServerError(err.Error())
+			}
+		}
+		if uint64(len(entries)) < batch.PageSize {
+			return nil
+		}
+		batch.BeforeId = entries[len(entries)-1].ID
+	}
+}
@@ -1,13 +1,28 @@
 package services
 
-import "GamesAPI/src/utils/authUtils"
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/authUtils"
+	"GamesAPI/src/utils/errorUtils"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const badCredentialsMessage = "Bad username/password combination"
 
 var (
 	AuthenticationService AuthServiceInterface = &AuthService{}
+
+	//compared against when the email is unknown, so unknown and known emails take as long to reject
+	dummyPasswordHash     string
+	dummyPasswordHashOnce sync.Once
 )
 
 type AuthServiceInterface interface {
 	ValidatePassword(plainPassword []byte, hashedPassword string) (bool, error)
+	Authenticate(email string, password []byte, ip string) (*domain.User, errorUtils.EntityError)
 }
 
 type AuthService struct{}
@@ -15,3 +30,46 @@ type AuthService struct{}
 func (a *AuthService) ValidatePassword(plainPassword []byte, hashedPassword string) (bool, error) {
 	return authUtils.CompareStrings(hashedPassword, plainPassword)
 }
+
+//Authenticate checks the credentials of a login attempt coming from ip.
+//Unknown emails and wrong passwords get the same answer in about the same time, and both count towards the lockout.
+func (a *AuthService) Authenticate(email string, password []byte, ip string) (*domain.User, errorUtils.EntityError) {
+	now := time.Now()
+	if wait := LoginAttemptService.LockedFor(email, ip, now); wait > 0 {
+		return nil, errorUtils.NewTooManyRequestsError(fmt.Sprintf("too many failed login attempts, retry in %d seconds", RetryAfterSeconds(wait)))
+	}
+
+	user, err := UsersService.GetUserByEmail(email)
+	if err != nil && err.Status() != http.StatusNotFound {
+		return nil, err
+	}
+
+	hash := getDummyPasswordHash()
+	if user != nil {
+		hash = user.PasswordHash
+	}
+	isPasswordValid, authErr := a.ValidatePassword(password, hash)
+	if user == nil || authErr != nil || !isPasswordValid {
+		LoginAttemptService.RecordFailure(email, ip, now)
+		return nil, errorUtils.NewUnauthorizedError(badCredentialsMessage)
+	}
+
+	LoginAttemptService.RecordSuccess(email, ip)
+	return user, nil
+}
+
+func getDummyPasswordHash() string {
+	dummyPasswordHashOnce.Do(func() {
+		dummyPasswordHash, _ = authUtils.HashAndSalt([]byte("not the password you are looking for"))
+	})
+	return dummyPasswordHash
+}
+
+//RetryAfterSeconds rounds up, a client retrying after that many seconds is never locked out anymore
+func RetryAfterSeconds(wait time.Duration) int {
+	seconds := int(wait / time.Second)
+	if wait%time.Second != 0 {
+		seconds++
+	}
+	return seconds
+}
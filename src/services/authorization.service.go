@@ -4,60 +4,346 @@ import (
 	"GamesAPI/src/domain"
 	"GamesAPI/src/utils/errorUtils"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
 	"net/url"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
 //Tests were strongly inspired from https://dev.to/bastianrob/rbac-in-rest-api-using-go-5gg0
 //They cover way more cases than our current needs
 
+const DefaultRbacReloadInterval = 5 * time.Second
+
 var (
 	AuthorizationService AuthorizationServiceInterface = &authorizationService{}
 )
 
 type AuthorizationServiceInterface interface {
 	Authorize(ctx context.Context, url *url.URL, role string, resource string, endpoint string) error
+	//AuthorizeRoles checks every role of the user, combined according to the role strategy.
+	//The decision is returned even when access is refused.
+	AuthorizeRoles(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error)
+	//Explain authorizes the request like AuthorizeRoles does, recording every role and rule checked along the way
+	Explain(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) *domain.RbacTrace
 	GetRbac() domain.RBAC
+	//Reload reads the policy again from its source. An invalid policy is rejected and the current one stays in place.
+	Reload() error
+	PolicyInfo() RbacPolicyInfo
+	//Watch reloads the policy when its source changes, checked every interval, and on SIGHUP
+	Watch(interval time.Duration) (stop func())
+}
+
+//RbacPolicyInfo describes the policy being enforced, and the last reload that failed if any
+type RbacPolicyInfo struct {
+	//Path is the policy file, or database
+	Path        string     `json:"path"`
+	Version     int        `json:"version"`
+	Hash        string     `json:"hash"`
+	LoadedAt    time.Time  `json:"loaded_at"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	//Strategy combines the permissions of a user holding several roles
+	Strategy domain.RoleStrategy `json:"strategy"`
+}
+
+//rbacPolicy is swapped as a whole, so a request never sees half of a policy
+type rbacPolicy struct {
+	rbac     domain.RBAC
+	version  int
+	hash     string
+	loadedAt time.Time
 }
 
 type authorizationService struct {
-	rbac domain.RBAC
+	source   RbacPolicySource
+	strategy domain.RoleStrategy
+	//what the routes expose, reloaded policies are linted against it. Only the routes-independent checks are made when nil.
+	surface *domain.RbacSurface
+	policy  atomic.Value
+
+	//serializes reloads, and the use of source. Requests only ever read policy.
+	reloadMutex sync.Mutex
+	lastError   string
+	lastErrorAt *time.Time
 }
 
-//Constructor - must be instantiated with a role-based access YAML file
+//Constructor - must be instantiated with a role-based access YAML file.
+//There is no policy to fall back on yet, so an invalid file is fatal here, unlike on reloads.
 func NewAuthorizationService(path string) *authorizationService {
-	ret := &authorizationService{}
-	rbac, err := domain.RbacFromFile(path)
-	if err != nil {
+	return NewAuthorizationServiceWithStrategy(path, domain.DefaultRoleStrategy)
+}
+
+//Constructor - same as NewAuthorizationService, combining the roles of a user with strategy
+func NewAuthorizationServiceWithStrategy(path string, strategy domain.RoleStrategy) *authorizationService {
+	return NewAuthorizationServiceFromSource(NewRbacFileSource(path), strategy, nil)
+}
+
+//Constructor - reads the policy from source, see RbacSourceFile and RbacSourceDatabase.
+//Every policy loaded, at startup and on reloads, must fit surface.
+func NewAuthorizationServiceFromSource(source RbacPolicySource, strategy domain.RoleStrategy, surface *domain.RbacSurface) *authorizationService {
+	ret := &authorizationService{source: source, strategy: strategy, surface: surface}
+	if err := ret.Reload(); err != nil {
 		panic(err)
 	}
-	ret.rbac = rbac
 	return ret
 }
 
+func (a *authorizationService) current() *rbacPolicy {
+	policy, _ := a.policy.Load().(*rbacPolicy)
+	if policy == nil {
+		return &rbacPolicy{}
+	}
+	return policy
+}
+
 func (a *authorizationService) Authorize(ctx context.Context, url *url.URL, role string, resource string, endpoint string) error {
-	permission, exists := a.rbac[role][resource][endpoint]
+	_, err := a.AuthorizeRoles(ctx, url, []string{role}, resource, endpoint)
+	return err
+}
+
+func (a *authorizationService) AuthorizeRoles(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+	decision := &domain.RbacDecision{Roles: roles, Strategy: a.strategy, Resource: resource, Endpoint: endpoint}
+	if len(roles) < 1 {
+		return decision, errorUtils.ErrNoRole
+	}
+	//a reload in the middle of the loop must not mix two policies
+	rbac := a.current().rbac
+	//only explained requests are traced
+	trace := domain.RbacTraceFromContext(ctx)
+	skipRoles := func(from int) {
+		for _, role := range roles[from:] {
+			trace.AddRole(domain.RbacRoleTrace{Role: role, Checks: []domain.RbacRuleCheck{}, Outcome: domain.RbacOutcomeSkipped})
+		}
+	}
 
-	if !exists {
-		return errorUtils.ErrRoleUnknown
+	//when no role grants access, the most specific reason is reported: a rule violation, then a denial
+	var granted *domain.Permission
+	var refusal error = errorUtils.ErrRoleUnknown
+	for i, role := range roles {
+		permission, entry, exists := rbac.PermissionEntry(role, resource, endpoint)
+		step := domain.RbacRoleTrace{Role: role, Entry: entry, Allow: permission.Allow, Checks: []domain.RbacRuleCheck{}}
+		if permission.DeclaredBy != role {
+			step.DeclaredBy = permission.DeclaredBy
+		}
+		if !exists {
+			step.Outcome = domain.RbacOutcomeNoPermission
+			trace.AddRole(step)
+			continue
+		}
+		if !permission.Allow {
+			step.Outcome = domain.RbacOutcomeDenied
+			trace.AddRole(step)
+			if a.strategy == domain.RoleStrategyDenyOverrides {
+				skipRoles(i + 1)
+				decision.Role, decision.Fields = "", nil
+				return decision, errorUtils.ErrForbidden
+			}
+			if refusal == errorUtils.ErrRoleUnknown {
+				refusal = errorUtils.ErrForbidden
+			}
+			continue
+		}
+		err := ensure(ctx, url, permission)
+		step.Checks = trace.TakeChecks()
+		if err != nil {
+			step.Outcome, step.Error = domain.RbacOutcomeViolated, err.Error()
+			trace.AddRole(step)
+			if refusal == errorUtils.ErrRoleUnknown || refusal == errorUtils.ErrForbidden {
+				refusal = err
+			}
+			continue
+		}
+		step.Outcome = domain.RbacOutcomeGranted
+		trace.AddRole(step)
+		if granted == nil {
+			granted, decision.Role = &permission, role
+			decision.Fields = visibleFields(rbac, role, resource, permission)
+		}
+		//every role has to be checked for an explicit denial
+		if a.strategy != domain.RoleStrategyDenyOverrides {
+			skipRoles(i + 1)
+			break
+		}
 	}
+	if granted == nil {
+		return decision, refusal
+	}
+
+	//only the rules of the role granting access are enforced
+	err := enforce(ctx, url, *granted)
+	if trace != nil {
+		trace.Enforced = trace.TakeChecks()
+	}
+	if err != nil {
+		decision.Role, decision.Fields = "", nil
+		return decision, err
+	}
+	return decision, nil
+}
+
+//visibleFields are the fields of the permission granting access, those of the read permission of the resource
+//when it does not restrict the visible ones
+func visibleFields(rbac domain.RBAC, role string, resource string, granted domain.Permission) *domain.RbacFields {
+	if granted.Fields != nil && len(granted.Fields.Visible) > 0 {
+		return granted.Fields
+	}
+	read, _ := rbac.Permission(role, resource, "read")
+	return read.Fields
+}
 
-	if !permission.Allow {
-		return errorUtils.ErrForbidden
+func (a *authorizationService) Explain(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) *domain.RbacTrace {
+	trace := &domain.RbacTrace{
+		Resource: resource,
+		Endpoint: endpoint,
+		Strategy: a.strategy,
+		Roles:    []domain.RbacRoleTrace{},
+		Enforced: []domain.RbacRuleCheck{},
 	}
+	decision, err := a.AuthorizeRoles(domain.WithRbacTrace(ctx, trace), url, roles, resource, endpoint)
+	trace.Allowed, trace.Role = err == nil, decision.Role
+	if err != nil {
+		trace.Error = err.Error()
+	}
+	return trace
+}
 
+func ensure(ctx context.Context, url *url.URL, permission domain.Permission) error {
 	err := permission.Ensure.QueryComplies(ctx, url)
 	if err != nil {
 		return err
 	}
 
-	err = permission.Enforce.QueryComplies(ctx, url)
+	err = permission.Ensure.PathComplies(ctx)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	err = permission.Ensure.HeaderComplies(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = permission.Ensure.BodyComplies(ctx)
+	if err != nil {
+		return err
+	}
+
+	return permission.Fields.WritableComplies(ctx)
+}
+
+func enforce(ctx context.Context, url *url.URL, permission domain.Permission) error {
+	err := permission.Enforce.QueryComplies(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	return permission.Enforce.HeaderComplies(ctx)
 }
 
 func (a *authorizationService) GetRbac() domain.RBAC {
-	return a.rbac
+	return a.current().rbac
+}
+
+func (a *authorizationService) Reload() error {
+	a.reloadMutex.Lock()
+	defer a.reloadMutex.Unlock()
+
+	if a.source == nil {
+		return a.reloadFailed(errors.New("rbac policy has no source"))
+	}
+	content, err := a.source.Read()
+	if err != nil {
+		return a.reloadFailed(err)
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	current := a.current()
+	//the enforced policy is back in the source, whatever was rejected in between is not pending anymore
+	if hash == current.hash {
+		a.lastError, a.lastErrorAt = "", nil
+		return nil
+	}
+
+	rbac, err := domain.RbacFromBytesForSurface(content, a.surface)
+	if err != nil {
+		return a.reloadFailed(err)
+	}
+	a.policy.Store(&rbacPolicy{rbac: rbac, version: current.version + 1, hash: hash, loadedAt: time.Now()})
+	a.lastError, a.lastErrorAt = "", nil
+	if current.version > 0 {
+		log.Printf("rbac policy %s reloaded, version %d (%s)", a.sourceName(), current.version+1, hash)
+	}
+	return nil
+}
+
+func (a *authorizationService) reloadFailed(err error) error {
+	now := time.Now()
+	a.lastError, a.lastErrorAt = err.Error(), &now
+	if a.current().version > 0 {
+		log.Printf("rbac policy %s rejected, still enforcing version %d: %s", a.sourceName(), a.current().version, err.Error())
+	}
+	return err
+}
+
+func (a *authorizationService) PolicyInfo() RbacPolicyInfo {
+	a.reloadMutex.Lock()
+	defer a.reloadMutex.Unlock()
+
+	policy := a.current()
+	return RbacPolicyInfo{
+		Path:        a.sourceName(),
+		Version:     policy.version,
+		Hash:        policy.hash,
+		LoadedAt:    policy.loadedAt,
+		Strategy:    a.strategy,
+		LastError:   a.lastError,
+		LastErrorAt: a.lastErrorAt,
+	}
+}
+
+func (a *authorizationService) sourceName() string {
+	if a.source == nil {
+		return ""
+	}
+	return a.source.String()
+}
+
+func (a *authorizationService) sourceChanged() bool {
+	a.reloadMutex.Lock()
+	defer a.reloadMutex.Unlock()
+	return a.source != nil && a.source.Changed()
+}
+
+func (a *authorizationService) Watch(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-hangup:
+				_ = a.Reload()
+			case <-ticker.C:
+				if a.sourceChanged() {
+					_ = a.Reload()
+				}
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		signal.Stop(hangup)
+		close(done)
+	}
 }
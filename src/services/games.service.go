@@ -3,6 +3,7 @@ package services
 import (
 	"GamesAPI/src/domain"
 	"GamesAPI/src/utils/errorUtils"
+	"fmt"
 )
 
 var (
@@ -16,8 +17,9 @@ type GamesServiceInterface interface {
 	CreateGame(*domain.Game) (*domain.Game, errorUtils.EntityError)
 	UpdateGame(game *domain.Game) (*domain.Game, errorUtils.EntityError)
 	DeleteGame(uint64) errorUtils.EntityError
-	GetAllGames() ([]domain.Game, errorUtils.EntityError)
+	GetAllGames(query *domain.GameQuery) (*domain.GamePage, errorUtils.EntityError)
 	ExistsWithSteamID(id string) (bool, errorUtils.EntityError)
+	GetGameBySteamID(id string) (*domain.Game, errorUtils.EntityError)
 }
 
 func (g *gamesService) GetGame(gameId uint64) (*domain.Game, errorUtils.EntityError) {
@@ -28,12 +30,21 @@ func (g *gamesService) GetGame(gameId uint64) (*domain.Game, errorUtils.EntityEr
 	return game, nil
 }
 
-func (g *gamesService) GetAllGames() ([]domain.Game, errorUtils.EntityError) {
-	games, err := domain.GameRepo.GetAll()
+func (g *gamesService) GetAllGames(query *domain.GameQuery) (*domain.GamePage, errorUtils.EntityError) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	games, total, err := domain.GameRepo.GetAll(query)
 	if err != nil {
 		return nil, err
 	}
-	return games, nil
+	return &domain.GamePage{
+		Items:    games,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
 }
 
 func (g *gamesService) CreateGame(game *domain.Game) (*domain.Game, errorUtils.EntityError) {
@@ -84,16 +95,26 @@ func (g *gamesService) DeleteGame(gameId uint64) errorUtils.EntityError {
 }
 
 func (g *gamesService) ExistsWithSteamID(id string) (bool, errorUtils.EntityError) {
-	games, err := domain.GameRepo.GetAll()
-	exists := false
+	query := domain.NewGameQuery()
+	query.SteamId = id
+	query.PageSize = 1
+	_, total, err := domain.GameRepo.GetAll(query)
 	if err != nil {
 		return false, err
 	}
-	for _, game := range games {
-		if game.SteamId == id {
-			exists = true
-			break
-		}
+	return total > 0, nil
+}
+
+func (g *gamesService) GetGameBySteamID(id string) (*domain.Game, errorUtils.EntityError) {
+	query := domain.NewGameQuery()
+	query.SteamId = id
+	query.PageSize = 1
+	games, _, err := domain.GameRepo.GetAll(query)
+	if err != nil {
+		return nil, err
 	}
-	return exists, nil
-}
\ No newline at end of file
+	if len(games) == 0 {
+		return nil, errorUtils.NewNotFoundError(fmt.Sprintf("no game with steam id %s", id))
+	}
+	return &games[0], nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"GamesAPI/src/utils/authUtils"
+	"GamesAPI/src/utils/errorUtils"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"time"
+)
+
+var (
+	//JwtService is disabled until keys are configured, see NewJwtServiceFromEnv
+	JwtService JwtServiceInterface = NewJwtService(nil)
+)
+
+//JwtServiceInterface issues and verifies stateless access tokens.
+//A JWT carries the user id and roles, so nothing is stored server side and a JWT cannot be revoked:
+//it simply lives until it expires, which is why access tokens should stay short-lived.
+type JwtServiceInterface interface {
+	Enabled() bool
+	IssueAccessToken(userId uint64, roles []string, expiresAt time.Time) (string, errorUtils.EntityError)
+	Verify(token string) (*authUtils.UserClaims, errorUtils.EntityError)
+	JWKS() authUtils.JWKS
+}
+
+type jwtService struct {
+	keys *authUtils.JwtKeySet
+}
+
+//Constructor - a nil key set leaves the service disabled
+func NewJwtService(keys *authUtils.JwtKeySet) JwtServiceInterface {
+	return &jwtService{keys: keys}
+}
+
+//NewJwtServiceFromEnv loads the keys described by JWT_ALGORITHM, JWT_SECRET, JWT_KEYS and JWT_ACTIVE_KID
+func NewJwtServiceFromEnv() (JwtServiceInterface, error) {
+	keys, err := authUtils.JwtKeySetFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	authUtils.JwtKeys = keys
+	return NewJwtService(keys), nil
+}
+
+func (j *jwtService) Enabled() bool {
+	return j.keys != nil
+}
+
+func (j *jwtService) IssueAccessToken(userId uint64, roles []string, expiresAt time.Time) (string, errorUtils.EntityError) {
+	if !j.Enabled() {
+		return "", errorUtils.NewInternalServerError("jwt authentication is not enabled")
+	}
+	now := time.Now()
+	token, err := j.keys.Sign(&authUtils.UserClaims{
+		UserId: userId,
+		Roles:  roles,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   fmt.Sprintf("%d", userId),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	})
+	if err != nil {
+		return "", errorUtils.NewInternalServerError(fmt.Sprintf("Token couldn't be generated by server - %s", err.Error()))
+	}
+	return token, nil
+}
+
+func (j *jwtService) Verify(token string) (*authUtils.UserClaims, errorUtils.EntityError) {
+	if !j.Enabled() {
+		return nil, errorUtils.NewUnauthorizedError("jwt authentication is not enabled")
+	}
+	claims, err := j.keys.Parse(token)
+	if err != nil {
+		return nil, errorUtils.NewUnauthorizedError(fmt.Sprintf("invalid token - %s", err.Error()))
+	}
+	return claims, nil
+}
+
+func (j *jwtService) JWKS() authUtils.JWKS {
+	if !j.Enabled() {
+		return authUtils.JWKS{Keys: []authUtils.JWK{}}
+	}
+	return j.keys.JWKS()
+}
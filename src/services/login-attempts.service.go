@@ -0,0 +1,157 @@
+package services
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxFailedLoginsPerAccount = 5
+	DefaultMaxFailedLoginsPerIP      = 20
+	DefaultLoginAttemptWindow        = 15 * time.Minute
+	DefaultLoginLockout              = 15 * time.Minute
+)
+
+var (
+	LoginAttemptService LoginAttemptServiceInterface = NewLoginAttemptService(LoginAttemptConfig{})
+)
+
+//LoginAttemptServiceInterface keeps track of failed logins, per account and per client IP.
+//Too many failures within the window lock the account (or the IP) out for a while.
+type LoginAttemptServiceInterface interface {
+	LockedFor(email string, ip string, now time.Time) time.Duration
+	RecordFailure(email string, ip string, now time.Time)
+	RecordSuccess(email string, ip string)
+	DeleteExpired(now time.Time) int
+}
+
+//LoginAttemptConfig holds the lockout policy. Zero values are replaced by the defaults.
+type LoginAttemptConfig struct {
+	MaxPerAccount int
+	MaxPerIP      int
+	Window        time.Duration
+	Lockout       time.Duration
+}
+
+//LoginAttemptConfigFromEnv reads LOGIN_MAX_ATTEMPTS, LOGIN_MAX_ATTEMPTS_PER_IP,
+//LOGIN_ATTEMPT_WINDOW (duration) and LOGIN_LOCKOUT (duration)
+func LoginAttemptConfigFromEnv() LoginAttemptConfig {
+	config := LoginAttemptConfig{}
+	if max, err := strconv.Atoi(os.Getenv("LOGIN_MAX_ATTEMPTS")); err == nil {
+		config.MaxPerAccount = max
+	}
+	if max, err := strconv.Atoi(os.Getenv("LOGIN_MAX_ATTEMPTS_PER_IP")); err == nil {
+		config.MaxPerIP = max
+	}
+	config.Window = lifetimeFromEnv("LOGIN_ATTEMPT_WINDOW", 0)
+	config.Lockout = lifetimeFromEnv("LOGIN_LOCKOUT", 0)
+	return config
+}
+
+type loginAttempts struct {
+	failures    int
+	firstFailAt time.Time
+	lockedUntil time.Time
+}
+
+//loginAttemptService lives in memory, every API instance tracks the attempts it has seen
+type loginAttemptService struct {
+	mutex    sync.Mutex
+	config   LoginAttemptConfig
+	attempts map[string]*loginAttempts
+}
+
+//Constructor
+func NewLoginAttemptService(config LoginAttemptConfig) LoginAttemptServiceInterface {
+	if config.MaxPerAccount <= 0 {
+		config.MaxPerAccount = DefaultMaxFailedLoginsPerAccount
+	}
+	if config.MaxPerIP <= 0 {
+		config.MaxPerIP = DefaultMaxFailedLoginsPerIP
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultLoginAttemptWindow
+	}
+	if config.Lockout <= 0 {
+		config.Lockout = DefaultLoginLockout
+	}
+	return &loginAttemptService{
+		config:   config,
+		attempts: map[string]*loginAttempts{},
+	}
+}
+
+//accounts are tracked whether they exist or not, so a lockout tells nothing about the email
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+//LockedFor returns how long the account or IP is still locked out, 0 when logging in is allowed
+func (l *loginAttemptService) LockedFor(email string, ip string, now time.Time) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var wait time.Duration
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		if attempts, exists := l.attempts[key]; exists && attempts.lockedUntil.After(now) {
+			if remaining := attempts.lockedUntil.Sub(now); remaining > wait {
+				wait = remaining
+			}
+		}
+	}
+	return wait
+}
+
+func (l *loginAttemptService) RecordFailure(email string, ip string, now time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.fail(accountKey(email), l.config.MaxPerAccount, now)
+	l.fail(ipKey(ip), l.config.MaxPerIP, now)
+}
+
+func (l *loginAttemptService) fail(key string, max int, now time.Time) {
+	attempts, exists := l.attempts[key]
+	if !exists || now.Sub(attempts.firstFailAt) > l.config.Window {
+		attempts = &loginAttempts{firstFailAt: now}
+		l.attempts[key] = attempts
+	}
+	attempts.failures++
+	if attempts.failures >= max {
+		//the count starts over once the lockout is served
+		attempts.lockedUntil = now.Add(l.config.Lockout)
+		attempts.failures = 0
+		attempts.firstFailAt = now
+	}
+}
+
+//RecordSuccess forgets the failures of the account. The IP keeps its count, otherwise
+//logging into an account of their own would let an attacker reset it.
+func (l *loginAttemptService) RecordSuccess(email string, _ string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.attempts, accountKey(email))
+}
+
+//DeleteExpired forgets the accounts and IPs that are neither locked nor within the window anymore
+func (l *loginAttemptService) DeleteExpired(now time.Time) int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	deleted := 0
+	for key, attempts := range l.attempts {
+		if !attempts.lockedUntil.After(now) && now.Sub(attempts.firstFailAt) > l.config.Window {
+			delete(l.attempts, key)
+			deleted++
+		}
+	}
+	return deleted
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	//RateLimitService throttles nothing until limits are configured, see NewRateLimitServiceFromEnv
+	RateLimitService RateLimitServiceInterface = NewRateLimitService(nil, NewMemoryRateLimitStore())
+)
+
+//RateLimitResult is the state of a client's bucket once a request has been counted
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	//Reset is how long until the bucket is full again
+	Reset time.Duration
+	//RetryAfter is how long to wait before the next request goes through, 0 when allowed
+	RetryAfter time.Duration
+}
+
+//RateLimitStoreInterface holds the token buckets. The in-memory store only sees the requests of its own instance,
+//a store backed by a shared cache is needed for the limits to hold across instances.
+type RateLimitStoreInterface interface {
+	//Take counts a request against every bucket of keys, or against none of them when one is empty.
+	//The result is the one of the most restrictive bucket.
+	Take(keys []string, limit domain.RateLimit, now time.Time) (RateLimitResult, error)
+	DeleteExpired(now time.Time) int
+}
+
+type RateLimitServiceInterface interface {
+	//Allow counts a request of the clients against the limit of the route group, a request refused by one of them
+	//is counted against none. ok is false when the group is not throttled, in which case the result is meaningless.
+	Allow(group string, clients []string, now time.Time) (result RateLimitResult, ok bool, err error)
+	DeleteExpired(now time.Time) int
+}
+
+type rateLimitService struct {
+	limits domain.RateLimits
+	store  RateLimitStoreInterface
+}
+
+//Constructor - nil limits throttle nothing
+func NewRateLimitService(limits domain.RateLimits, store RateLimitStoreInterface) RateLimitServiceInterface {
+	return &rateLimitService{limits: limits, store: store}
+}
+
+//NewRateLimitServiceFromEnv loads the limits of the YAML file at RATE_LIMIT_FILEPATH, nothing is throttled when it is not set
+func NewRateLimitServiceFromEnv() (RateLimitServiceInterface, error) {
+	path := os.Getenv("RATE_LIMIT_FILEPATH")
+	if path == "" {
+		return NewRateLimitService(nil, NewMemoryRateLimitStore()), nil
+	}
+	limits, err := domain.RateLimitsFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewRateLimitService(limits, NewMemoryRateLimitStore()), nil
+}
+
+func (r *rateLimitService) Allow(group string, clients []string, now time.Time) (RateLimitResult, bool, error) {
+	limit, exists := r.limits.For(group)
+	if !exists {
+		return RateLimitResult{}, false, nil
+	}
+	//every group has buckets of its own, a client busy syncing still gets to read its library
+	keys := make([]string, len(clients))
+	for i, client := range clients {
+		keys[i] = group + "|" + client
+	}
+	result, err := r.store.Take(keys, limit, now)
+	return result, true, err
+}
+
+func (r *rateLimitService) DeleteExpired(now time.Time) int {
+	return r.store.DeleteExpired(now)
+}
+
+type tokenBucket struct {
+	tokens   float64
+	last     time.Time
+	capacity float64
+	//tokens gained per second
+	rate float64
+}
+
+//memoryRateLimitStore keeps a token bucket per key
+type memoryRateLimitStore struct {
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+//Constructor
+func NewMemoryRateLimitStore() RateLimitStoreInterface {
+	return &memoryRateLimitStore{buckets: map[string]*tokenBucket{}}
+}
+
+func (m *memoryRateLimitStore) Take(keys []string, limit domain.RateLimit, now time.Time) (RateLimitResult, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	capacity := float64(limit.Capacity())
+	rate := float64(limit.Requests) / limit.Period.Seconds()
+	buckets := make([]*tokenBucket, len(keys))
+	allowed := true
+	for i, key := range keys {
+		bucket, exists := m.buckets[key]
+		if !exists || bucket.capacity != capacity || bucket.rate != rate {
+			//new client, or the limit changed: start over with a full bucket
+			bucket = &tokenBucket{tokens: capacity, last: now, capacity: capacity, rate: rate}
+			m.buckets[key] = bucket
+		}
+		bucket.refill(now)
+		buckets[i] = bucket
+		allowed = allowed && bucket.tokens >= 1
+	}
+
+	var limiting *RateLimitResult
+	for _, bucket := range buckets {
+		result := RateLimitResult{Limit: limit.Capacity(), Allowed: allowed}
+		if allowed {
+			bucket.tokens--
+		} else {
+			result.RetryAfter = bucket.timeUntil(1)
+		}
+		result.Remaining = int(math.Floor(bucket.tokens))
+		result.Reset = bucket.timeUntil(capacity)
+		if limiting == nil || result.RetryAfter > limiting.RetryAfter ||
+			(result.RetryAfter == limiting.RetryAfter && result.Remaining < limiting.Remaining) {
+			limiting = &result
+		}
+	}
+	if limiting == nil {
+		return RateLimitResult{Limit: limit.Capacity(), Allowed: true, Remaining: limit.Capacity()}, nil
+	}
+	return *limiting, nil
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+//timeUntil is how long until the bucket holds that many tokens
+func (b *tokenBucket) timeUntil(tokens float64) time.Duration {
+	if b.tokens >= tokens {
+		return 0
+	}
+	return time.Duration((tokens - b.tokens) / b.rate * float64(time.Second))
+}
+
+//DeleteExpired forgets the buckets that are full again, a new bucket would be just the same
+func (m *memoryRateLimitStore) DeleteExpired(now time.Time) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	deleted := 0
+	for key, bucket := range m.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= bucket.capacity {
+			delete(m.buckets, key)
+			deleted++
+		}
+	}
+	return deleted
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	RbacPolicyService RbacPolicyServiceInterface = &rbacPolicyService{}
+
+	//the resource of the routes managing the policy
+	rbacPolicyResource = "rbac"
+
+	//role names are compared with the lowercased roles of the users
+	rbacRoleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+	rbacNamePattern     = regexp.MustCompile(`^([a-z][a-z0-9_]*|\*)$`)
+)
+
+//RbacPolicyServiceInterface manages the policy stored in the database. Every change is checked like the policy file is at startup,
+//stored as a new version of the policy, and enforced right away.
+type RbacPolicyServiceInterface interface {
+	GetPolicy() (domain.RbacPolicy, errorUtils.EntityError)
+	PutRole(name string, role domain.RbacPolicyRole, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError)
+	DeleteRole(name string, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError)
+	PutPermission(role string, resource string, action string, permission domain.Permission, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError)
+	DeletePermission(role string, resource string, action string, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError)
+	GetVersions() ([]domain.RbacPolicyVersion, errorUtils.EntityError)
+	GetVersion(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError)
+	//Rollback stores the policy of version as a new version
+	Rollback(version uint64, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError)
+	//Import stores the policy file as the first version, when the database holds no policy yet
+	Import(path string) errorUtils.EntityError
+}
+
+//RbacPolicyUpdate is the version a change was stored as, and what lint has to say about the policy
+type RbacPolicyUpdate struct {
+	Version  *domain.RbacPolicyVersion `json:"version"`
+	Warnings []string                  `json:"warnings"`
+}
+
+type rbacPolicyService struct {
+	//changes are checked against the routes when known
+	surface *domain.RbacSurface
+	enabled bool
+	//serializes changes, the repository refuses those of other instances made in between
+	mutex sync.Mutex
+}
+
+//Constructor - the policy has to be read from the database for its changes to be enforced, see RbacSourceDatabase
+func NewRbacPolicyService(surface *domain.RbacSurface) RbacPolicyServiceInterface {
+	return &rbacPolicyService{surface: surface, enabled: true}
+}
+
+func (r *rbacPolicyService) checkEnabled() errorUtils.EntityError {
+	if !r.enabled {
+		return errorUtils.NewConflictError("the rbac policy is read from a file, set RBAC_SOURCE=database to manage it through the API")
+	}
+	return nil
+}
+
+func (r *rbacPolicyService) GetPolicy() (domain.RbacPolicy, errorUtils.EntityError) {
+	if err := r.checkEnabled(); err != nil {
+		return nil, err
+	}
+	roles, err := domain.RbacPolicyRepo.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+	policy, policyErr := domain.RbacPolicyFromRoles(roles)
+	if policyErr != nil {
+		return nil, errorUtils.NewInternalServerError(policyErr.Error())
+	}
+	return policy, nil
+}
+
+func (r *rbacPolicyService) PutRole(name string, role domain.RbacPolicyRole, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError) {
+	if !rbacRoleNamePattern.MatchString(name) {
+		return nil, errorUtils.NewUnprocessableEntityError(fmt.Sprintf("role name '%s' should be lowercase letters, digits, - and _", name))
+	}
+	for resourceName, endpoints := range role.Resources {
+		for actionName := range endpoints {
+			if err := checkRbacNames(resourceName, actionName); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return r.apply(authorId, "put role "+name, func(policy domain.RbacPolicy) (domain.RbacPolicy, errorUtils.EntityError) {
+		policy[name] = role
+		return policy, nil
+	})
+}
+
+func (r *rbacPolicyService) DeleteRole(name string, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError) {
+	return r.apply(authorId, "delete role "+name, func(policy domain.RbacPolicy) (domain.RbacPolicy, errorUtils.EntityError) {
+		if _, exists := policy[name]; !exists {
+			return nil, errorUtils.NewNotFoundError(fmt.Sprintf("role %s does not exist", name))
+		}
+		delete(policy, name)
+		return policy, nil
+	})
+}
+
+func (r *rbacPolicyService) PutPermission(role string, resource string, action string, permission domain.Permission, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError) {
+	if err := checkRbacNames(resource, action); err != nil {
+		return nil, err
+	}
+	where := strings.Join([]string{role, resource, action}, ".")
+	return r.apply(authorId, "put permission "+where, func(policy domain.RbacPolicy) (domain.RbacPolicy, errorUtils.EntityError) {
+		existing, exists := policy[role]
+		if !exists {
+			return nil, errorUtils.NewNotFoundError(fmt.Sprintf("role %s does not exist", role))
+		}
+		if existing.Resources == nil {
+			existing.Resources = domain.Resource{}
+		}
+		if existing.Resources[resource] == nil {
+			existing.Resources[resource] = domain.Endpoint{}
+		}
+		existing.Resources[resource][action] = permission
+		policy[role] = existing
+		return policy, nil
+	})
+}
+
+func (r *rbacPolicyService) DeletePermission(role string, resource string, action string, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError) {
+	where := strings.Join([]string{role, resource, action}, ".")
+	return r.apply(authorId, "delete permission "+where, func(policy domain.RbacPolicy) (domain.RbacPolicy, errorUtils.EntityError) {
+		if _, exists := policy[role].Resources[resource][action]; !exists {
+			return nil, errorUtils.NewNotFoundError(fmt.Sprintf("permission %s does not exist", where))
+		}
+		delete(policy[role].Resources[resource], action)
+		if len(policy[role].Resources[resource]) == 0 {
+			delete(policy[role].Resources, resource)
+		}
+		return policy, nil
+	})
+}
+
+func (r *rbacPolicyService) GetVersions() ([]domain.RbacPolicyVersion, errorUtils.EntityError) {
+	if err := r.checkEnabled(); err != nil {
+		return nil, err
+	}
+	return domain.RbacPolicyRepo.GetVersions()
+}
+
+func (r *rbacPolicyService) GetVersion(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError) {
+	if err := r.checkEnabled(); err != nil {
+		return nil, err
+	}
+	return domain.RbacPolicyRepo.GetVersion(version)
+}
+
+func (r *rbacPolicyService) Rollback(version uint64, authorId uint64) (*RbacPolicyUpdate, errorUtils.EntityError) {
+	if err := r.checkEnabled(); err != nil {
+		return nil, err
+	}
+	snapshot, err := domain.RbacPolicyRepo.GetVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return r.apply(authorId, fmt.Sprintf("rollback to version %d", version), func(domain.RbacPolicy) (domain.RbacPolicy, errorUtils.EntityError) {
+		policy, parseErr := domain.ParseRbacPolicy([]byte(snapshot.Content))
+		if parseErr != nil {
+			return nil, errorUtils.NewInternalServerError(fmt.Sprintf("version %d could not be read - %s", version, parseErr.Error()))
+		}
+		return policy, nil
+	})
+}
+
+func (r *rbacPolicyService) Import(path string) errorUtils.EntityError {
+	if err := r.checkEnabled(); err != nil {
+		return err
+	}
+	_, latestErr := domain.RbacPolicyRepo.GetLatestVersion()
+	if latestErr == nil {
+		return nil
+	}
+	if latestErr.Status() != http.StatusNotFound {
+		return latestErr
+	}
+	content, readErr := ioutil.ReadFile(path)
+	if readErr != nil {
+		return errorUtils.NewInternalServerError(fmt.Sprintf("rbac policy %s could not be imported - %s", path, readErr.Error()))
+	}
+	_, err := r.update(0, "imported from "+path, func(domain.RbacPolicy) (domain.RbacPolicy, errorUtils.EntityError) {
+		policy, parseErr := domain.ParseRbacPolicy(content)
+		if parseErr != nil {
+			return nil, errorUtils.NewUnprocessableEntityError(parseErr.Error())
+		}
+		return policy, nil
+	})
+	return err
+}
+
+//apply stores a change made through the API, and has it enforced right away rather than on the next check for changes
+func (r *rbacPolicyService) apply(authorId uint64, comment string, change func(domain.RbacPolicy) (domain.RbacPolicy, errorUtils.EntityError)) (*RbacPolicyUpdate, errorUtils.EntityError) {
+	update, err := r.update(authorId, comment, func(current domain.RbacPolicy) (domain.RbacPolicy, errorUtils.EntityError) {
+		policy, err := change(current)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.checkAdminManagesPolicy(policy); err != nil {
+			return nil, err
+		}
+		return policy, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reloadErr := AuthorizationService.Reload(); reloadErr != nil {
+		return nil, errorUtils.NewInternalServerError(fmt.Sprintf("rbac policy version %d was stored but could not be enforced - %s", update.Version.Version, reloadErr.Error()))
+	}
+	return update, nil
+}
+
+//checkAdminManagesPolicy refuses a policy the admin role could not change back through the API,
+//it would only be repaired by editing the database
+func (r *rbacPolicyService) checkAdminManagesPolicy(policy domain.RbacPolicy) errorUtils.EntityError {
+	rbac, resolveErr := policy.Resolve()
+	if resolveErr != nil {
+		//reported by lint
+		return nil
+	}
+	//without the routes, at least what it takes to fix the policy
+	actions := []string{"read", "update", "rollback"}
+	if r.surface != nil && len(r.surface.Resources[rbacPolicyResource]) > 0 {
+		actions = actions[:0]
+		for action := range r.surface.Resources[rbacPolicyResource] {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+	}
+	var lost []string
+	for _, action := range actions {
+		if permission, exists := rbac.Permission(domain.AdminRoleName, rbacPolicyResource, action); !exists || !permission.Allow {
+			lost = append(lost, rbacPolicyResource+"."+action)
+		}
+	}
+	if len(lost) > 0 {
+		return errorUtils.NewConflictError(fmt.Sprintf("the %s role would lose %s, and nobody could manage the rbac policy anymore", domain.AdminRoleName, strings.Join(lost, ", ")))
+	}
+	return nil
+}
+
+//update applies change to the stored policy, and stores the result as a new version if it passes lint
+func (r *rbacPolicyService) update(authorId uint64, comment string, change func(domain.RbacPolicy) (domain.RbacPolicy, errorUtils.EntityError)) (*RbacPolicyUpdate, errorUtils.EntityError) {
+	if err := r.checkEnabled(); err != nil {
+		return nil, err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var latestVersion uint64
+	latest, latestErr := domain.RbacPolicyRepo.GetLatestVersion()
+	if latestErr == nil {
+		latestVersion = latest.Version
+	} else if latestErr.Status() != http.StatusNotFound {
+		return nil, latestErr
+	}
+	current, err := r.GetPolicy()
+	if err != nil {
+		return nil, err
+	}
+	policy, err := change(current)
+	if err != nil {
+		return nil, err
+	}
+
+	//what gets checked is what the authorization service will read back
+	content, marshalErr := policy.Marshal()
+	if marshalErr != nil {
+		return nil, errorUtils.NewInternalServerError(marshalErr.Error())
+	}
+	issues, lintErr := domain.LintRbacBytes(content, r.surface)
+	if lintErr != nil {
+		return nil, errorUtils.NewUnprocessableEntityError(lintErr.Error())
+	}
+	var errors, warnings []string
+	for _, issue := range issues {
+		if issue.Severity == domain.RbacIssueError {
+			errors = append(errors, issue.String())
+		} else {
+			warnings = append(warnings, issue.String())
+		}
+	}
+	if len(errors) > 0 {
+		return nil, errorUtils.NewUnprocessableEntityError(strings.Join(errors, "\n"))
+	}
+
+	sum := sha256.Sum256(content)
+	version := &domain.RbacPolicyVersion{
+		Version:  latestVersion + 1,
+		AuthorId: authorId,
+		Comment:  comment,
+		Hash:     hex.EncodeToString(sum[:]),
+		Content:  string(content),
+	}
+	if err := domain.RbacPolicyRepo.Save(domain.RbacRolesFromPolicy(policy), version); err != nil {
+		return nil, err
+	}
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return &RbacPolicyUpdate{Version: version, Warnings: warnings}, nil
+}
+
+func checkRbacNames(resource string, action string) errorUtils.EntityError {
+	if !rbacNamePattern.MatchString(resource) {
+		return errorUtils.NewUnprocessableEntityError(fmt.Sprintf("resource name '%s' should be lowercase letters, digits and _, or *", resource))
+	}
+	if !rbacNamePattern.MatchString(action) {
+		return errorUtils.NewUnprocessableEntityError(fmt.Sprintf("action name '%s' should be lowercase letters, digits and _, or *", action))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	//RbacSourceFile reads the policy from RBAC_FILEPATH, the default
+	RbacSourceFile = "file"
+	//RbacSourceDatabase reads the policy from the rbac tables, managed through the /rbac routes
+	RbacSourceDatabase = "database"
+)
+
+//RbacPolicySource is where the authorization service reads the policy from.
+//It is only used by one reload at a time.
+type RbacPolicySource interface {
+	//Read returns the policy as YAML
+	Read() ([]byte, error)
+	//Changed tells, without reading it, whether the policy changed since it was last read
+	Changed() bool
+	String() string
+}
+
+type rbacFileSource struct {
+	path string
+	//modification time and size of the file when last read, to notice changes without reading it
+	modTime time.Time
+	size    int64
+}
+
+//Constructor
+func NewRbacFileSource(path string) RbacPolicySource {
+	return &rbacFileSource{path: path}
+}
+
+func (f *rbacFileSource) Read() ([]byte, error) {
+	f.modTime, f.size = time.Time{}, 0
+	if stat, err := os.Stat(f.path); err == nil {
+		f.modTime, f.size = stat.ModTime(), stat.Size()
+	}
+	return ioutil.ReadFile(f.path)
+}
+
+func (f *rbacFileSource) Changed() bool {
+	stat, err := os.Stat(f.path)
+	if err != nil {
+		//a missing file is reported once, by the reload following its removal
+		return !f.modTime.IsZero()
+	}
+	return !stat.ModTime().Equal(f.modTime) || stat.Size() != f.size
+}
+
+func (f *rbacFileSource) String() string {
+	return f.path
+}
+
+type rbacDatabaseSource struct {
+	//version of the policy when last read
+	version uint64
+}
+
+//Constructor - reads the policy from domain.RbacPolicyRepo
+func NewRbacDatabaseSource() RbacPolicySource {
+	return &rbacDatabaseSource{}
+}
+
+//Read builds the policy from the tables. The version is read first, a change made in between is read again on the next check.
+func (d *rbacDatabaseSource) Read() ([]byte, error) {
+	latest, err := domain.RbacPolicyRepo.GetLatestVersion()
+	if err != nil {
+		if err.Status() == http.StatusNotFound {
+			return nil, errors.New("the database holds no rbac policy yet")
+		}
+		return nil, err
+	}
+	roles, err := domain.RbacPolicyRepo.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+	policy, policyErr := domain.RbacPolicyFromRoles(roles)
+	if policyErr != nil {
+		return nil, policyErr
+	}
+	content, marshalErr := policy.Marshal()
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	d.version = latest.Version
+	return content, nil
+}
+
+func (d *rbacDatabaseSource) Changed() bool {
+	latest, err := domain.RbacPolicyRepo.GetLatestVersion()
+	return err == nil && latest.Version != d.version
+}
+
+func (d *rbacDatabaseSource) String() string {
+	return RbacSourceDatabase
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/authUtils"
+	"GamesAPI/src/utils/errorUtils"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+const (
+	DefaultAccessTokenLifetime  = time.Minute * 10
+	DefaultRefreshTokenLifetime = time.Hour * 24 * 30
+)
+
+var (
+	RefreshTokenService RefreshTokenServiceInterface = NewRefreshTokenService(DefaultAccessTokenLifetime, DefaultRefreshTokenLifetime)
+)
+
+type RefreshTokenServiceInterface interface {
+	IssueTokens(userId uint64) (*domain.TokenPair, errorUtils.EntityError)
+	Refresh(refreshToken string) (*domain.TokenPair, errorUtils.EntityError)
+	RevokeFamily(familyId string) errorUtils.EntityError
+	Logout(userId uint64, accessToken string, refreshToken string) errorUtils.EntityError
+}
+
+//refreshTokenService hands out short-lived access tokens (sessions) along with long-lived refresh tokens.
+//A refresh token can only be used once: using it again means it leaked, so its whole family is revoked.
+type refreshTokenService struct {
+	accessLifetime  time.Duration
+	refreshLifetime time.Duration
+}
+
+//Constructor
+func NewRefreshTokenService(accessLifetime time.Duration, refreshLifetime time.Duration) RefreshTokenServiceInterface {
+	return &refreshTokenService{
+		accessLifetime:  accessLifetime,
+		refreshLifetime: refreshLifetime,
+	}
+}
+
+//NewRefreshTokenServiceFromEnv reads the token lifetimes from ACCESS_TOKEN_LIFETIME and REFRESH_TOKEN_LIFETIME (durations)
+func NewRefreshTokenServiceFromEnv() RefreshTokenServiceInterface {
+	return NewRefreshTokenService(
+		lifetimeFromEnv("ACCESS_TOKEN_LIFETIME", DefaultAccessTokenLifetime),
+		lifetimeFromEnv("REFRESH_TOKEN_LIFETIME", DefaultRefreshTokenLifetime),
+	)
+}
+
+func lifetimeFromEnv(key string, fallback time.Duration) time.Duration {
+	lifetime, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || lifetime <= 0 {
+		return fallback
+	}
+	return lifetime
+}
+
+//IssueTokens starts a new token family, typically right after a login
+func (r *refreshTokenService) IssueTokens(userId uint64) (*domain.TokenPair, errorUtils.EntityError) {
+	familyId, err := authUtils.NewSessionToken()
+	if err != nil {
+		return nil, errorUtils.NewInternalServerError(fmt.Sprintf("Token couldn't be generated by server - %s", err.Error()))
+	}
+	return r.issue(userId, familyId)
+}
+
+func (r *refreshTokenService) Refresh(refreshToken string) (*domain.TokenPair, errorUtils.EntityError) {
+	key := authUtils.HashSessionToken(refreshToken)
+	token, err := domain.RefreshTokenRepo.Get(key)
+	if err != nil {
+		return nil, errorUtils.NewUnauthorizedError("invalid refresh token")
+	}
+
+	now := time.Now()
+	if token.ExpiresAt < now.UnixNano() {
+		return nil, errorUtils.NewUnauthorizedError("refresh token is expired")
+	}
+
+	firstUse, err := domain.RefreshTokenRepo.MarkUsed(key, now)
+	if err != nil {
+		return nil, err
+	}
+	if !firstUse {
+		//someone is replaying an old token, we can't tell who's legit so nobody keeps the family
+		if revokeErr := r.RevokeFamily(token.FamilyId); revokeErr != nil {
+			log.Printf("could not revoke token family of user %d: %s", token.UserId, revokeErr.Message())
+		}
+		return nil, errorUtils.NewUnauthorizedError("refresh token was already used, every session of this login was revoked")
+	}
+
+	return r.issue(token.UserId, token.FamilyId)
+}
+
+//RevokeFamily deletes every access and refresh token issued from the same login
+func (r *refreshTokenService) RevokeFamily(familyId string) errorUtils.EntityError {
+	if familyId == "" {
+		return nil
+	}
+	if _, err := domain.RefreshTokenRepo.DeleteByFamily(familyId); err != nil {
+		return err
+	}
+	if _, err := domain.UserSessionRepo.DeleteByFamily(familyId); err != nil {
+		return err
+	}
+	return nil
+}
+
+//Logout ends the login the access token belongs to. A JWT cannot be revoked, so with JWT authentication
+//the refresh token is what ends the login: it is revoked along with its family when given.
+func (r *refreshTokenService) Logout(userId uint64, accessToken string, refreshToken string) errorUtils.EntityError {
+	if !JwtService.Enabled() || !authUtils.LooksLikeJwt(accessToken) {
+		if err := UserSessionService.RevokeSession(userId, authUtils.HashSessionToken(accessToken)); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+	token, err := domain.RefreshTokenRepo.Get(authUtils.HashSessionToken(refreshToken))
+	if err != nil || token.UserId != userId {
+		return errorUtils.NewUnauthorizedError("invalid refresh token")
+	}
+	return r.RevokeFamily(token.FamilyId)
+}
+
+func (r *refreshTokenService) issue(userId uint64, familyId string) (*domain.TokenPair, errorUtils.EntityError) {
+	now := time.Now()
+	accessExpiresAt := now.Add(r.accessLifetime)
+	accessToken, err := r.issueAccessToken(userId, familyId, accessExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	//every refresh slides the refresh token's expiry forward
+	refreshExpiresAt := now.Add(r.refreshLifetime)
+	refreshToken, tokenErr := authUtils.NewSessionToken()
+	if tokenErr != nil {
+		return nil, errorUtils.NewInternalServerError(fmt.Sprintf("Token couldn't be generated by server - %s", tokenErr.Error()))
+	}
+
+	_, err = domain.RefreshTokenRepo.Create(&domain.RefreshToken{
+		Token:     authUtils.HashSessionToken(refreshToken),
+		FamilyId:  familyId,
+		UserId:    userId,
+		CreatedAt: now,
+		ExpiresAt: refreshExpiresAt.UnixNano(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt.UnixNano(),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt.UnixNano(),
+	}, nil
+}
+
+//issueAccessToken signs a JWT carrying the user's roles when JWT authentication is enabled,
+//otherwise it creates an opaque session token
+func (r *refreshTokenService) issueAccessToken(userId uint64, familyId string, expiresAt time.Time) (string, errorUtils.EntityError) {
+	if JwtService.Enabled() {
+		roles, err := UserRoleService.GetRolesByUserID(userId)
+		if err != nil {
+			return "", err
+		}
+		roleNames := make([]string, 0, len(roles))
+		for _, role := range roles {
+			roleNames = append(roleNames, role.Name)
+		}
+		return JwtService.IssueAccessToken(userId, roleNames, expiresAt)
+	}
+
+	accessToken, tokenErr := UserSessionService.GenerateSessionToken()
+	if tokenErr != nil {
+		return "", errorUtils.NewInternalServerError(fmt.Sprintf("Token couldn't be generated by server - %s", tokenErr.Error()))
+	}
+
+	_, err := UserSessionService.CreateSession(&domain.UserSession{
+		Token:     accessToken,
+		UserId:    userId,
+		FamilyId:  familyId,
+		ExpiresAt: expiresAt.UnixNano(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return accessToken, nil
+}
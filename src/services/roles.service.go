@@ -3,10 +3,17 @@ package services
 import (
 	"GamesAPI/src/domain"
 	"GamesAPI/src/utils/errorUtils"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 )
 
 var (
 	UserRoleService UserRoleServiceInterface = &userRoleService{}
+
+	//serializes the changes checked against the other admins, so two admins cannot remove each other at once
+	roleAssignmentMutex sync.Mutex
 )
 
 type userRoleService struct{}
@@ -19,6 +26,14 @@ type UserRoleServiceInterface interface {
 	UpdateRole(role *domain.UserRole) (*domain.UserRole, errorUtils.EntityError)
 	DeleteRole(roleId uint64) errorUtils.EntityError
 	GetAllRoles() ([]domain.UserRole, errorUtils.EntityError)
+	//PolicyRole returns name as it is found among the roles of the RBAC policy being enforced, 422 when it is not there
+	PolicyRole(name string) (string, errorUtils.EntityError)
+	//AssignRole gives the user a role of the RBAC policy being enforced
+	AssignRole(userId uint64, name string) (*domain.UserRole, errorUtils.EntityError)
+	//ChangeRole replaces a role of a user with another role of the policy, the last admin cannot lose the admin role
+	ChangeRole(userRoleId uint64, name string) (*domain.UserRole, errorUtils.EntityError)
+	//RevokeRole takes a role away from a user, the last admin cannot lose the admin role
+	RevokeRole(userRoleId uint64) errorUtils.EntityError
 }
 
 func (u userRoleService) GetRole(userRoleId uint64) (*domain.UserRole, errorUtils.EntityError) {
@@ -87,3 +102,109 @@ func (u userRoleService) GetAllRoles() ([]domain.UserRole, errorUtils.EntityErro
 	}
 	return roles, nil
 }
+
+func (u userRoleService) PolicyRole(name string) (string, errorUtils.EntityError) {
+	return policyRoleName(name)
+}
+
+func (u userRoleService) AssignRole(userId uint64, name string) (*domain.UserRole, errorUtils.EntityError) {
+	name, err := policyRoleName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := UsersService.GetUser(userId); err != nil {
+		return nil, err
+	}
+
+	roleAssignmentMutex.Lock()
+	defer roleAssignmentMutex.Unlock()
+	current, err := domain.UserRoleRepo.GetByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range current {
+		if strings.EqualFold(role.Name, name) {
+			return nil, errorUtils.NewConflictError(fmt.Sprintf("user %d already has the role %s", userId, name))
+		}
+	}
+	return domain.UserRoleRepo.Create(&domain.UserRole{UserID: userId, Name: name})
+}
+
+func (u userRoleService) ChangeRole(userRoleId uint64, name string) (*domain.UserRole, errorUtils.EntityError) {
+	name, err := policyRoleName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	roleAssignmentMutex.Lock()
+	defer roleAssignmentMutex.Unlock()
+	current, err := domain.UserRoleRepo.GetByID(userRoleId)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(current.Name, name) {
+		return current, nil
+	}
+	held, err := domain.UserRoleRepo.GetByUserID(current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range held {
+		if strings.EqualFold(role.Name, name) {
+			return nil, errorUtils.NewConflictError(fmt.Sprintf("user %d already has the role %s", current.UserID, name))
+		}
+	}
+	if err := checkNotLastAdmin(current); err != nil {
+		return nil, err
+	}
+	current.Name = name
+	return domain.UserRoleRepo.Update(current)
+}
+
+func (u userRoleService) RevokeRole(userRoleId uint64) errorUtils.EntityError {
+	roleAssignmentMutex.Lock()
+	defer roleAssignmentMutex.Unlock()
+	current, err := domain.UserRoleRepo.GetByID(userRoleId)
+	if err != nil {
+		return err
+	}
+	if err := checkNotLastAdmin(current); err != nil {
+		return err
+	}
+	return domain.UserRoleRepo.Delete(current.ID)
+}
+
+//policyRoleName finds name among the roles of the policy being enforced. Users' roles are lowercased when checked, so is name.
+func policyRoleName(name string) (string, errorUtils.EntityError) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", errorUtils.NewUnprocessableEntityError("role name cannot be empty")
+	}
+	rbac := AuthorizationService.GetRbac()
+	if _, exists := rbac[name]; exists {
+		return name, nil
+	}
+	known := make([]string, 0, len(rbac))
+	for roleName := range rbac {
+		known = append(known, roleName)
+	}
+	sort.Strings(known)
+	return "", errorUtils.NewUnprocessableEntityError(fmt.Sprintf("role %s is not in the rbac policy, expected one of %s", name, strings.Join(known, ", ")))
+}
+
+//checkNotLastAdmin refuses to take the admin role away from the only user holding it
+func checkNotLastAdmin(role *domain.UserRole) errorUtils.EntityError {
+	if !strings.EqualFold(role.Name, domain.AdminRoleName) {
+		return nil
+	}
+	admins, err := domain.UserRoleRepo.GetByRole(domain.AdminRoleName)
+	if err != nil {
+		return err
+	}
+	for _, admin := range admins {
+		if admin.UserID != role.UserID {
+			return nil
+		}
+	}
+	return errorUtils.NewConflictError(fmt.Sprintf("user %d is the last %s, give the role to someone else first", role.UserID, domain.AdminRoleName))
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"GamesAPI/src/External/Steam"
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultSyncQueueSize = 100
+
+var (
+	SyncJobsService SyncJobsServiceInterface = NewSyncJobsService(defaultSyncQueueSize, "")
+)
+
+type SyncJobsServiceInterface interface {
+	Start(workers int)
+	Stop()
+	EnqueueSync(user *domain.User) (*domain.SyncJob, errorUtils.EntityError)
+	GetJob(jobId uint64) (*domain.SyncJob, errorUtils.EntityError)
+	CancelJob(jobId uint64) (*domain.SyncJob, errorUtils.EntityError)
+}
+
+//syncJobsService runs Steam synchronizations on a pool of background workers.
+//Once a job is enqueued, only the worker executing it writes to its row.
+type syncJobsService struct {
+	queue   chan uint64
+	mutex   sync.Mutex
+	pending map[uint64]*pendingSync
+	workers sync.WaitGroup
+	//the jobs run with contexts derived from ctx, so stopping the service interrupts their Steam calls
+	ctx  context.Context
+	stop context.CancelFunc
+	//instance tells the jobs of this server apart from those of other replicas sharing the database
+	instance string
+}
+
+//pendingSync holds what a worker needs to run a job that is queued or running on this server
+type pendingSync struct {
+	steamUserId string
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+//Constructor - the queue size is the number of jobs that can wait for a free worker.
+//Instance names this server among the replicas sharing the database, see NewSyncJobsServiceFromEnv.
+func NewSyncJobsService(queueSize int, instance string) SyncJobsServiceInterface {
+	ctx, stop := context.WithCancel(context.Background())
+	return &syncJobsService{
+		queue:    make(chan uint64, queueSize),
+		pending:  map[uint64]*pendingSync{},
+		ctx:      ctx,
+		stop:     stop,
+		instance: instance,
+	}
+}
+
+//Constructor - names this server with SYNC_INSTANCE. It has to stay the same across restarts and differ between the replicas,
+//for a restarted server to fail the jobs it left unfinished and only those. A hostname changes with every container,
+//so there is no default.
+func NewSyncJobsServiceFromEnv() (SyncJobsServiceInterface, error) {
+	instance := strings.TrimSpace(os.Getenv("SYNC_INSTANCE"))
+	if instance == "" {
+		return nil, errors.New("SYNC_INSTANCE should name this server among the replicas, and stay the same across its restarts")
+	}
+	return NewSyncJobsService(defaultSyncQueueSize, instance), nil
+}
+
+func (s *syncJobsService) Start(workers int) {
+	//jobs left unfinished by a previous run of this server will never be picked up again,
+	//those of the other replicas are still running there
+	interrupted, err := domain.SyncJobRepo.GetByInstance(s.instance, domain.SyncJobQueued, domain.SyncJobRunning)
+	if err != nil {
+		log.Printf("could not look for interrupted sync jobs: %s", err.Message())
+	}
+	for i := range interrupted {
+		s.finish(&interrupted[i], domain.SyncJobFailed, "interrupted by a server restart")
+	}
+
+	for i := 0; i < workers; i++ {
+		s.workers.Add(1)
+		go s.work(s.ctx)
+	}
+}
+
+//Stop interrupts every running job and waits for the workers to return
+func (s *syncJobsService) Stop() {
+	s.stop()
+	s.workers.Wait()
+}
+
+func (s *syncJobsService) EnqueueSync(user *domain.User) (*domain.SyncJob, errorUtils.EntityError) {
+	job := &domain.SyncJob{
+		UserID:   user.ID,
+		Status:   domain.SyncJobQueued,
+		Instance: s.instance,
+	}
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+
+	job, err := domain.SyncJobRepo.Create(job)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.mutex.Lock()
+	s.pending[job.ID] = &pendingSync{steamUserId: user.SteamUserId, ctx: ctx, cancel: cancel}
+	s.mutex.Unlock()
+
+	select {
+	case s.queue <- job.ID:
+		return job, nil
+	default:
+		s.forget(job.ID)
+		s.finish(job, domain.SyncJobFailed, "sync queue is full")
+		return nil, errorUtils.NewServiceUnavailableError("too many synchronizations are pending, try again later")
+	}
+}
+
+func (s *syncJobsService) GetJob(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+	job, err := domain.SyncJobRepo.Get(jobId)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+//CancelJob asks the worker in charge of the job to stop. The job is marked as cancelled by the worker itself.
+func (s *syncJobsService) CancelJob(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+	job, err := domain.SyncJobRepo.Get(jobId)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsFinished() {
+		return nil, errorUtils.NewConflictError(fmt.Sprintf("sync job %d is already %s", jobId, job.Status))
+	}
+
+	s.mutex.Lock()
+	pending, exists := s.pending[jobId]
+	s.mutex.Unlock()
+	if !exists {
+		return nil, errorUtils.NewConflictError(fmt.Sprintf("sync job %d is not handled by this server", jobId))
+	}
+	pending.cancel()
+	return job, nil
+}
+
+func (s *syncJobsService) work(ctx context.Context) {
+	defer s.workers.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case jobId := <-s.queue:
+			s.run(ctx, jobId)
+		}
+	}
+}
+
+func (s *syncJobsService) forget(jobId uint64) {
+	s.mutex.Lock()
+	if pending, exists := s.pending[jobId]; exists {
+		pending.cancel()
+		delete(s.pending, jobId)
+	}
+	s.mutex.Unlock()
+}
+
+func (s *syncJobsService) run(workerCtx context.Context, jobId uint64) {
+	s.mutex.Lock()
+	pending, exists := s.pending[jobId]
+	s.mutex.Unlock()
+	if !exists {
+		return
+	}
+	defer s.forget(jobId)
+
+	job, err := domain.SyncJobRepo.Get(jobId)
+	if err != nil {
+		log.Printf("sync job %d could not be loaded: %s", jobId, err.Message())
+		return
+	}
+	//errors are stored as they happen, we don't need to carry the ones already saved
+	job.Errors = nil
+
+	//the job could have been cancelled while waiting in the queue
+	if s.interrupted(workerCtx, pending, job) {
+		return
+	}
+
+	now := time.Now()
+	job.Status = domain.SyncJobRunning
+	job.StartedAt = &now
+	s.save(job)
+
+	ownedGames, steamErr := Steam.ExternalSteamUserService.GetUserOwnedGames(pending.ctx, pending.steamUserId)
+	if s.interrupted(workerCtx, pending, job) {
+		return
+	}
+	if steamErr != nil {
+		s.finish(job, domain.SyncJobFailed, steamErr.Error())
+		return
+	}
+	job.Total = len(ownedGames)
+	s.save(job)
+
+	for _, ownedGame := range ownedGames {
+		if s.interrupted(workerCtx, pending, job) {
+			return
+		}
+
+		inserted, appErr := syncOwnedGame(pending.ctx, job.UserID, ownedGame)
+		//the game was interrupted halfway, it will be picked up by the next sync
+		if s.interrupted(workerCtx, pending, job) {
+			return
+		}
+		if appErr != nil {
+			job.Errored += 1
+			if err := domain.SyncJobRepo.AddError(&domain.SyncJobError{
+				JobID:   job.ID,
+				AppId:   ownedGame.AppId,
+				Message: appErr.Error(),
+			}); err != nil {
+				log.Printf("sync job %d could not record error for app %s: %s", job.ID, ownedGame.AppId, err.Message())
+			}
+		} else if inserted {
+			job.Inserted += 1
+		} else {
+			job.Skipped += 1
+		}
+		s.save(job)
+	}
+
+	s.finish(job, domain.SyncJobSucceeded, "")
+}
+
+//interrupted finishes the job if the server is shutting down or the job was cancelled.
+//The job's context is derived from the workers' one, so the shutdown is checked first.
+func (s *syncJobsService) interrupted(workerCtx context.Context, pending *pendingSync, job *domain.SyncJob) bool {
+	if workerCtx.Err() != nil {
+		s.finish(job, domain.SyncJobFailed, "interrupted by a server shutdown")
+		return true
+	}
+	if pending.ctx.Err() != nil {
+		s.finish(job, domain.SyncJobCancelled, "")
+		return true
+	}
+	return false
+}
+
+func (s *syncJobsService) save(job *domain.SyncJob) {
+	if _, err := domain.SyncJobRepo.Update(job); err != nil {
+		log.Printf("sync job %d could not be saved: %s", job.ID, err.Message())
+	}
+}
+
+func (s *syncJobsService) finish(job *domain.SyncJob, status string, reason string) {
+	now := time.Now()
+	job.Status = status
+	job.Error = reason
+	job.FinishedAt = &now
+	s.save(job)
+}
+
+//syncOwnedGame adds the game to the catalog if needed, then to the user's library.
+//Returns whether the game had to be inserted in the catalog.
+func syncOwnedGame(ctx context.Context, userId uint64, ownedGame Steam.OwnedGame) (bool, error) {
+	inserted := false
+	game, err := GamesService.GetGameBySteamID(ownedGame.AppId)
+	if err != nil && err.Status() != http.StatusNotFound {
+		return false, err
+	}
+	if game == nil {
+		g, steamErr := Steam.ExternalSteamUserService.GetGameInfo(ctx, ownedGame.AppId)
+		if steamErr != nil {
+			return false, steamErr
+		}
+		created, createErr := GamesService.CreateGame(&g)
+		if createErr != nil {
+			return false, createErr
+		}
+		game = created
+		inserted = true
+	}
+
+	_, syncErr := UserGamesService.SyncLibraryEntry(&domain.UserGame{
+		UserID:                 userId,
+		GameID:                 game.ID,
+		Source:                 domain.UserGameSourceSteam,
+		PlaytimeForever:        ownedGame.PlaytimeForever,
+		PlaytimeWindowsForever: ownedGame.PlaytimeWindowsForever,
+		PlaytimeMacForever:     ownedGame.PlaytimeMacForever,
+		PlaytimeLinuxForever:   ownedGame.PlaytimeLinuxForever,
+	})
+	if syncErr != nil {
+		return inserted, syncErr
+	}
+	return inserted, nil
+}
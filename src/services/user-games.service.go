@@ -0,0 +1,79 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"net/http"
+	"time"
+)
+
+var (
+	UserGamesService UserGamesServiceInterface = &userGamesService{}
+)
+
+type userGamesService struct{}
+
+type UserGamesServiceInterface interface {
+	GetLibrary(userId uint64) ([]domain.UserGame, errorUtils.EntityError)
+	GetLibraryEntry(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError)
+	SyncLibraryEntry(entry *domain.UserGame) (*domain.UserGame, errorUtils.EntityError)
+	DeleteLibraryEntry(userId uint64, gameId uint64, source string) errorUtils.EntityError
+}
+
+func (u *userGamesService) GetLibrary(userId uint64) ([]domain.UserGame, errorUtils.EntityError) {
+	library, err := domain.UserGameRepo.GetByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+	return library, nil
+}
+
+func (u *userGamesService) GetLibraryEntry(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+	entry, err := domain.UserGameRepo.GetByUserAndGame(userId, gameId, source)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+//SyncLibraryEntry creates the library entry if the user doesn't own the game on this source yet,
+//otherwise it refreshes the playtime of the existing entry
+func (u *userGamesService) SyncLibraryEntry(entry *domain.UserGame) (*domain.UserGame, errorUtils.EntityError) {
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	current, err := domain.UserGameRepo.GetByUserAndGame(entry.UserID, entry.GameID, entry.Source)
+	if err != nil {
+		if err.Status() != http.StatusNotFound {
+			return nil, err
+		}
+		entry.LastSyncedAt = &now
+		return domain.UserGameRepo.Create(entry)
+	}
+
+	current.PlaytimeForever = entry.PlaytimeForever
+	current.PlaytimeWindowsForever = entry.PlaytimeWindowsForever
+	current.PlaytimeMacForever = entry.PlaytimeMacForever
+	current.PlaytimeLinuxForever = entry.PlaytimeLinuxForever
+	current.LastSyncedAt = &now
+
+	updated, err := domain.UserGameRepo.Update(current)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (u *userGamesService) DeleteLibraryEntry(userId uint64, gameId uint64, source string) errorUtils.EntityError {
+	current, err := domain.UserGameRepo.GetByUserAndGame(userId, gameId, source)
+	if err != nil {
+		return err
+	}
+	deleteErr := domain.UserGameRepo.Delete(current.ID)
+	if deleteErr != nil {
+		return deleteErr
+	}
+	return nil
+}
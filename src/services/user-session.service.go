@@ -2,44 +2,41 @@ package services
 
 import (
 	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/authUtils"
 	"GamesAPI/src/utils/errorUtils"
-	"encoding/binary"
 	"fmt"
-	"hash/fnv"
-	"strconv"
+	"log"
 	"time"
 )
 
+const DefaultSessionReapInterval = time.Minute
+
 var (
 	UserSessionService UserSessionServiceInterface = &userSessionService{}
 )
 
+//Sessions are looked up with the token sent by the client, but only its hash is ever stored.
+//The hash is also the session id used to list and revoke sessions.
 type UserSessionServiceInterface interface {
 	CreateSession(token *domain.UserSession) (*domain.UserSession, errorUtils.EntityError)
 	ExistsSession(token string) bool
 	DeleteSession(token string) errorUtils.EntityError
 	IsSessionExpired(key string, currentTime time.Time) (bool, errorUtils.EntityError)
-	GenerateSessionToken(userId uint64, expireAt time.Time) (string, error)
+	GenerateSessionToken() (string, error)
 	GetSession(key string) (*domain.UserSession, errorUtils.EntityError)
+	GetUserSessions(userId uint64) ([]domain.UserSession, errorUtils.EntityError)
+	RevokeSession(userId uint64, sessionId string) errorUtils.EntityError
+	RevokeUserSessions(userId uint64) (int64, errorUtils.EntityError)
 }
 
 type userSessionService struct{}
 
 func (u *userSessionService) GetSession(key string) (*domain.UserSession, errorUtils.EntityError) {
-	return domain.UserSessionRepo.Get(key)
+	return domain.UserSessionRepo.Get(authUtils.HashSessionToken(key))
 }
 
-func (u *userSessionService) GenerateSessionToken(userId uint64, expireAt time.Time) (string, error) {
-	h := fnv.New32a()
-	// add both values as bytes to a buffer big enough to contain them
-	buf := make([]byte, binary.MaxVarintLen64+binary.MaxVarintLen64)
-	n := binary.PutUvarint(buf, userId)
-	m := binary.PutVarint(buf, expireAt.UnixNano())
-	//take the resulting slice
-	b := buf[:(n + m)]
-	//hash it
-	_, err := h.Write(b)
-	return strconv.Itoa(int(h.Sum32())), err
+func (u *userSessionService) GenerateSessionToken() (string, error) {
+	return authUtils.NewSessionToken()
 }
 
 func (u *userSessionService) CreateSession(token *domain.UserSession) (*domain.UserSession, errorUtils.EntityError) {
@@ -47,11 +44,18 @@ func (u *userSessionService) CreateSession(token *domain.UserSession) (*domain.U
 		return nil, err
 	}
 
-	if domain.UserSessionRepo.Exists(token.Token) {
-		return nil, errorUtils.NewUnprocessableEntityError(fmt.Sprintf("token with key %s already exists", token.Token))
+	//store a copy keyed by the hash, the caller keeps the plain token
+	session := *token
+	session.Token = authUtils.HashSessionToken(token.Token)
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+
+	if domain.UserSessionRepo.Exists(session.Token) {
+		return nil, errorUtils.NewUnprocessableEntityError("a session already exists for this token")
 	}
 
-	ret, err := domain.UserSessionRepo.Create(token.Token, token)
+	ret, err := domain.UserSessionRepo.Create(session.Token, &session)
 	if err != nil {
 		return nil, err
 	}
@@ -60,11 +64,12 @@ func (u *userSessionService) CreateSession(token *domain.UserSession) (*domain.U
 }
 
 func (u *userSessionService) IsSessionExpired(key string, currentTime time.Time) (bool, errorUtils.EntityError) {
-	if !domain.UserSessionRepo.Exists(key) {
-		return true, errorUtils.NewNotFoundError(fmt.Sprintf("token with key %s does not exist", key))
+	hashed := authUtils.HashSessionToken(key)
+	if !domain.UserSessionRepo.Exists(hashed) {
+		return true, errorUtils.NewNotFoundError("session does not exist for given token")
 	}
 
-	sesh, err := domain.UserSessionRepo.Get(key)
+	sesh, err := domain.UserSessionRepo.Get(hashed)
 
 	if err != nil {
 		return true, err
@@ -74,12 +79,94 @@ func (u *userSessionService) IsSessionExpired(key string, currentTime time.Time)
 }
 
 func (u *userSessionService) ExistsSession(key string) bool {
-	return domain.UserSessionRepo.Exists(key)
+	return domain.UserSessionRepo.Exists(authUtils.HashSessionToken(key))
 }
 
 func (u *userSessionService) DeleteSession(key string) errorUtils.EntityError {
-	if !domain.UserSessionRepo.Exists(key) {
-		return errorUtils.NewNotFoundError(fmt.Sprintf("token with key %s does not exist", key))
+	hashed := authUtils.HashSessionToken(key)
+	if !domain.UserSessionRepo.Exists(hashed) {
+		return errorUtils.NewNotFoundError("session does not exist for given token")
+	}
+	return domain.UserSessionRepo.Delete(hashed)
+}
+
+//GetUserSessions lists the sessions of a user that are still valid
+func (u *userSessionService) GetUserSessions(userId uint64) ([]domain.UserSession, errorUtils.EntityError) {
+	sessions, err := domain.UserSessionRepo.GetByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixNano()
+	active := []domain.UserSession{}
+	for _, session := range sessions {
+		if session.ExpiresAt >= now {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+//RevokeSession deletes one of the user's sessions by id. Sessions of other users are reported as not found.
+func (u *userSessionService) RevokeSession(userId uint64, sessionId string) errorUtils.EntityError {
+	session, err := domain.UserSessionRepo.Get(sessionId)
+	if err != nil || session.UserId != userId {
+		return errorUtils.NewNotFoundError(fmt.Sprintf("session %s does not exist", sessionId))
+	}
+	//the refresh tokens of the session must go too, or they could bring it back
+	if session.FamilyId != "" {
+		if _, err := domain.RefreshTokenRepo.DeleteByFamily(session.FamilyId); err != nil {
+			return err
+		}
+	}
+	return domain.UserSessionRepo.Delete(sessionId)
+}
+
+//RevokeUserSessions logs the user out everywhere
+func (u *userSessionService) RevokeUserSessions(userId uint64) (int64, errorUtils.EntityError) {
+	if _, err := domain.RefreshTokenRepo.DeleteByUserID(userId); err != nil {
+		return 0, err
+	}
+	return domain.UserSessionRepo.DeleteByUserID(userId)
+}
+
+//StartSessionReaper frees expired sessions, refresh tokens and login attempts from the store every interval, until the returned function is called
+func StartSessionReaper(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				reapSessions(now)
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+//reapSessions runs every cleanup on its own, a store failing to free its rows does not keep the others from doing so
+func reapSessions(now time.Time) {
+	if deleted, err := domain.UserSessionRepo.DeleteExpired(now); err != nil {
+		log.Printf("could not delete expired sessions: %s", err.Message())
+	} else if deleted > 0 {
+		log.Printf("deleted %d expired sessions", deleted)
+	}
+
+	if deleted, err := domain.RefreshTokenRepo.DeleteExpired(now); err != nil {
+		log.Printf("could not delete expired refresh tokens: %s", err.Message())
+	} else if deleted > 0 {
+		log.Printf("deleted %d expired refresh tokens", deleted)
+	}
+
+	if deleted := LoginAttemptService.DeleteExpired(now); deleted > 0 {
+		log.Printf("deleted %d expired login attempts", deleted)
+	}
+	if deleted := RateLimitService.DeleteExpired(now); deleted > 0 {
+		log.Printf("deleted %d full rate limit buckets", deleted)
 	}
-	return domain.UserSessionRepo.Delete(key)
 }
@@ -13,8 +13,9 @@ type usersService struct{}
 
 type UsersServiceInterface interface {
 	GetUser(uint64) (*domain.User, errorUtils.EntityError)
+	GetUserByEmail(email string) (*domain.User, errorUtils.EntityError)
 	CreateUser(*domain.User) (*domain.User, errorUtils.EntityError)
-	UpdateUser(*domain.User) (*domain.User, errorUtils.EntityError)
+	UpdateUser(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError)
 	DeleteUser(uint64) errorUtils.EntityError
 	GetAllUsers() ([]domain.User, errorUtils.EntityError)
 }
@@ -27,6 +28,14 @@ func (u usersService) GetUser(userId uint64) (*domain.User, errorUtils.EntityErr
 	return user, nil
 }
 
+func (u usersService) GetUserByEmail(email string) (*domain.User, errorUtils.EntityError) {
+	user, err := domain.UserRepo.GetByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 func (u usersService) CreateUser(user *domain.User) (*domain.User, errorUtils.EntityError) {
 	if err := user.Validate(); err != nil {
 		return nil, err
@@ -39,18 +48,18 @@ func (u usersService) CreateUser(user *domain.User) (*domain.User, errorUtils.En
 	return user, nil
 }
 
-func (u usersService) UpdateUser(user *domain.User) (*domain.User, errorUtils.EntityError) {
-	if err := user.Validate(); err != nil {
-		return nil, err
-	}
-
+//UpdateUser sets the given fields of the user, by their JSON names, the others keep their current value
+func (u usersService) UpdateUser(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 	current, err := domain.UserRepo.Get(user.ID)
 	if err != nil {
 		return nil, err
 	}
-	current.Email = user.Email
-	current.Name = user.Name
-	current.SteamUserId = user.SteamUserId
+	if err := current.Patch(user, fields); err != nil {
+		return nil, err
+	}
+	if err := current.Validate(); err != nil {
+		return nil, err
+	}
 
 	updatedUser, err := domain.UserRepo.Update(current)
 	if err != nil {
@@ -59,16 +68,36 @@ func (u usersService) UpdateUser(user *domain.User) (*domain.User, errorUtils.En
 	return updatedUser, nil
 }
 
+//DeleteUser deletes the user along with its roles, the last admin cannot be deleted
 func (u usersService) DeleteUser(userId uint64) errorUtils.EntityError {
 	user, err := domain.UserRepo.Get(userId)
 	if err != nil {
 		return err
 	}
 
+	//checked against the other admins like a role taken away
+	roleAssignmentMutex.Lock()
+	defer roleAssignmentMutex.Unlock()
+	roles, err := domain.UserRoleRepo.GetByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+	for i := range roles {
+		if err := checkNotLastAdmin(&roles[i]); err != nil {
+			return err
+		}
+	}
+
 	deleteErr := domain.UserRepo.Delete(user.ID)
 	if deleteErr != nil {
 		return deleteErr
 	}
+	//the roles of a deleted user would still count, an admin among others
+	for _, role := range roles {
+		if err := domain.UserRoleRepo.Delete(role.ID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,205 @@
+package authUtils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+)
+
+const (
+	JwtAlgorithmHS256 = "HS256"
+	JwtAlgorithmRS256 = "RS256"
+	JwtAlgorithmEdDSA = "EdDSA"
+
+	//kid given to the secret read from JWT_SECRET
+	defaultJwtKid = "default"
+)
+
+//SigningMethodEdDSA signs tokens with Ed25519 keys, which jwt-go does not support out of the box
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+type signingMethodEdDSA struct{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *signingMethodEdDSA) Alg() string {
+	return JwtAlgorithmEdDSA
+}
+
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
+
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
+
+//JwtKeySetFromEnv loads the JWT keys described by the environment, or returns nil when JWT_ALGORITHM is not set.
+//	JWT_ALGORITHM	HS256, RS256 or EdDSA
+//	JWT_SECRET		HS256 only, a single secret (kid "default")
+//	JWT_KEYS		comma-separated kid=path entries. HS256 files hold a secret, the others a PEM key.
+//					A public key can only verify tokens, which is how old keys are kept around after a rotation.
+//	JWT_ACTIVE_KID	kid of the key signing new tokens, defaults to the first key
+func JwtKeySetFromEnv() (*JwtKeySet, error) {
+	algorithm := os.Getenv("JWT_ALGORITHM")
+	if algorithm == "" {
+		return nil, nil
+	}
+
+	var keys []*JwtKey
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		key, err := NewJwtKey(algorithm, defaultJwtKid, []byte(secret))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	for _, entry := range strings.Split(os.Getenv("JWT_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("JWT_KEYS entry %s should look like kid=path", entry)
+		}
+		content, err := ioutil.ReadFile(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		key, err := NewJwtKey(algorithm, parts[0], content)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %s", parts[0], err.Error())
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWT_ALGORITHM is set but neither JWT_SECRET nor JWT_KEYS is")
+	}
+
+	activeKid := os.Getenv("JWT_ACTIVE_KID")
+	if activeKid == "" {
+		activeKid = keys[0].Kid
+	}
+	return NewJwtKeySet(activeKid, keys...)
+}
+
+//NewJwtKey builds a key for the algorithm. HS256 takes the secret itself, RS256 and EdDSA take a PEM encoded
+//private key (PKCS1 or PKCS8) or public key (PKIX).
+func NewJwtKey(algorithm string, kid string, material []byte) (*JwtKey, error) {
+	switch algorithm {
+	case JwtAlgorithmHS256:
+		secret := []byte(strings.TrimSpace(string(material)))
+		if len(secret) < 32 {
+			return nil, errors.New("HS256 secrets should be at least 32 bytes long")
+		}
+		return &JwtKey{Kid: kid, Method: jwt.SigningMethodHS256, SignKey: secret, VerifyKey: secret}, nil
+
+	case JwtAlgorithmRS256:
+		if privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(material); err == nil {
+			return &JwtKey{Kid: kid, Method: jwt.SigningMethodRS256, SignKey: privateKey, VerifyKey: &privateKey.PublicKey}, nil
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(material)
+		if err != nil {
+			return nil, err
+		}
+		return &JwtKey{Kid: kid, Method: jwt.SigningMethodRS256, VerifyKey: publicKey}, nil
+
+	case JwtAlgorithmEdDSA:
+		block, _ := pem.Decode(material)
+		if block == nil {
+			return nil, jwt.ErrKeyMustBePEMEncoded
+		}
+		if parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+			privateKey, ok := parsed.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errors.New("key is not an Ed25519 private key")
+			}
+			return &JwtKey{Kid: kid, Method: SigningMethodEdDSA, SignKey: privateKey, VerifyKey: privateKey.Public()}, nil
+		}
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("key is not an Ed25519 public key")
+		}
+		return &JwtKey{Kid: kid, Method: SigningMethodEdDSA, VerifyKey: publicKey}, nil
+	}
+	return nil, fmt.Errorf("unsupported jwt algorithm %s", algorithm)
+}
+
+//JWK is the public part of a key, as described by RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+//JWKS lists the public keys of the set. Shared secrets are never exposed, so an HS256 set has no keys to show.
+func (s *JwtKeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range s.Keys() {
+		switch publicKey := key.VerifyKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.Kid,
+				Alg: key.Method.Alg(),
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.Kid,
+				Alg: key.Method.Alg(),
+				Use: "sig",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+	return jwks
+}
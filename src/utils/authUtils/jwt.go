@@ -1,30 +1,134 @@
 package authUtils
 
-import "github.com/dgrijalva/jwt-go"
+import (
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"strings"
+)
 
-var superSecretString = []byte("golang.gang.gang")
+const JwtIssuer = "GamesAPI"
+
+var (
+	//JwtKeys signs and verifies the JWTs of the API. It is nil until JWT authentication is configured.
+	JwtKeys *JwtKeySet
+
+	ErrJwtNotConfigured = errors.New("jwt keys are not configured")
+)
 
 type UserClaims struct {
-	UserId uint64 `json:"user_id"`
+	UserId uint64   `json:"user_id"`
+	Roles  []string `json:"roles,omitempty"`
 	jwt.StandardClaims
 }
 
+//JwtKey is one of the keys of a key set. Keys loaded from a public key can only verify tokens,
+//which is how we keep accepting tokens signed by a key we rotated away from.
+type JwtKey struct {
+	Kid       string
+	Method    jwt.SigningMethod
+	SignKey   interface{}
+	VerifyKey interface{}
+}
+
+//JwtKeySet holds every key we accept, identified by their kid. New tokens are signed with the active key.
+type JwtKeySet struct {
+	activeKid string
+	keys      map[string]*JwtKey
+}
+
+//Constructor - the active key must be part of the set and be able to sign
+func NewJwtKeySet(activeKid string, keys ...*JwtKey) (*JwtKeySet, error) {
+	set := &JwtKeySet{activeKid: activeKid, keys: map[string]*JwtKey{}}
+	for _, key := range keys {
+		if _, exists := set.keys[key.Kid]; exists {
+			return nil, fmt.Errorf("jwt key %s is defined twice", key.Kid)
+		}
+		set.keys[key.Kid] = key
+	}
+	active, exists := set.keys[activeKid]
+	if !exists {
+		return nil, fmt.Errorf("active jwt key %s is not part of the key set", activeKid)
+	}
+	if active.SignKey == nil {
+		return nil, fmt.Errorf("active jwt key %s cannot sign tokens, a private key is needed", activeKid)
+	}
+	return set, nil
+}
+
+//Keys returns every key of the set, active one included
+func (s *JwtKeySet) Keys() []*JwtKey {
+	keys := make([]*JwtKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (s *JwtKeySet) Sign(claims *UserClaims) (string, error) {
+	key := s.keys[s.activeKid]
+	if claims.Issuer == "" {
+		claims.Issuer = JwtIssuer
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Kid
+	return token.SignedString(key.SignKey)
+}
+
+//Parse verifies the token with the key named by its kid. The algorithm must be the one of that key,
+//so a token cannot pick a weaker algorithm than the one we configured.
+func (s *JwtKeySet) Parse(tokenString string) (*UserClaims, error) {
+	claims := &UserClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" && len(s.keys) == 1 {
+			kid = s.activeKid
+		}
+		key, exists := s.keys[kid]
+		if !exists {
+			return nil, fmt.Errorf("unknown jwt key %s", kid)
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return key.VerifyKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if claims.Issuer != JwtIssuer {
+		return nil, fmt.Errorf("unexpected issuer %s", claims.Issuer)
+	}
+	return claims, nil
+}
+
+//LooksLikeJwt tells a JWT apart from an opaque session token
+func LooksLikeJwt(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
 //jwt utils inspired from https://tabvn.medium.com/authenticate-jwt-in-go-graphql-d71db976f71c
 
 func JwtDecode(token string) (*jwt.Token, error) {
-	return jwt.ParseWithClaims(token, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return superSecretString, nil
-	})
+	if JwtKeys == nil {
+		return nil, ErrJwtNotConfigured
+	}
+	claims, err := JwtKeys.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	return &jwt.Token{Claims: claims, Valid: true}, nil
 }
 
 func JwtCreate(userID uint64, expiredAt int64) (string, error) {
-	claims := UserClaims{
-		userID,
-		jwt.StandardClaims{
+	if JwtKeys == nil {
+		return "", ErrJwtNotConfigured
+	}
+	return JwtKeys.Sign(&UserClaims{
+		UserId: userID,
+		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expiredAt,
-			Issuer:    "GamesAPI",
+			Issuer:    JwtIssuer,
 		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(superSecretString)
+	})
 }
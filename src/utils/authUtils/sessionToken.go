@@ -0,0 +1,37 @@
+package authUtils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+//SessionTokenBytes is the amount of random bytes in a session token (256 bits)
+const SessionTokenBytes = 32
+
+//NewSessionToken returns a random, URL-safe token read from crypto/rand
+func NewSessionToken() (string, error) {
+	b := make([]byte, SessionTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+//HashSessionToken is what we store instead of the token itself, so a leaked session store cannot be replayed.
+//Tokens are random enough that a plain SHA-256 is sufficient, and it lets us look sessions up by hash.
+func HashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+//BearerToken extracts the token of an `Authorization: Bearer <token>` header, or returns "" if there is none
+func BearerToken(header string) string {
+	parts := strings.Split(strings.Trim(header, " "), "Bearer ")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
@@ -80,3 +80,35 @@ func NewInternalServerError(message string) EntityError {
 		ErrError:     "server_error",
 	}
 }
+
+func NewConflictError(message string) EntityError {
+	return &entityError{
+		ErrorMessage: message,
+		ErrorStatus:  http.StatusConflict,
+		ErrError:     "conflict",
+	}
+}
+
+func NewServiceUnavailableError(message string) EntityError {
+	return &entityError{
+		ErrorMessage: message,
+		ErrorStatus:  http.StatusServiceUnavailable,
+		ErrError:     "service_unavailable",
+	}
+}
+
+func NewTooManyRequestsError(message string) EntityError {
+	return &entityError{
+		ErrorMessage: message,
+		ErrorStatus:  http.StatusTooManyRequests,
+		ErrError:     "too_many_requests",
+	}
+}
+
+func NewUnauthorizedError(message string) EntityError {
+	return &entityError{
+		ErrorMessage: message,
+		ErrorStatus:  http.StatusUnauthorized,
+		ErrError:     "unauthorized",
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"GamesAPI/src/External/Steam"
 	"GamesAPI/src/domain"
 	"GamesAPI/tests/integration"
+	"context"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -27,7 +28,7 @@ func (s *SteamUserAPITestSuite) BeforeTest(_, _ string) {
 
 func (s *SteamUserAPITestSuite) TestGetSteamUserID_Success() {
 	steamUserURL := "gabelogannewell"
-	steamUserID, err := Steam.ExternalSteamUserService.GetUserID(steamUserURL)
+	steamUserID, err := Steam.ExternalSteamUserService.GetUserID(context.Background(), steamUserURL)
 	t := s.T()
 	assert.Nil(t, err)
 	assert.EqualValues(t, "76561197960287930", steamUserID)
@@ -36,7 +37,7 @@ func (s *SteamUserAPITestSuite) TestGetSteamUserID_Success() {
 //Warning, this test is valid until someone create this as a valid UserURL
 func (s *SteamUserAPITestSuite) TestGetSteamUserID_BadUserURL() {
 	steamUserURL := "gabelogannewell6584968746541654156"
-	steamUserID, err := Steam.ExternalSteamUserService.GetUserID(steamUserURL)
+	steamUserID, err := Steam.ExternalSteamUserService.GetUserID(context.Background(), steamUserURL)
 	t := s.T()
 	assert.EqualValues(t, "", steamUserID)
 	assert.NotNil(t, err)
@@ -44,16 +45,16 @@ func (s *SteamUserAPITestSuite) TestGetSteamUserID_BadUserURL() {
 
 func (s *SteamUserAPITestSuite) TestGetSteamUserOwnedGames_Success() {
 	steamUserID := "76561198017133337"
-	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(steamUserID)
+	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(context.Background(), steamUserID)
 	t := s.T()
 	assert.Nil(t, err)
-	assert.EqualValues(t, "2100", steamGamesIDs[0])
-	assert.EqualValues(t, "2130", steamGamesIDs[1])
+	assert.EqualValues(t, "2100", steamGamesIDs[0].AppId)
+	assert.EqualValues(t, "2130", steamGamesIDs[1].AppId)
 }
 
 func (s *SteamUserAPITestSuite) TestGetSteamUserOwnedGames_OwnesNoGames() {
 	steamUserID := "76561197960287930"
-	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(steamUserID)
+	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(context.Background(), steamUserID)
 	t := s.T()
 	assert.Nil(t, err)
 	assert.EqualValues(t, 0, len(steamGamesIDs))
@@ -61,7 +62,7 @@ func (s *SteamUserAPITestSuite) TestGetSteamUserOwnedGames_OwnesNoGames() {
 
 func (s *SteamUserAPITestSuite) TestGetSteamUserOwnedGames_BadUserID() {
 	steamUserID := "thishavenochanceofbeingarealsteamid1324567899876544321"
-	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(steamUserID)
+	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(context.Background(), steamUserID)
 	t := s.T()
 	assert.Nil(t, err)
 	assert.EqualValues(t, 0, len(steamGamesIDs))
@@ -69,7 +70,7 @@ func (s *SteamUserAPITestSuite) TestGetSteamUserOwnedGames_BadUserID() {
 
 func (s *SteamUserAPITestSuite) TestGetSteamGame_Success(){
 	gameID := "524220"
-	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(gameID)
+	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(context.Background(), gameID)
 	t := s.T()
 	assert.Nil(t, err)
 	assert.EqualValues(t, "NieR:Automata™", gameInfo.Title)
@@ -80,7 +81,7 @@ func (s *SteamUserAPITestSuite) TestGetSteamGame_Success(){
 
 func (s *SteamUserAPITestSuite) TestGetSteamGame_SuccessSecondGame(){
 	gameID := "218620"
-	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(gameID)
+	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(context.Background(), gameID)
 	t := s.T()
 	assert.Nil(t, err)
 	assert.EqualValues(t, "PAYDAY 2", gameInfo.Title)
@@ -91,7 +92,7 @@ func (s *SteamUserAPITestSuite) TestGetSteamGame_SuccessSecondGame(){
 
 func (s *SteamUserAPITestSuite) TestGetSteamGame_BadGameID(){
 	gameID := "65465156435"
-	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(gameID)
+	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(context.Background(), gameID)
 	t := s.T()
 	assert.NotNil(t, err)
 	assert.EqualValues(t, domain.Game{}, gameInfo)
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/router"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ApiKeysControllerTestSuite struct {
+	suite.Suite
+	mockService mocks.ApiKeyServiceMockInterface
+	r           *gin.Engine
+	rr          *httptest.ResponseRecorder
+}
+
+func TestApiKeysControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(ApiKeysControllerTestSuite))
+}
+
+func (s *ApiKeysControllerTestSuite) SetupSuite() {
+	mock := &mocks.ApiKeyServiceMock{}
+	s.mockService = mock
+	services.ApiKeyService = mock
+	s.r = gin.Default()
+	router.InitAllApiKeyRoutes(s.r.Group(""))
+}
+
+func (s *ApiKeysControllerTestSuite) BeforeTest(_, _ string) {
+	s.rr = httptest.NewRecorder()
+}
+
+func (s *ApiKeysControllerTestSuite) TestCreateApiKey_Success() {
+	s.mockService.SetIssueKey(func(key *domain.ApiKey) (string, *domain.ApiKey, errorUtils.EntityError) {
+		assert.EqualValues(s.T(), 3, key.OwnerId)
+		assert.EqualValues(s.T(), domain.ApiScopes{"games"}, key.Scopes)
+		key.ID = 1
+		key.Prefix = "0a1b2c3d4e5f"
+		key.SecretHash = "hash"
+		return "gapi_0a1b2c3d4e5f_secret", key, nil
+	})
+	body := `{"owner_id":3,"label":"mobile app","scopes":["games"]}`
+	req, _ := http.NewRequest(http.MethodPost, "/api-keys", strings.NewReader(body))
+	s.r.ServeHTTP(s.rr, req)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(s.rr.Body.Bytes(), &response)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusCreated, s.rr.Code)
+	assert.EqualValues(t, "gapi_0a1b2c3d4e5f_secret", response["key"])
+	assert.NotContains(t, s.rr.Body.String(), "hash")
+}
+
+func (s *ApiKeysControllerTestSuite) TestCreateApiKey_InvalidBody() {
+	req, _ := http.NewRequest(http.MethodPost, "/api-keys", strings.NewReader(`{"owner_id":"three"}`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusUnprocessableEntity, s.rr.Code)
+}
+
+func (s *ApiKeysControllerTestSuite) TestCreateApiKey_ExpiresInThePast() {
+	s.mockService.SetIssueKey(func(key *domain.ApiKey) (string, *domain.ApiKey, errorUtils.EntityError) {
+		assert.Fail(s.T(), "an expired key should not be issued")
+		return "", nil, nil
+	})
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	body := `{"owner_id":3,"label":"mobile app","expires_at":"` + past + `"}`
+	req, _ := http.NewRequest(http.MethodPost, "/api-keys", strings.NewReader(body))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusUnprocessableEntity, s.rr.Code)
+}
+
+func (s *ApiKeysControllerTestSuite) TestGetApiKeys_ByOwner() {
+	s.mockService.SetGetKeys(func(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError) {
+		assert.EqualValues(s.T(), 3, ownerId)
+		return []domain.ApiKey{{ID: 1, OwnerId: 3}}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/api-keys?owner_id=3", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	var keys []map[string]interface{}
+	err := json.Unmarshal(s.rr.Body.Bytes(), &keys)
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+	assert.Len(s.T(), keys, 1)
+}
+
+func (s *ApiKeysControllerTestSuite) TestRevokeApiKey_Success() {
+	s.mockService.SetRevokeKey(func(id uint64) errorUtils.EntityError {
+		assert.EqualValues(s.T(), 7, id)
+		return nil
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/api-keys/7", nil)
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+}
+
+func (s *ApiKeysControllerTestSuite) TestRevokeApiKey_BadId() {
+	req, _ := http.NewRequest(http.MethodDelete, "/api-keys/abc", nil)
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusBadRequest, s.rr.Code)
+}
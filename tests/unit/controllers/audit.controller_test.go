@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/router"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type AuditControllerTestSuite struct {
+	suite.Suite
+	mockService mocks.AuditServiceMockInterface
+	r           *gin.Engine
+	rr          *httptest.ResponseRecorder
+}
+
+func TestAuditControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditControllerTestSuite))
+}
+
+func (s *AuditControllerTestSuite) SetupSuite() {
+	mock := &mocks.AuditServiceMock{}
+	s.mockService = mock
+	services.AuditService = mock
+	s.r = gin.Default()
+	router.InitAllAuditRoutes(s.r.Group(""))
+}
+
+func (s *AuditControllerTestSuite) BeforeTest(_, _ string) {
+	s.rr = httptest.NewRecorder()
+}
+
+func (s *AuditControllerTestSuite) TestGetAudit() {
+	var received *domain.AuditQuery
+	s.mockService.SetGetEntries(func(query *domain.AuditQuery) (*domain.AuditPage, errorUtils.EntityError) {
+		received = query
+		return &domain.AuditPage{
+			Items:    []domain.AuditEntry{{ID: 7, ActorId: 3, Resource: "game", Action: "update", EntityId: 12, RequestId: "req-1"}},
+			Total:    1,
+			Page:     query.Page,
+			PageSize: query.PageSize,
+		}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/audit?page=2&page_size=10&actor_id=3&resource=game&action=update&entity_id=12&request_id=req-1&since=2026-10-01T00:00:00Z", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	require.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+	require.NotNil(s.T(), received)
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	assert.EqualValues(s.T(), 2, received.Page)
+	assert.EqualValues(s.T(), 10, received.PageSize)
+	assert.EqualValues(s.T(), 3, received.ActorId)
+	assert.EqualValues(s.T(), 0, received.ApiKeyId)
+	assert.EqualValues(s.T(), "game", received.Resource)
+	assert.EqualValues(s.T(), "update", received.Action)
+	assert.EqualValues(s.T(), 12, received.EntityId)
+	assert.EqualValues(s.T(), "req-1", received.RequestId)
+	require.NotNil(s.T(), received.Since)
+	assert.True(s.T(), since.Equal(*received.Since))
+	assert.Nil(s.T(), received.Until)
+
+	var page domain.AuditPage
+	require.Nil(s.T(), json.Unmarshal(s.rr.Body.Bytes(), &page))
+	assert.EqualValues(s.T(), 1, page.Total)
+	require.Len(s.T(), page.Items, 1)
+	assert.EqualValues(s.T(), 7, page.Items[0].ID)
+}
+
+func (s *AuditControllerTestSuite) TestGetAudit_InvalidFilter() {
+	tests := []struct {
+		query   string
+		message string
+	}{
+		{"actor_id=abc", "actor_id should be a number"},
+		{"page=-1", "page should be a number"},
+		{"since=yesterday", "since should be formatted as RFC 3339, e.g. 2026-10-18T12:00:00Z"},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/audit?"+test.query, nil)
+		s.r.ServeHTTP(rr, req)
+
+		apiErr, err := errorUtils.NewApiErrFromBytes(rr.Body.Bytes())
+		require.Nil(s.T(), err, test.query)
+		assert.EqualValues(s.T(), http.StatusBadRequest, apiErr.Status(), test.query)
+		assert.EqualValues(s.T(), test.message, apiErr.Message(), test.query)
+	}
+}
+
+func (s *AuditControllerTestSuite) TestGetAudit_Failure() {
+	s.mockService.SetGetEntries(func(query *domain.AuditQuery) (*domain.AuditPage, errorUtils.EntityError) {
+		return nil, errorUtils.NewBadRequestError("since cannot be later than until")
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/audit?since=2026-10-02T00:00:00Z&until=2026-10-01T00:00:00Z", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	apiErr, err := errorUtils.NewApiErrFromBytes(s.rr.Body.Bytes())
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusBadRequest, apiErr.Status())
+	assert.EqualValues(s.T(), "since cannot be later than until", apiErr.Message())
+}
+
+func (s *AuditControllerTestSuite) TestExportAudit() {
+	s.mockService.SetExport(func(query *domain.AuditQuery, w io.Writer) errorUtils.EntityError {
+		assert.EqualValues(s.T(), "user", query.Resource)
+		_, _ = io.WriteString(w, "{\"id\":2}\n{\"id\":1}\n")
+		return nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/audit/export?resource=user", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+	assert.EqualValues(s.T(), "application/x-ndjson", s.rr.Header().Get("Content-Type"))
+	assert.EqualValues(s.T(), `attachment; filename="audit.jsonl"`, s.rr.Header().Get("Content-Disposition"))
+	assert.EqualValues(s.T(), "{\"id\":2}\n{\"id\":1}\n", s.rr.Body.String())
+}
+
+func (s *AuditControllerTestSuite) TestExportAudit_Failure() {
+	//nothing was sent yet, so the error can still be reported
+	s.mockService.SetExport(func(query *domain.AuditQuery, w io.Writer) errorUtils.EntityError {
+		return errorUtils.NewInternalServerError("database is down")
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/audit/export", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	apiErr, err := errorUtils.NewApiErrFromBytes(s.rr.Body.Bytes())
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusInternalServerError, apiErr.Status())
+	assert.EqualValues(s.T(), "database is down", apiErr.Message())
+	assert.Empty(s.T(), s.rr.Header().Get("Content-Disposition"))
+}
@@ -7,6 +7,7 @@ import (
 	"GamesAPI/src/utils"
 	"GamesAPI/src/utils/errorUtils"
 	"GamesAPI/tests/unit/mocks"
+	"context"
 	"bytes"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
@@ -19,6 +20,7 @@ import (
 
 type GameControllerTestSuite struct {
 	suite.Suite
+	mockAuditService mocks.AuditServiceMockInterface
 	mockService mocks.GameServiceMockInterface
 	r           *gin.Engine
 	rr          *httptest.ResponseRecorder
@@ -29,7 +31,17 @@ func TestGamesControllerTestSuite(t *testing.T) {
 }
 
 func (s *GameControllerTestSuite) SetupSuite() {
+	auditMock := &mocks.AuditServiceMock{}
+	auditMock.SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+		return nil
+	})
+	s.mockAuditService = auditMock
+	services.AuditService = auditMock
 	mock := &mocks.GameServiceMock{}
+	//looked up before changes, for the audit log
+	mock.SetGetGame(func(id uint64) (*domain.Game, errorUtils.EntityError) {
+		return nil, errorUtils.NewNotFoundError("game not found")
+	})
 	s.mockService = mock
 	services.GamesService = mock
 	s.r = gin.Default()
@@ -300,6 +312,51 @@ func (s *GameControllerTestSuite) TestDeleteGame_Success() {
 	assert.EqualValues(s.T(), "deleted", response["status"])
 }
 
+func (s *GameControllerTestSuite) TestDeleteGame_Audited() {
+	game := &domain.Game{ID: 1, Title: "Rocket League"}
+	s.mockService.SetGetGame(func(id uint64) (*domain.Game, errorUtils.EntityError) {
+		return game, nil
+	})
+	s.mockService.SetDelete(func(u uint64) errorUtils.EntityError {
+		return nil
+	})
+	var recorded []interface{}
+	s.mockAuditService.SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+		recorded = []interface{}{resource, action, entityId, before, after}
+		return nil
+	})
+	defer s.mockAuditService.SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+		return nil
+	})
+	defer s.mockService.SetGetGame(func(id uint64) (*domain.Game, errorUtils.EntityError) {
+		return nil, errorUtils.NewNotFoundError("game not found")
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/games/1", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+	assert.EqualValues(s.T(), []interface{}{"game", "delete", uint64(1), game, nil}, recorded)
+}
+
+func (s *GameControllerTestSuite) TestDeleteGame_FailureNotAudited() {
+	s.mockService.SetDelete(func(u uint64) errorUtils.EntityError {
+		return errorUtils.NewInternalServerError("error deleting game")
+	})
+	audited := false
+	s.mockAuditService.SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+		audited = true
+		return nil
+	})
+	defer s.mockAuditService.SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+		return nil
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/games/1", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusInternalServerError, s.rr.Code)
+	assert.False(s.T(), audited)
+}
+
 func (s *GameControllerTestSuite) TestDeleteGame_InvalidId() {
 	gameIdParam := "abc"
 	req, _ := http.NewRequest(http.MethodDelete, "/games/"+gameIdParam, nil)
@@ -335,8 +392,8 @@ func (s *GameControllerTestSuite) TestDeleteGame_Failure() {
 }
 
 func (s *GameControllerTestSuite) TestGetAllGames_Success() {
-	s.mockService.SetGetAll(func() ([]domain.Game, errorUtils.EntityError) {
-		return []domain.Game{
+	s.mockService.SetGetAll(func(query *domain.GameQuery) (*domain.GamePage, errorUtils.EntityError) {
+		return &domain.GamePage{Total: 2, Page: query.Page, PageSize: query.PageSize, Items: []domain.Game{
 			{
 				ID:          1,
 				Title:       "Rocket League",
@@ -351,7 +408,7 @@ func (s *GameControllerTestSuite) TestGetAllGames_Success() {
 				Publisher:   "CD PROJEKT RED",
 				ReleaseDate: utils.GetDate("2015-05-18"),
 			},
-		}, nil
+		}}, nil
 	})
 
 	req, err := http.NewRequest(http.MethodGet, "/games", nil)
@@ -360,13 +417,17 @@ func (s *GameControllerTestSuite) TestGetAllGames_Success() {
 	}
 	s.r.ServeHTTP(s.rr, req)
 
-	var games []domain.Game
-	theErr := json.Unmarshal(s.rr.Body.Bytes(), &games)
+	var page domain.GamePage
+	theErr := json.Unmarshal(s.rr.Body.Bytes(), &page)
 	if theErr != nil {
 		s.T().Errorf("could not unmarshal response: %v\n", theErr)
 	}
 	t := s.T()
 	assert.Nil(t, err)
+	assert.EqualValues(t, 2, page.Total)
+	assert.EqualValues(t, "", page.Next)
+	assert.EqualValues(t, "", page.Prev)
+	games := page.Items
 	assert.NotNil(t, games)
 	assert.EqualValues(t, 1, games[0].ID)
 	assert.EqualValues(t, "Rocket League", games[0].Title)
@@ -379,7 +440,7 @@ func (s *GameControllerTestSuite) TestGetAllGames_Success() {
 }
 
 func (s *GameControllerTestSuite) TestGetAllGames_Failure() {
-	s.mockService.SetGetAll(func() ([]domain.Game, errorUtils.EntityError) {
+	s.mockService.SetGetAll(func(query *domain.GameQuery) (*domain.GamePage, errorUtils.EntityError) {
 		return nil, errorUtils.NewInternalServerError("error getting games")
 	})
 	req, err := http.NewRequest(http.MethodGet, "/games", nil)
@@ -396,3 +457,46 @@ func (s *GameControllerTestSuite) TestGetAllGames_Failure() {
 	assert.EqualValues(t, "server_error", apiErr.Error())
 	assert.EqualValues(t, http.StatusInternalServerError, apiErr.Status())
 }
+
+func (s *GameControllerTestSuite) TestGetAllGames_QueryParameters() {
+	var received *domain.GameQuery
+	s.mockService.SetGetAll(func(query *domain.GameQuery) (*domain.GamePage, errorUtils.EntityError) {
+		received = query
+		return &domain.GamePage{Total: 45, Page: query.Page, PageSize: query.PageSize, Items: []domain.Game{}}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet,
+		"/games?page=2&page_size=20&developer=Psyonix&steam_id=252950&released_after=2015-01-01&sort=title&order=desc", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	var page domain.GamePage
+	err := json.Unmarshal(s.rr.Body.Bytes(), &page)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.EqualValues(t, 2, received.Page)
+	assert.EqualValues(t, 20, received.PageSize)
+	assert.EqualValues(t, "Psyonix", received.Developer)
+	assert.EqualValues(t, "252950", received.SteamId)
+	assert.EqualValues(t, utils.GetDate("2015-01-01"), *received.ReleasedAfter)
+	assert.Nil(t, received.ReleasedBefore)
+	assert.EqualValues(t, "title", received.Sort)
+	assert.True(t, received.Descending)
+	assert.Contains(t, page.Next, "page=3")
+	assert.Contains(t, page.Prev, "page=1")
+	assert.Contains(t, page.Next, "developer=Psyonix")
+}
+
+func (s *GameControllerTestSuite) TestGetAllGames_InvalidQueryParameters() {
+	for _, query := range []string{"page=abc", "page_size=-1", "released_before=yesterday", "order=sideways"} {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/games?"+query, nil)
+		s.r.ServeHTTP(rr, req)
+
+		apiErr, err := errorUtils.NewApiErrFromBytes(rr.Body.Bytes())
+		t := s.T()
+		assert.Nil(t, err)
+		assert.EqualValues(t, http.StatusBadRequest, apiErr.Status(), query)
+		assert.EqualValues(t, "bad_request", apiErr.Error(), query)
+	}
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/router"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type LibraryControllerTestSuite struct {
+	suite.Suite
+	mockService mocks.UserGamesServiceMockInterface
+	r           *gin.Engine
+	rr          *httptest.ResponseRecorder
+}
+
+func TestLibraryControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(LibraryControllerTestSuite))
+}
+
+func (s *LibraryControllerTestSuite) SetupSuite() {
+	mock := &mocks.UserGamesServiceMock{}
+	s.mockService = mock
+	services.UserGamesService = mock
+	s.r = gin.Default()
+	//library routes are nested under the user routes, make sure they can live together
+	router.InitAllUserRoutes(s.r.Group(""))
+	router.InitAllLibraryRoutes(s.r.Group(""))
+}
+
+func (s *LibraryControllerTestSuite) BeforeTest(_, _ string) {
+	s.rr = httptest.NewRecorder()
+}
+
+func (s *LibraryControllerTestSuite) TestGetUserLibrary_Success() {
+	s.mockService.SetGetLibrary(func(userId uint64) ([]domain.UserGame, errorUtils.EntityError) {
+		return []domain.UserGame{
+			{ID: 1, UserID: userId, GameID: 10, Source: domain.UserGameSourceSteam, PlaytimeForever: 120,
+				Game: &domain.Game{ID: 10, Title: "Rocket League"}},
+		}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/users/3/library", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	var library []domain.UserGame
+	err := json.Unmarshal(s.rr.Body.Bytes(), &library)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.Len(t, library, 1)
+	assert.EqualValues(t, 3, library[0].UserID)
+	assert.EqualValues(t, 120, library[0].PlaytimeForever)
+	assert.EqualValues(t, "Rocket League", library[0].Game.Title)
+}
+
+func (s *LibraryControllerTestSuite) TestGetUserLibrary_InvalidUserId() {
+	req, _ := http.NewRequest(http.MethodGet, "/users/abc/library", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	apiErr, err := errorUtils.NewApiErrFromBytes(s.rr.Body.Bytes())
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, apiErr.Status())
+	assert.EqualValues(t, "user id should be a number", apiErr.Message())
+}
+
+func (s *LibraryControllerTestSuite) TestGetUserLibraryGame_NotFound() {
+	s.mockService.SetGetLibraryEntry(func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+		return nil, errorUtils.NewNotFoundError("record not found")
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/users/3/library/10", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	apiErr, err := errorUtils.NewApiErrFromBytes(s.rr.Body.Bytes())
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, apiErr.Status())
+}
+
+func (s *LibraryControllerTestSuite) TestGetUserLibraryGame_DefaultsToSteam() {
+	var receivedSource string
+	s.mockService.SetGetLibraryEntry(func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+		receivedSource = source
+		return &domain.UserGame{ID: 1, UserID: userId, GameID: gameId, Source: source}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/users/3/library/10", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.EqualValues(t, domain.UserGameSourceSteam, receivedSource)
+}
+
+func (s *LibraryControllerTestSuite) TestDeleteUserLibraryGame_Success() {
+	s.mockService.SetDeleteLibraryEntry(func(userId uint64, gameId uint64, source string) errorUtils.EntityError {
+		return nil
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/users/3/library/10", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+}
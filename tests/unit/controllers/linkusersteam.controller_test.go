@@ -7,6 +7,7 @@ import (
 	"GamesAPI/src/services"
 	"GamesAPI/src/utils/errorUtils"
 	"GamesAPI/tests/unit/mocks"
+	"context"
 	"bytes"
 	"errors"
 	"fmt"
@@ -20,6 +21,7 @@ import (
 
 type LinkSteamUserTestSuite struct {
 	suite.Suite
+	mockAuditService mocks.AuditServiceMockInterface
 	mockUserService  mocks.UserServiceMockInterface
 	mockSteamService mocks.SteamUserMockInterface
 	r                *gin.Engine
@@ -31,6 +33,12 @@ func TestLinkSteamUsersControllerTestSuite(t *testing.T) {
 }
 
 func (s *LinkSteamUserTestSuite) SetupSuite() {
+	auditMock := &mocks.AuditServiceMock{}
+	auditMock.SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+		return nil
+	})
+	s.mockAuditService = auditMock
+	services.AuditService = auditMock
 	mock := &mocks.UserServiceMock{}
 	steammock := &mocks.SteamUserMock{}
 	s.mockSteamService = steammock
@@ -54,7 +62,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_ValidProfile() {
 		}, nil
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev",
@@ -80,7 +88,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_InvalidProfile() {
 		}, nil
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev",
@@ -104,7 +112,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_ValidSteamId() {
 		}, nil
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev",
@@ -133,7 +141,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_invalidSteamId() {
 		}, nil
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev",
@@ -163,7 +171,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_InvalidValidateUrl_null() {
 		}, nil
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev",
@@ -189,7 +197,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_InvalidValidateUrl_steamcommu
 		}, nil
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev",
@@ -217,7 +225,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_InvalidValidateUrl_len() {
 		}, nil
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev",
@@ -245,7 +253,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_InvalidValidateUrl_component_
 		}, nil
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev",
@@ -269,7 +277,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_InvalidGetUserid() {
 		return nil, expectedErr
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev",
@@ -296,7 +304,7 @@ func (s *LinkSteamUserTestSuite) TestLinkUserSteam_InvalidUpdateUser() {
 		}, nil
 	})
 
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return nil, expectedErr
 	})
 
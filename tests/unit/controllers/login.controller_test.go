@@ -3,9 +3,12 @@ package controllers
 import (
 	"GamesAPI/src/controllers"
 	"GamesAPI/src/domain"
+	"GamesAPI/src/middleware"
 	"GamesAPI/src/services"
 	"GamesAPI/src/utils/errorUtils"
 	"GamesAPI/tests/unit/mocks"
+	"bytes"
+	"context"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -40,126 +43,110 @@ func (s *LoginControllerTestSuite) SetupSuite() {
 	services.AuthenticationService = authMock
 	services.UsersService = usersMock
 	s.r = gin.Default()
-	s.r.GET("/auth/login", controllers.LoginController)
+	s.r.POST("/auth/login", controllers.LoginController)
 }
 
 func (s *LoginControllerTestSuite) BeforeTest(_, _ string) {
 	s.rr = httptest.NewRecorder()
 }
 
-func (s *LoginControllerTestSuite) TestLogin_NoHeader() {
-	req, _ := http.NewRequest(http.MethodGet, "/auth/login", nil)
-	s.r.ServeHTTP(s.rr, req)
+const loginBody = `{"email": "dev@golang.com", "password": "some_password"}`
+
+func newLoginRequest(body string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(body))
+	req.RemoteAddr = "192.0.2.1:4242"
+	return req
+}
+
+func (s *LoginControllerTestSuite) authenticateAs(user *domain.User) {
+	s.mockAuthenticationService.SetAuthenticate(func(email string, password []byte, ip string) (*domain.User, errorUtils.EntityError) {
+		return user, nil
+	})
+}
+
+func (s *LoginControllerTestSuite) TestLogin_NoBody() {
+	s.r.ServeHTTP(s.rr, newLoginRequest(""))
 	t := s.T()
 	assert.Equal(t, http.StatusBadRequest, s.rr.Code)
 }
 
-func (s *LoginControllerTestSuite) TestLogin_BadHeader() {
-	req, _ := http.NewRequest(http.MethodGet, "/auth/login", nil)
-	req.Header.Add("Authorization", "username;password;favorite_fantasy_animal")
-	s.r.ServeHTTP(s.rr, req)
+func (s *LoginControllerTestSuite) TestLogin_MissingPassword() {
+	s.r.ServeHTTP(s.rr, newLoginRequest(`{"email": "dev@golang.com"}`))
 	t := s.T()
 	assert.Equal(t, http.StatusBadRequest, s.rr.Code)
 }
 
-func (s *LoginControllerTestSuite) TestLogin_ErrGettingAllUsers() {
-	s.mockUsersService.SetGetAll(func() ([]domain.User, errorUtils.EntityError) {
-		return nil, errorUtils.NewInternalServerError("Sumtin wong")
+func (s *LoginControllerTestSuite) TestLogin_BadCredentials() {
+	s.mockAuthenticationService.SetAuthenticate(func(email string, password []byte, ip string) (*domain.User, errorUtils.EntityError) {
+		assert.Equal(s.T(), "dev@golang.com", email)
+		assert.Equal(s.T(), "some_password", string(password))
+		assert.Equal(s.T(), "192.0.2.1", ip)
+		return nil, errorUtils.NewUnauthorizedError("Bad username/password combination")
 	})
 
-	req, _ := http.NewRequest(http.MethodGet, "/auth/login", nil)
-	req.Header.Add("Authorization", "username;password")
-	s.r.ServeHTTP(s.rr, req)
+	s.r.ServeHTTP(s.rr, newLoginRequest(loginBody))
 	t := s.T()
-	assert.Equal(t, http.StatusInternalServerError, s.rr.Code)
+	assert.Equal(t, http.StatusUnauthorized, s.rr.Code)
 }
 
-func (s *LoginControllerTestSuite) TestLogin_NoUserMatchesEmail() {
-	s.mockUsersService.SetGetAll(func() ([]domain.User, errorUtils.EntityError) {
-		return []domain.User{
-			{
-				ID:           1,
-				Name:         "dev",
-				Email:        "dev@golang.com",
-				PasswordHash: "epsteindidntkillhimself",
-			},
-		}, nil
+func (s *LoginControllerTestSuite) TestLogin_LockedOut() {
+	previous := services.LoginAttemptService
+	services.LoginAttemptService = services.NewLoginAttemptService(services.LoginAttemptConfig{MaxPerAccount: 1, Lockout: time.Minute})
+	defer func() {
+		services.LoginAttemptService = previous
+	}()
+	services.LoginAttemptService.RecordFailure("dev@golang.com", "192.0.2.1", time.Now())
+	s.mockAuthenticationService.SetAuthenticate(func(email string, password []byte, ip string) (*domain.User, errorUtils.EntityError) {
+		return nil, errorUtils.NewTooManyRequestsError("too many failed login attempts")
 	})
 
-	req, _ := http.NewRequest(http.MethodGet, "/auth/login", nil)
-	req.Header.Add("Authorization", "golang@dev.com;some_password")
-	s.r.ServeHTTP(s.rr, req)
+	s.r.ServeHTTP(s.rr, newLoginRequest(loginBody))
 	t := s.T()
-	assert.Equal(t, http.StatusUnauthorized, s.rr.Code)
+	assert.Equal(t, http.StatusTooManyRequests, s.rr.Code)
+	assert.Equal(t, "60", s.rr.Header().Get("Retry-After"))
 }
 
-func (s *LoginControllerTestSuite) TestLogin_PasswordInvalid() {
-	s.mockUsersService.SetGetAll(func() ([]domain.User, errorUtils.EntityError) {
-		return []domain.User{
-			{
-				ID:           1,
-				Name:         "dev",
-				Email:        "dev@golang.com",
-				PasswordHash: "epsteindidntkillhimself",
-			},
-		}, nil
+func (s *LoginControllerTestSuite) TestLogin_ForgedForwardedForLockedOut() {
+	previousAttempts, previousAuthentication := services.LoginAttemptService, services.AuthenticationService
+	services.LoginAttemptService = services.NewLoginAttemptService(services.LoginAttemptConfig{MaxPerIP: 2, Lockout: time.Minute})
+	services.AuthenticationService = &services.AuthService{}
+	defer func() {
+		services.LoginAttemptService, services.AuthenticationService = previousAttempts, previousAuthentication
+	}()
+	s.mockUsersService.SetGetUserByEmail(func(email string) (*domain.User, errorUtils.EntityError) {
+		return nil, errorUtils.NewNotFoundError("user not found")
 	})
+	r := gin.New()
+	middleware.InitClientIP(r)
+	r.POST("/auth/login", controllers.LoginController)
 
-	s.mockAuthenticationService.SetValidatePassword(func(plainPassword []byte, hashedPassword string) (bool, error) {
-		return false, nil
-	})
-
-	req, _ := http.NewRequest(http.MethodGet, "/auth/login", nil)
-	req.Header.Add("Authorization", "dev@golang.com;some_password")
-	s.r.ServeHTTP(s.rr, req)
-	t := s.T()
-	assert.Equal(t, http.StatusUnauthorized, s.rr.Code)
+	var codes []int
+	for _, forwarded := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+		rr := httptest.NewRecorder()
+		req := newLoginRequest(loginBody)
+		req.Header.Set("X-Forwarded-For", forwarded)
+		r.ServeHTTP(rr, req)
+		codes = append(codes, rr.Code)
+	}
+	assert.EqualValues(s.T(), []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
 }
 
 func (s *LoginControllerTestSuite) TestLogin_CouldNotGenerateSessionToken() {
-	s.mockUsersService.SetGetAll(func() ([]domain.User, errorUtils.EntityError) {
-		return []domain.User{
-			{
-				ID:           1,
-				Name:         "dev",
-				Email:        "dev@golang.com",
-				PasswordHash: "epsteindidntkillhimself",
-			},
-		}, nil
-	})
-
-	s.mockAuthenticationService.SetValidatePassword(func(plainPassword []byte, hashedPassword string) (bool, error) {
-		return true, nil
-	})
+	s.authenticateAs(&domain.User{ID: 1, Name: "dev", Email: "dev@golang.com"})
 
-	s.mockUserSessionService.SetGenerateSessionToken(func(userId uint64, expireAt time.Time) (string, error) {
+	s.mockUserSessionService.SetGenerateSessionToken(func() (string, error) {
 		return "", errors.New("could not generate token")
 	})
 
-	req, _ := http.NewRequest(http.MethodGet, "/auth/login", nil)
-	req.Header.Add("Authorization", "dev@golang.com;some_password")
-	s.r.ServeHTTP(s.rr, req)
+	s.r.ServeHTTP(s.rr, newLoginRequest(loginBody))
 	t := s.T()
 	assert.Equal(t, http.StatusInternalServerError, s.rr.Code)
 }
 
 func (s *LoginControllerTestSuite) TestLogin_CouldNotCreateUserSession() {
-	s.mockUsersService.SetGetAll(func() ([]domain.User, errorUtils.EntityError) {
-		return []domain.User{
-			{
-				ID:           1,
-				Name:         "dev",
-				Email:        "dev@golang.com",
-				PasswordHash: "epsteindidntkillhimself",
-			},
-		}, nil
-	})
-
-	s.mockAuthenticationService.SetValidatePassword(func(plainPassword []byte, hashedPassword string) (bool, error) {
-		return true, nil
-	})
+	s.authenticateAs(&domain.User{ID: 1, Name: "dev", Email: "dev@golang.com"})
 
-	s.mockUserSessionService.SetGenerateSessionToken(func(userId uint64, expireAt time.Time) (string, error) {
+	s.mockUserSessionService.SetGenerateSessionToken(func() (string, error) {
 		return "some_token", nil
 	})
 
@@ -167,30 +154,15 @@ func (s *LoginControllerTestSuite) TestLogin_CouldNotCreateUserSession() {
 		return nil, errorUtils.NewInternalServerError("could not create user session")
 	})
 
-	req, _ := http.NewRequest(http.MethodGet, "/auth/login", nil)
-	req.Header.Add("Authorization", "dev@golang.com;some_password")
-	s.r.ServeHTTP(s.rr, req)
+	s.r.ServeHTTP(s.rr, newLoginRequest(loginBody))
 	t := s.T()
 	assert.Equal(t, http.StatusInternalServerError, s.rr.Code)
 }
 
 func (s *LoginControllerTestSuite) TestLogin_Success() {
-	s.mockUsersService.SetGetAll(func() ([]domain.User, errorUtils.EntityError) {
-		return []domain.User{
-			{
-				ID:           1,
-				Name:         "dev",
-				Email:        "dev@golang.com",
-				PasswordHash: "epsteindidntkillhimself",
-			},
-		}, nil
-	})
-
-	s.mockAuthenticationService.SetValidatePassword(func(plainPassword []byte, hashedPassword string) (bool, error) {
-		return true, nil
-	})
+	s.authenticateAs(&domain.User{ID: 1, Name: "dev", Email: "dev@golang.com"})
 
-	s.mockUserSessionService.SetGenerateSessionToken(func(userId uint64, expireAt time.Time) (string, error) {
+	s.mockUserSessionService.SetGenerateSessionToken(func() (string, error) {
 		return "some_token", nil
 	})
 
@@ -202,11 +174,113 @@ func (s *LoginControllerTestSuite) TestLogin_Success() {
 		}, nil
 	})
 
-	req, _ := http.NewRequest(http.MethodGet, "/auth/login", nil)
-	req.Header.Add("Authorization", "dev@golang.com;some_password")
-	s.r.ServeHTTP(s.rr, req)
+	s.r.ServeHTTP(s.rr, newLoginRequest(loginBody))
 	t := s.T()
 	assert.Equal(t, http.StatusOK, s.rr.Code)
 	//check if we got the token back in the response
 	assert.Equal(t, "some_token", s.rr.Header().Get("Authorization"))
+	assert.NotEmpty(t, s.rr.Header().Get("Refresh-Token"))
+}
+
+type RefreshControllerTestSuite struct {
+	suite.Suite
+	mockService     mocks.RefreshTokenServiceMockInterface
+	previousService services.RefreshTokenServiceInterface
+	r               *gin.Engine
+	rr              *httptest.ResponseRecorder
+}
+
+func TestRefreshControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshControllerTestSuite))
+}
+
+func (s *RefreshControllerTestSuite) SetupSuite() {
+	mock := &mocks.RefreshTokenServiceMock{}
+	s.mockService = mock
+	s.previousService = services.RefreshTokenService
+	services.RefreshTokenService = mock
+	s.r = gin.Default()
+	s.r.POST("/auth/refresh", controllers.RefreshController)
+	//stands in for the session handler, the authenticated user is 3
+	s.r.POST("/auth/logout", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), domain.RbacUserId(), uint64(3)))
+	}, controllers.LogoutController)
+}
+
+func (s *RefreshControllerTestSuite) TearDownSuite() {
+	services.RefreshTokenService = s.previousService
+}
+
+func (s *RefreshControllerTestSuite) BeforeTest(_, _ string) {
+	s.rr = httptest.NewRecorder()
+}
+
+func (s *RefreshControllerTestSuite) TestRefresh_MissingToken() {
+	req, _ := http.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBufferString(`{}`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusBadRequest, s.rr.Code)
+}
+
+func (s *RefreshControllerTestSuite) TestRefresh_Rejected() {
+	s.mockService.SetRefresh(func(refreshToken string) (*domain.TokenPair, errorUtils.EntityError) {
+		return nil, errorUtils.NewUnauthorizedError("refresh token was already used")
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBufferString(`{"refresh_token": "old"}`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusUnauthorized, s.rr.Code)
+}
+
+func (s *RefreshControllerTestSuite) TestRefresh_Success() {
+	s.mockService.SetRefresh(func(refreshToken string) (*domain.TokenPair, errorUtils.EntityError) {
+		assert.Equal(s.T(), "current", refreshToken)
+		return &domain.TokenPair{AccessToken: "access", RefreshToken: "next"}, nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBufferString(`{"refresh_token": "current"}`))
+	s.r.ServeHTTP(s.rr, req)
+	t := s.T()
+	assert.Equal(t, http.StatusOK, s.rr.Code)
+	assert.Equal(t, "access", s.rr.Header().Get("Authorization"))
+	assert.Equal(t, "next", s.rr.Header().Get("Refresh-Token"))
+}
+
+func (s *RefreshControllerTestSuite) TestLogout_Success() {
+	s.mockService.SetLogout(func(userId uint64, accessToken string, refreshToken string) errorUtils.EntityError {
+		assert.EqualValues(s.T(), 3, userId)
+		assert.Equal(s.T(), "access", accessToken)
+		assert.Equal(s.T(), "current", refreshToken)
+		return nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBufferString(`{"refresh_token": "current"}`))
+	req.Header.Set("Authorization", "Bearer access")
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusOK, s.rr.Code)
+}
+
+func (s *RefreshControllerTestSuite) TestLogout_WithoutBody() {
+	s.mockService.SetLogout(func(userId uint64, accessToken string, refreshToken string) errorUtils.EntityError {
+		assert.Equal(s.T(), "access", accessToken)
+		assert.Equal(s.T(), "", refreshToken)
+		return nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer access")
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusOK, s.rr.Code)
+}
+
+func (s *RefreshControllerTestSuite) TestLogout_BadBody() {
+	req, _ := http.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBufferString(`not json`))
+	req.Header.Set("Authorization", "Bearer access")
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusBadRequest, s.rr.Code)
+}
+
+func (s *RefreshControllerTestSuite) TestLogout_Rejected() {
+	s.mockService.SetLogout(func(userId uint64, accessToken string, refreshToken string) errorUtils.EntityError {
+		return errorUtils.NewUnauthorizedError("invalid refresh token")
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBufferString(`{"refresh_token": "someone-else"}`))
+	req.Header.Set("Authorization", "Bearer access")
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusUnauthorized, s.rr.Code)
 }
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/router"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type RbacControllerTestSuite struct {
+	suite.Suite
+	mockService       mocks.AuthorizationServiceMockInterface
+	mockPolicyService mocks.RbacPolicyServiceMockInterface
+	r                 *gin.Engine
+	rr          *httptest.ResponseRecorder
+}
+
+func TestRbacControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(RbacControllerTestSuite))
+}
+
+func (s *RbacControllerTestSuite) SetupSuite() {
+	mock := &mocks.AuthorizationServiceMock{}
+	s.mockService = mock
+	services.AuthorizationService = mock
+	policyMock := &mocks.RbacPolicyServiceMock{}
+	s.mockPolicyService = policyMock
+	services.RbacPolicyService = policyMock
+	s.r = gin.Default()
+	s.r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), domain.RbacUserId(), uint64(3)))
+	})
+	router.InitAllRbacRoutes(s.r.Group(""))
+}
+
+func (s *RbacControllerTestSuite) BeforeTest(_, _ string) {
+	s.rr = httptest.NewRecorder()
+}
+
+func (s *RbacControllerTestSuite) TestGetRbacPolicy() {
+	failedAt := time.Now()
+	s.mockService.SetPolicyInfo(func() services.RbacPolicyInfo {
+		return services.RbacPolicyInfo{Path: "role-based-access.yml", Version: 3, Hash: "abc", LastError: "yaml: line 3", LastErrorAt: &failedAt}
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/rbac/policy", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	var info map[string]interface{}
+	err := json.Unmarshal(s.rr.Body.Bytes(), &info)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.EqualValues(t, 3, info["version"])
+	assert.EqualValues(t, "abc", info["hash"])
+	assert.EqualValues(t, "yaml: line 3", info["last_error"])
+}
+
+func (s *RbacControllerTestSuite) TestGetRbacRole() {
+	s.mockPolicyService.SetGetPolicy(func() (domain.RbacPolicy, errorUtils.EntityError) {
+		return domain.RbacPolicy{"moderator": {Inherits: []string{"user"}, Resources: domain.Resource{"game": domain.Endpoint{"*": {Allow: true}}}}}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/rbac/roles/moderator", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	var role domain.RbacPolicyRole
+	require.Nil(t, json.Unmarshal(s.rr.Body.Bytes(), &role))
+	assert.EqualValues(t, []string{"user"}, role.Inherits)
+	assert.True(t, role.Resources["game"]["*"].Allow)
+
+	s.rr = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/rbac/roles/ghost", nil)
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(t, http.StatusNotFound, s.rr.Code)
+}
+
+func (s *RbacControllerTestSuite) TestPutRbacRole() {
+	var gotName string
+	var gotRole domain.RbacPolicyRole
+	var gotAuthor uint64
+	s.mockPolicyService.SetPutRole(func(name string, role domain.RbacPolicyRole, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError) {
+		gotName, gotRole, gotAuthor = name, role, authorId
+		return &services.RbacPolicyUpdate{Version: &domain.RbacPolicyVersion{Version: 4}, Warnings: []string{}}, nil
+	})
+	body := `{"inherits": ["user"], "resources": {"library": {"read": {"allow": true, "ensure": {"path": [{"key": "id", "operator": "=", "value": "ctx.userId"}]}}}}}`
+	req, _ := http.NewRequest(http.MethodPut, "/rbac/roles/editor", strings.NewReader(body))
+	s.r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.EqualValues(t, "editor", gotName)
+	assert.EqualValues(t, 3, gotAuthor)
+	assert.EqualValues(t, []domain.Rule{{Key: "id", Operator: "=", Value: "ctx.userId"}}, gotRole.Resources["library"]["read"].Ensure.Path)
+	assert.Contains(t, s.rr.Body.String(), `"version":4`)
+}
+
+func (s *RbacControllerTestSuite) TestPutRbacPermission_Refused() {
+	s.mockPolicyService.SetPutPermission(func(role string, resource string, action string, permission domain.Permission, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError) {
+		return nil, errorUtils.NewConflictError("the rbac policy is read from a file, set RBAC_SOURCE=database to manage it through the API")
+	})
+	req, _ := http.NewRequest(http.MethodPut, "/rbac/roles/user/permissions/game/*", strings.NewReader(`{"allow": true}`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusConflict, s.rr.Code)
+
+	s.rr = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/rbac/roles/user/permissions/game/read", strings.NewReader(`{"allow": "yes"}`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusUnprocessableEntity, s.rr.Code)
+}
+
+func (s *RbacControllerTestSuite) TestRollbackRbacPolicy() {
+	var gotVersion uint64
+	s.mockPolicyService.SetRollback(func(version uint64, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError) {
+		gotVersion = version
+		return &services.RbacPolicyUpdate{Version: &domain.RbacPolicyVersion{Version: 5, Comment: "rollback to version 2"}, Warnings: []string{}}, nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/rbac/versions/2/rollback", nil)
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+	assert.EqualValues(s.T(), 2, gotVersion)
+
+	s.rr = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/rbac/versions/latest/rollback", nil)
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusBadRequest, s.rr.Code)
+}
+
+func (s *RbacControllerTestSuite) TestExplainRbac() {
+	var gotRoles []string
+	var gotResource, gotEndpoint, gotRole string
+	s.mockService.SetExplain(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) *domain.RbacTrace {
+		gotRoles, gotResource, gotEndpoint = roles, resource, endpoint
+		gotRole, _ = ctx.Value(domain.RbacRouteParams()).(map[string]string)["role"]
+		return &domain.RbacTrace{Resource: resource, Endpoint: endpoint, Allowed: false, Error: errorUtils.ErrForbidden.Error(),
+			Roles: []domain.RbacRoleTrace{{Role: "user", Entry: "rbac.*", Outcome: domain.RbacOutcomeDenied, Checks: []domain.RbacRuleCheck{}}}}
+	})
+	body := `{"roles": ["USER"], "method": "get", "path": "/rbac/roles/editor"}`
+	req, _ := http.NewRequest(http.MethodPost, "/rbac/explain", strings.NewReader(body))
+	s.r.ServeHTTP(s.rr, req)
+
+	var trace domain.RbacTrace
+	err := json.Unmarshal(s.rr.Body.Bytes(), &trace)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.EqualValues(t, []string{"user"}, gotRoles)
+	assert.EqualValues(t, "rbac", gotResource)
+	assert.EqualValues(t, "read", gotEndpoint)
+	assert.EqualValues(t, "editor", gotRole)
+	assert.False(t, trace.Allowed)
+	require.Len(t, trace.Roles, 1)
+	assert.EqualValues(t, domain.RbacOutcomeDenied, trace.Roles[0].Outcome)
+}
+
+func (s *RbacControllerTestSuite) TestExplainRbac_Invalid() {
+	for _, body := range []string{
+		`{"roles": ["user"], "path": "/rbac/roles"}`,
+		`{"method": "GET", "path": "/rbac/roles"}`,
+		`{"roles": ["user"], "method": "GET", "path": "rbac/roles"}`,
+	} {
+		s.rr = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/rbac/explain", strings.NewReader(body))
+		s.r.ServeHTTP(s.rr, req)
+		assert.EqualValues(s.T(), http.StatusUnprocessableEntity, s.rr.Code, body)
+	}
+
+	s.rr = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/rbac/explain", strings.NewReader(`{"roles": ["user"], "method": "GET", "path": "/nowhere"}`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusNotFound, s.rr.Code)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/router"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type RolesControllerTestSuite struct {
+	suite.Suite
+	mockAuditService mocks.AuditServiceMockInterface
+	mockService mocks.UserRoleServiceMockInterface
+	r           *gin.Engine
+	rr          *httptest.ResponseRecorder
+}
+
+func TestRolesControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(RolesControllerTestSuite))
+}
+
+func (s *RolesControllerTestSuite) SetupSuite() {
+	auditMock := &mocks.AuditServiceMock{}
+	auditMock.SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+		return nil
+	})
+	s.mockAuditService = auditMock
+	services.AuditService = auditMock
+	mock := &mocks.UserRoleMock{}
+	//looked up before changes, for the audit log
+	mock.SetGetRole(func(userRoleId uint64) (*domain.UserRole, errorUtils.EntityError) {
+		return nil, errorUtils.NewNotFoundError("role not found")
+	})
+	s.mockService = mock
+	services.UserRoleService = mock
+	s.r = gin.Default()
+	router.InitAllRoleRoutes(s.r.Group(""))
+}
+
+func (s *RolesControllerTestSuite) BeforeTest(_, _ string) {
+	s.rr = httptest.NewRecorder()
+}
+
+func (s *RolesControllerTestSuite) TestGetRoles_ByName() {
+	s.mockService.SetGetRolesByRoleName(func(name string) ([]domain.UserRole, errorUtils.EntityError) {
+		assert.EqualValues(s.T(), "admin", name)
+		return []domain.UserRole{{ID: 1, UserID: 1, Name: "admin"}}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/roles?name=admin", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	var roles []domain.UserRole
+	t := s.T()
+	require.Nil(t, json.Unmarshal(s.rr.Body.Bytes(), &roles))
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.Len(t, roles, 1)
+}
+
+func (s *RolesControllerTestSuite) TestAddUserRole() {
+	s.mockService.SetAssignRole(func(userId uint64, name string) (*domain.UserRole, errorUtils.EntityError) {
+		assert.EqualValues(s.T(), 3, userId)
+		assert.EqualValues(s.T(), "moderator", name)
+		return &domain.UserRole{ID: 4, UserID: userId, Name: name}, nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/users/3/roles", strings.NewReader(`{"name":"moderator"}`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusCreated, s.rr.Code)
+
+	s.rr = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/users/3/roles", strings.NewReader(`["moderator"]`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusUnprocessableEntity, s.rr.Code)
+
+	s.rr = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/users/me/roles", strings.NewReader(`{"name":"moderator"}`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusBadRequest, s.rr.Code)
+}
+
+func (s *RolesControllerTestSuite) TestUpdateRole_UnknownRole() {
+	s.mockService.SetChangeRole(func(roleId uint64, name string) (*domain.UserRole, errorUtils.EntityError) {
+		return nil, errorUtils.NewUnprocessableEntityError("role superuser is not in the rbac policy, expected one of admin, user")
+	})
+	req, _ := http.NewRequest(http.MethodPatch, "/roles/2", strings.NewReader(`{"name":"superuser"}`))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusUnprocessableEntity, s.rr.Code)
+}
+
+func (s *RolesControllerTestSuite) TestDeleteRole_LastAdmin() {
+	s.mockService.SetRevokeRole(func(roleId uint64) errorUtils.EntityError {
+		assert.EqualValues(s.T(), 1, roleId)
+		return errorUtils.NewConflictError("user 1 is the last admin, give the role to someone else first")
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/roles/1", nil)
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusConflict, s.rr.Code)
+	assert.Contains(s.T(), s.rr.Body.String(), "last admin")
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/router"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type SessionsControllerTestSuite struct {
+	suite.Suite
+	mockService mocks.UserSessionServiceMockInterface
+	r           *gin.Engine
+	rr          *httptest.ResponseRecorder
+}
+
+func TestSessionsControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionsControllerTestSuite))
+}
+
+func (s *SessionsControllerTestSuite) SetupSuite() {
+	mock := &mocks.UserSessionServiceMock{}
+	s.mockService = mock
+	services.UserSessionService = mock
+	s.r = gin.Default()
+	//stands in for the session handler, the authenticated user is 3
+	s.r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), domain.RbacUserId(), uint64(3)))
+	})
+	router.InitAllUserRoutes(s.r.Group(""))
+	router.InitAllSessionRoutes(s.r.Group(""))
+}
+
+func (s *SessionsControllerTestSuite) BeforeTest(_, _ string) {
+	s.rr = httptest.NewRecorder()
+}
+
+func (s *SessionsControllerTestSuite) TestGetUserSessions_Success() {
+	s.mockService.SetGetUserSessions(func(userId uint64) ([]domain.UserSession, errorUtils.EntityError) {
+		return []domain.UserSession{{Token: "hashed", UserId: userId}}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/users/3/sessions", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	var sessions []map[string]interface{}
+	err := json.Unmarshal(s.rr.Body.Bytes(), &sessions)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.Len(t, sessions, 1)
+	assert.EqualValues(t, "hashed", sessions[0]["id"])
+}
+
+func (s *SessionsControllerTestSuite) TestDeleteUserSessions_Success() {
+	s.mockService.SetRevokeUserSessions(func(userId uint64) (int64, errorUtils.EntityError) {
+		assert.EqualValues(s.T(), 5, userId)
+		return 2, nil
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/users/5/sessions", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+}
+
+func (s *SessionsControllerTestSuite) TestDeleteSession_UsesAuthenticatedUser() {
+	s.mockService.SetRevokeSession(func(userId uint64, sessionId string) errorUtils.EntityError {
+		assert.EqualValues(s.T(), 3, userId)
+		assert.EqualValues(s.T(), "hashed", sessionId)
+		return nil
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/sessions/hashed", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+}
+
+func (s *SessionsControllerTestSuite) TestDeleteSession_NotFound() {
+	s.mockService.SetRevokeSession(func(userId uint64, sessionId string) errorUtils.EntityError {
+		return errorUtils.NewNotFoundError("session hashed does not exist")
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/sessions/hashed", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusNotFound, s.rr.Code)
+}
+
+func (s *SessionsControllerTestSuite) TestForceLogoutUser_Success() {
+	s.mockService.SetRevokeUserSessions(func(userId uint64) (int64, errorUtils.EntityError) {
+		assert.EqualValues(s.T(), 5, userId)
+		return 2, nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/users/5/logout", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	var body map[string]interface{}
+	_ = json.Unmarshal(s.rr.Body.Bytes(), &body)
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+	assert.EqualValues(s.T(), 2, body["sessions"])
+}
+
+func (s *SessionsControllerTestSuite) TestForceLogoutUser_BadId() {
+	req, _ := http.NewRequest(http.MethodPost, "/users/abc/logout", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusBadRequest, s.rr.Code)
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"GamesAPI/src/controllers"
+	"GamesAPI/src/domain"
+	"GamesAPI/src/router"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"context"
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type SyncJobsControllerTestSuite struct {
+	suite.Suite
+	mockAuditService mocks.AuditServiceMockInterface
+	mockService     mocks.SyncJobsServiceMockInterface
+	mockUserService mocks.UserServiceMockInterface
+	r               *gin.Engine
+	rr              *httptest.ResponseRecorder
+}
+
+func TestSyncJobsControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncJobsControllerTestSuite))
+}
+
+func (s *SyncJobsControllerTestSuite) SetupSuite() {
+	auditMock := &mocks.AuditServiceMock{}
+	auditMock.SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+		return nil
+	})
+	s.mockAuditService = auditMock
+	services.AuditService = auditMock
+	mock := &mocks.SyncJobsServiceMock{}
+	s.mockService = mock
+	services.SyncJobsService = mock
+	userMock := &mocks.UserServiceMock{}
+	s.mockUserService = userMock
+	services.UsersService = userMock
+	s.r = gin.Default()
+	router.InitAllSyncJobRoutes(s.r.Group(""))
+	s.r.POST("/SyncGames", controllers.SyncGamesHandler)
+}
+
+func (s *SyncJobsControllerTestSuite) BeforeTest(_, _ string) {
+	s.rr = httptest.NewRecorder()
+}
+
+func (s *SyncJobsControllerTestSuite) TestSyncGames_Enqueued() {
+	s.mockUserService.SetGetUser(func(id uint64) (*domain.User, errorUtils.EntityError) {
+		return &domain.User{ID: id, SteamUserId: "76561198000000000"}, nil
+	})
+	s.mockService.SetEnqueueSync(func(user *domain.User) (*domain.SyncJob, errorUtils.EntityError) {
+		return &domain.SyncJob{ID: 12, UserID: user.ID, Status: domain.SyncJobQueued}, nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/SyncGames", bytes.NewBufferString(`{"userid": 3}`))
+	s.r.ServeHTTP(s.rr, req)
+
+	var job domain.SyncJob
+	err := json.Unmarshal(s.rr.Body.Bytes(), &job)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusAccepted, s.rr.Code)
+	assert.EqualValues(t, "/users/3/sync-jobs/12", s.rr.Header().Get("Location"))
+	assert.EqualValues(t, domain.SyncJobQueued, job.Status)
+	assert.EqualValues(t, 3, job.UserID)
+}
+
+func (s *SyncJobsControllerTestSuite) TestSyncGames_NoSteamId() {
+	s.mockUserService.SetGetUser(func(id uint64) (*domain.User, errorUtils.EntityError) {
+		return &domain.User{ID: id}, nil
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/SyncGames", bytes.NewBufferString(`{"userid": 3}`))
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusNotFound, s.rr.Code)
+}
+
+func (s *SyncJobsControllerTestSuite) TestSyncGames_QueueFull() {
+	s.mockUserService.SetGetUser(func(id uint64) (*domain.User, errorUtils.EntityError) {
+		return &domain.User{ID: id, SteamUserId: "76561198000000000"}, nil
+	})
+	s.mockService.SetEnqueueSync(func(user *domain.User) (*domain.SyncJob, errorUtils.EntityError) {
+		return nil, errorUtils.NewServiceUnavailableError("too many synchronizations are pending, try again later")
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/SyncGames", bytes.NewBufferString(`{"userid": 3}`))
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusServiceUnavailable, s.rr.Code)
+}
+
+func (s *SyncJobsControllerTestSuite) TestGetSyncJob_Success() {
+	s.mockService.SetGetJob(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+		return &domain.SyncJob{ID: jobId, UserID: 3, Status: domain.SyncJobRunning, Total: 10, Inserted: 2, Skipped: 3}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/sync-jobs/12", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	var job domain.SyncJob
+	err := json.Unmarshal(s.rr.Body.Bytes(), &job)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.EqualValues(t, 12, job.ID)
+	assert.EqualValues(t, 5, job.Processed())
+}
+
+func (s *SyncJobsControllerTestSuite) TestGetSyncJob_InvalidId() {
+	req, _ := http.NewRequest(http.MethodGet, "/sync-jobs/abc", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusBadRequest, s.rr.Code)
+}
+
+func (s *SyncJobsControllerTestSuite) TestCancelSyncJob_Success() {
+	s.mockService.SetCancelJob(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+		return &domain.SyncJob{ID: jobId, Status: domain.SyncJobRunning}, nil
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/sync-jobs/12", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusAccepted, s.rr.Code)
+}
+
+func (s *SyncJobsControllerTestSuite) TestCancelSyncJob_AlreadyFinished() {
+	s.mockService.SetCancelJob(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+		return nil, errorUtils.NewConflictError("sync job 12 is already succeeded")
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/sync-jobs/12", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusConflict, s.rr.Code)
+}
+
+func (s *SyncJobsControllerTestSuite) TestGetUserSyncJob() {
+	s.mockService.SetGetJob(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+		return &domain.SyncJob{ID: jobId, UserID: 3, Status: domain.SyncJobRunning}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/users/3/sync-jobs/12", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	var job domain.SyncJob
+	t := s.T()
+	assert.Nil(t, json.Unmarshal(s.rr.Body.Bytes(), &job))
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.EqualValues(t, 12, job.ID)
+}
+
+func (s *SyncJobsControllerTestSuite) TestGetUserSyncJob_OtherUser() {
+	s.mockService.SetGetJob(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+		return &domain.SyncJob{ID: jobId, UserID: 4, Status: domain.SyncJobRunning}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/users/3/sync-jobs/12", nil)
+	s.r.ServeHTTP(s.rr, req)
+
+	apiErr, err := errorUtils.NewApiErrFromBytes(s.rr.Body.Bytes())
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, apiErr.Status())
+	assert.EqualValues(t, "sync job 12 of user 3 not found", apiErr.Message())
+}
+
+func (s *SyncJobsControllerTestSuite) TestCancelUserSyncJob() {
+	s.mockService.SetGetJob(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+		return &domain.SyncJob{ID: jobId, UserID: 3, Status: domain.SyncJobRunning}, nil
+	})
+	cancelled := false
+	s.mockService.SetCancelJob(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+		cancelled = true
+		return &domain.SyncJob{ID: jobId, UserID: 3, Status: domain.SyncJobRunning}, nil
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/users/3/sync-jobs/12", nil)
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(s.T(), http.StatusAccepted, s.rr.Code)
+	assert.True(s.T(), cancelled)
+
+	//the job of another user is left alone
+	cancelled = false
+	rr := httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodDelete, "/users/4/sync-jobs/12", nil)
+	s.r.ServeHTTP(rr, req)
+	assert.EqualValues(s.T(), http.StatusNotFound, rr.Code)
+	assert.False(s.T(), cancelled)
+}
@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"GamesAPI/src/controllers"
 	"GamesAPI/src/domain"
 	"GamesAPI/src/router"
 	"GamesAPI/src/services"
 	"GamesAPI/src/utils/errorUtils"
 	"GamesAPI/tests/unit/mocks"
 	"bytes"
+	"context"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +20,7 @@ import (
 
 type UserControllerTestSuite struct {
 	suite.Suite
+	mockAuditService mocks.AuditServiceMockInterface
 	mockUserRoleService mocks.UserRoleServiceMockInterface
 	mockUserService         mocks.UserServiceMockInterface
 	r                   *gin.Engine
@@ -29,7 +32,17 @@ func TestUsersControllerTestSuite(t *testing.T) {
 }
 
 func (s *UserControllerTestSuite) SetupSuite() {
+	auditMock := &mocks.AuditServiceMock{}
+	auditMock.SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+		return nil
+	})
+	s.mockAuditService = auditMock
+	services.AuditService = auditMock
 	mockUsers := &mocks.UserServiceMock{}
+	//looked up before changes, for the audit log
+	mockUsers.SetGetUser(func(id uint64) (*domain.User, errorUtils.EntityError) {
+		return nil, errorUtils.NewNotFoundError("user not found")
+	})
 	mockUserRoles := &mocks.UserRoleMock{}
 	s.mockUserService = mockUsers
 	s.mockUserRoleService = mockUserRoles
@@ -124,11 +137,16 @@ func (s *UserControllerTestSuite) TestCreateUser_Success() {
 		}, nil
 	})
 
-	s.mockUserRoleService.SetCreateRole(func(role *domain.UserRole) (*domain.UserRole, errorUtils.EntityError) {
+	s.mockUserRoleService.SetPolicyRole(func(name string) (string, errorUtils.EntityError) {
+		return "admin", nil
+	})
+	var assigned string
+	s.mockUserRoleService.SetAssignRole(func(userId uint64, name string) (*domain.UserRole, errorUtils.EntityError) {
+		assigned = name
 		return &domain.UserRole{
 			ID:     1,
-			UserID: 1,
-			Name:   "Admin",
+			UserID: userId,
+			Name:   name,
 		}, nil
 	})
 
@@ -149,6 +167,27 @@ func (s *UserControllerTestSuite) TestCreateUser_Success() {
 	assert.EqualValues(t, uint64(1), user.ID)
 	assert.EqualValues(t, "dev", user.Name)
 	assert.EqualValues(t, "dev@test.com", user.Email)
+	assert.EqualValues(t, "admin", assigned)
+}
+
+func (s *UserControllerTestSuite) TestCreateUser_UnknownRole() {
+	s.mockUserRoleService.SetPolicyRole(func(name string) (string, errorUtils.EntityError) {
+		return "", errorUtils.NewUnprocessableEntityError("role admn is not in the rbac policy, expected one of admin, user")
+	})
+	s.mockUserService.SetCreateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+		assert.Fail(s.T(), "a user with a role missing from the policy should not be created")
+		return user, nil
+	})
+
+	jsonBody := `{"name":"dev", "email":"dev@test.com", "password":"network7", "role":"admn"}`
+	req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(jsonBody))
+	s.r.ServeHTTP(s.rr, req)
+
+	apiErr, err := errorUtils.NewApiErrFromBytes(s.rr.Body.Bytes())
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, apiErr.Status())
+	assert.EqualValues(t, "role admn is not in the rbac policy, expected one of admin, user", apiErr.Message())
 }
 
 func (s *UserControllerTestSuite) TestCreateUser_InvalidJsonBadFieldType() {
@@ -184,7 +223,7 @@ func (s *UserControllerTestSuite) TestCreateUser_InvalidJsonMissingField() {
 }
 
 func (s *UserControllerTestSuite) TestUpdateUser_Success() {
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return &domain.User{
 			ID:    1,
 			Name:  "dev updated",
@@ -210,6 +249,22 @@ func (s *UserControllerTestSuite) TestUpdateUser_Success() {
 	assert.EqualValues(t, "dev.updated@test.com", user.Email)
 }
 
+func (s *UserControllerTestSuite) TestUpdateUser_OnlySentFields() {
+	s.mockUserService.SetGetUser(func(id uint64) (*domain.User, errorUtils.EntityError) {
+		return &domain.User{ID: 1, Name: "dev", Email: "dev@test.com"}, nil
+	})
+	var received []string
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
+		received = fields
+		return &domain.User{ID: 1, Name: user.Name, Email: "dev@test.com"}, nil
+	})
+	req, _ := http.NewRequest(http.MethodPatch, "/users/1", bytes.NewBufferString(`{"name":"dev updated"}`))
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusOK, s.rr.Code)
+	assert.EqualValues(s.T(), []string{"name"}, received)
+}
+
 func (s *UserControllerTestSuite) TestUpdateUser_InvalidId() {
 	userIdParam := "abc"
 	req, _ := http.NewRequest(http.MethodPatch, "/users/"+userIdParam, nil)
@@ -242,7 +297,7 @@ func (s *UserControllerTestSuite) TestUpdateUser_InvalidJson() {
 }
 
 func (s *UserControllerTestSuite) TestUpdateUser_ErrorUpdating() {
-	s.mockUserService.SetUpdateUser(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+	s.mockUserService.SetUpdateUser(func(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
 		return nil, errorUtils.NewInternalServerError("error updating user")
 	})
 
@@ -356,6 +411,35 @@ func (s *UserControllerTestSuite) TestGetAllUsers_Success() {
 	assert.EqualValues(t, users[1].Email, "dev2@test.com")
 }
 
+func (s *UserControllerTestSuite) TestGetAllUsers_VisibleFields() {
+	s.mockUserService.SetGetAll(func() ([]domain.User, errorUtils.EntityError) {
+		return []domain.User{
+			{ID: 1, Name: "dev1", Email: "dev1@test.com", PasswordHash: "hash", SteamUserId: "765"},
+			{ID: 2, Name: "dev2", Email: "dev2@test.com", PasswordHash: "hash", SteamUserId: "766"},
+		}, nil
+	})
+	//as the authorization layer would, user 2 being granted access by a role restricting the visible fields
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), domain.RbacUserId(), uint64(2))
+		decision := &domain.RbacDecision{Role: "user", Fields: &domain.RbacFields{
+			Visible: []string{"id", "name"}, Owner: "id", Own: []string{"email"},
+		}}
+		c.Request = c.Request.WithContext(context.WithValue(ctx, domain.RbacDecisionKey(), decision))
+	})
+	router.InitGetAllUsersRoute(r.Group("/users"), controllers.GetAllUsers)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users", nil)
+	r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, http.StatusOK, s.rr.Code)
+	assert.JSONEq(t, `[
+		{"id": 1, "name": "dev1"},
+		{"id": 2, "name": "dev2", "email": "dev2@test.com"}
+	]`, s.rr.Body.String())
+}
+
 func (s *UserControllerTestSuite) TestGetAllUsers_Failure() {
 	s.mockUserService.SetGetAll(func() ([]domain.User, errorUtils.EntityError) {
 		return nil, errorUtils.NewInternalServerError("error getting users")
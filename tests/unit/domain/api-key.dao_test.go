@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type ApiKeyDBTestSuite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository domain.ApiKeyRepoInterface
+	dsnCount   int64
+}
+
+func (s *ApiKeyDBTestSuite) BeforeTest(_, _ string) {
+	var (
+		err error
+	)
+	s.dsnCount++
+	dsn := fmt.Sprintf("sqlmock_db_apiKey_%d", s.dsnCount)
+	_, s.mock, err = sqlmock.NewWithDSN(dsn)
+	require.NoError(s.T(), err)
+
+	s.DB, err = gorm.Open("sqlmock", dsn)
+	require.NoError(s.T(), err)
+
+	s.DB.LogMode(true)
+
+	s.repository = domain.NewApiKeyRepository(s.DB)
+}
+
+func (s *ApiKeyDBTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func TestApiKeyDBTestSuite(t *testing.T) {
+	suite.Run(t, new(ApiKeyDBTestSuite))
+}
+
+func (s *ApiKeyDBTestSuite) TestRepo_GetByPrefix() {
+	rows := sqlmock.NewRows([]string{"id", "owner_id", "label", "prefix", "secret_hash", "scopes", "revoked"}).
+		AddRow(1, 42, "mobile app", "0a1b2c3d4e5f", "hash", "games,library", false)
+	s.mock.ExpectQuery(`SELECT \* FROM "api_keys" WHERE \(prefix = \?\)`).
+		WithArgs("0a1b2c3d4e5f").
+		WillReturnRows(rows)
+
+	key, err := s.repository.GetByPrefix("0a1b2c3d4e5f")
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 42, key.OwnerId)
+	assert.EqualValues(s.T(), domain.ApiScopes{"games", "library"}, key.Scopes)
+}
+
+func (s *ApiKeyDBTestSuite) TestRepo_GetByPrefix_NotFound() {
+	s.mock.ExpectQuery(`SELECT \* FROM "api_keys" WHERE \(prefix = \?\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.repository.GetByPrefix("0a1b2c3d4e5f")
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusNotFound, err.Status())
+}
+
+func (s *ApiKeyDBTestSuite) TestRepo_Revoke() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`UPDATE "api_keys" SET (.+) WHERE \(id = \?\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	assert.Nil(s.T(), s.repository.Revoke(1, time.Now()))
+}
+
+func (s *ApiKeyDBTestSuite) TestRepo_Revoke_NotFound() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`UPDATE "api_keys"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	err := s.repository.Revoke(1, time.Now())
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusNotFound, err.Status())
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository domain.AuditRepoInterface
+	dsnCount   int64
+}
+
+func (s *AuditTestSuite) BeforeTest(_, _ string) {
+	var (
+		err error
+	)
+	s.dsnCount++
+	dsn := fmt.Sprintf("sqlmock_db_audit_%d", s.dsnCount)
+	_, s.mock, err = sqlmock.NewWithDSN(dsn)
+	require.NoError(s.T(), err)
+
+	s.DB, err = gorm.Open("sqlmock", dsn)
+	require.NoError(s.T(), err)
+
+	s.DB.LogMode(true)
+
+	s.repository = domain.NewAuditRepository(s.DB)
+}
+
+func (s *AuditTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
+
+func (s *AuditTestSuite) TestAuditRepo_Append() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`INSERT INTO "audit_entries"`).WillReturnResult(sqlmock.NewResult(7, 1))
+	s.mock.ExpectCommit()
+
+	entry, err := s.repository.Append(&domain.AuditEntry{
+		ActorId:  1,
+		Resource: "game",
+		Action:   "delete",
+		EntityId: 12,
+		Changes:  domain.AuditChanges{"title": {Before: "Portal"}},
+	})
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 7, entry.ID)
+	assert.Nil(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *AuditTestSuite) TestAuditRepo_Append_Existing() {
+	entry, err := s.repository.Append(&domain.AuditEntry{ID: 7, Resource: "game", Action: "delete"})
+	assert.Nil(s.T(), entry)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusConflict, err.Status())
+}
+
+func (s *AuditTestSuite) TestAuditRepo_Find() {
+	s.mock.ExpectQuery(`SELECT count\(\*\) FROM "audit_entries" WHERE \(actor_id = \?\) AND \(resource = \?\) AND \(id < \?\)`).
+		WithArgs(1, "game", 100).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	s.mock.ExpectQuery(`SELECT \* FROM "audit_entries" WHERE \(actor_id = \?\) AND \(resource = \?\) AND \(id < \?\) ORDER BY id desc LIMIT 2 OFFSET 2`).
+		WithArgs(1, "game", 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "actor_id", "resource", "action", "changes"}).
+			AddRow(5, 1, "game", "update", `{"title":{"before":"Portal","after":"Portal 2"}}`))
+
+	query := domain.NewAuditQuery()
+	query.ActorId, query.Resource, query.BeforeId = 1, "game", 100
+	query.Page, query.PageSize = 2, 2
+	entries, total, err := s.repository.Find(query)
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 3, total)
+	require.Len(s.T(), entries, 1)
+	assert.EqualValues(s.T(), "update", entries[0].Action)
+	assert.EqualValues(s.T(), domain.AuditChange{Before: "Portal", After: "Portal 2"}, entries[0].Changes["title"])
+}
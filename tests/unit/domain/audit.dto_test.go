@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAuditDiff_Update(t *testing.T) {
+	before := &domain.User{ID: 3, Name: "dev", Email: "dev@test.com", PasswordHash: "old", UpdatedAt: time.Now()}
+	after := &domain.User{ID: 3, Name: "dev2", Email: "dev@test.com", PasswordHash: "new", UpdatedAt: time.Now().Add(time.Second)}
+
+	changes, err := domain.AuditDiff(before, after)
+	require.Nil(t, err)
+	assert.EqualValues(t, domain.AuditChanges{
+		"name":          {Before: "dev", After: "dev2"},
+		"password_hash": {Before: "[redacted]", After: "[redacted]"},
+	}, changes)
+}
+
+func TestAuditDiff_CreateAndDelete(t *testing.T) {
+	role := &domain.UserRole{ID: 4, UserID: 3, Name: "admin"}
+	var missing *domain.UserRole
+
+	created, err := domain.AuditDiff(missing, role)
+	require.Nil(t, err)
+	assert.EqualValues(t, domain.AuditChange{After: "admin"}, created["name"])
+	assert.EqualValues(t, domain.AuditChange{After: json.Number("3")}, created["user_id"])
+
+	deleted, err := domain.AuditDiff(role, nil)
+	require.Nil(t, err)
+	assert.EqualValues(t, domain.AuditChange{Before: "admin"}, deleted["name"])
+	assert.Len(t, deleted, len(created))
+}
+
+func TestAuditDiff_NotAnObject(t *testing.T) {
+	_, err := domain.AuditDiff(nil, []string{"admin"})
+	assert.NotNil(t, err)
+}
+
+func TestAuditChanges_ValueScan(t *testing.T) {
+	changes := domain.AuditChanges{"title": {Before: "Portal", After: "Portal 2"}}
+	value, err := changes.Value()
+	require.Nil(t, err)
+
+	var scanned domain.AuditChanges
+	require.Nil(t, scanned.Scan([]byte(value.(string))))
+	assert.EqualValues(t, changes, scanned)
+
+	require.Nil(t, scanned.Scan(nil))
+	assert.Empty(t, scanned)
+}
+
+func TestAuditQuery_Validate(t *testing.T) {
+	since := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	until := since.Add(-time.Hour)
+	tests := []struct {
+		given   string
+		query   domain.AuditQuery
+		wantErr string
+	}{
+		{given: "The default query", query: *domain.NewAuditQuery()},
+		{given: "No page", query: domain.AuditQuery{PageSize: 10}, wantErr: "page should be greater than 0"},
+		{given: "Too large a page", query: domain.AuditQuery{Page: 1, PageSize: 501}, wantErr: "page_size should be between 1 and 500"},
+		{given: "Since later than until", query: domain.AuditQuery{Page: 1, PageSize: 10, Since: &since, Until: &until}, wantErr: "since cannot be later than until"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			err := tt.query.Validate()
+			if tt.wantErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.EqualValues(t, tt.wantErr, err.Message())
+		})
+	}
+}
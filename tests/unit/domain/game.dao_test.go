@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type GameTestSuite struct {
@@ -51,28 +52,34 @@ func TestGamesTestSuite(t *testing.T) {
 func (s *GameTestSuite) TestGameRepo_GetAll_Empty() {
 
 	//Test for getting all games from empty table.
+	s.mock.ExpectQuery(`SELECT count\(\*\) FROM "games"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 	const sqlSelectAll = `SELECT (.+) FROM "games"`
 	s.mock.ExpectQuery(sqlSelectAll).
 		WillReturnRows(sqlmock.NewRows(nil))
 
-	data, err := s.repository.GetAll()
+	data, total, err := s.repository.GetAll(domain.NewGameQuery())
 	if err != nil {
 		assert.Fail(s.T(), "An error occurred during repo.GetAll")
 	}
 
 	assert.Equal(s.T(), []domain.Game{}, data) //result should be an empty slice of games.
+	assert.EqualValues(s.T(), 0, total)
 }
 
 func (s *GameTestSuite) TestGameRepo_GetAll_NotEmpty() {
 	rows := sqlmock.NewRows([]string{"id", "title", "developer", "publisher", "releaseDate"}).
 		AddRow(1, "Rocket League", "Psyonix", "Psyonix", utils.GetDate("2015-07-07")).
 		AddRow(2, "The Witcher 3: Wild Hunt", "CD PROJEKT RED", "CD PROJEKT RED", utils.GetDate("2015-05-18"))
+	s.mock.ExpectQuery(`SELECT count\(\*\) FROM "games"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 	s.mock.ExpectQuery(`SELECT (.+) FROM "games"`).
 		WillReturnRows(rows)
 
-	data, err := s.repository.GetAll()
+	data, total, err := s.repository.GetAll(domain.NewGameQuery())
 	assert.Nil(s.T(), err)
 	assert.NotNil(s.T(), data)
+	assert.EqualValues(s.T(), 2, total)
 	expected := []domain.Game{
 		{
 			ID:          1,
@@ -94,6 +101,58 @@ func (s *GameTestSuite) TestGameRepo_GetAll_NotEmpty() {
 	assert.Equal(s.T(), expected[1], data[1])
 }
 
+func (s *GameTestSuite) TestGameRepo_GetAll_Filtered() {
+	query := domain.NewGameQuery()
+	query.Page = 3
+	query.PageSize = 10
+	query.SteamId = "252950"
+	query.Developer = "Psyonix"
+	query.Sort = "title"
+	query.Descending = true
+
+	s.mock.ExpectQuery(`SELECT count\(\*\) FROM "games" WHERE (.+)developer LIKE(.+)steam_id = `).
+		WithArgs("%Psyonix%", "252950").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+	s.mock.ExpectQuery(`SELECT (.+) FROM "games" WHERE (.+) ORDER BY title desc, id asc LIMIT 10 OFFSET 20`).
+		WithArgs("%Psyonix%", "252950").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "steam_id"}).AddRow(21, "Rocket League", "252950"))
+
+	data, total, err := s.repository.GetAll(query)
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 21, total)
+	assert.Len(s.T(), data, 1)
+	assert.Nil(s.T(), s.mock.ExpectationsWereMet())
+}
+
+//wildcards typed by the client are matched as is, and released_before includes the whole day
+func (s *GameTestSuite) TestGameRepo_GetAll_EscapedFilters() {
+	query := domain.NewGameQuery()
+	query.Publisher = `100%_[Co]\`
+	releasedBefore := time.Date(2020, 12, 3, 0, 0, 0, 0, time.UTC)
+	query.ReleasedBefore = &releasedBefore
+
+	s.mock.ExpectQuery(`SELECT count\(\*\) FROM "games" WHERE (.+)publisher LIKE \? ESCAPE '\\'(.+)releaseDate < \?`).
+		WithArgs(`%100\%\_\[Co]\\%`, time.Date(2020, 12, 4, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectQuery(`SELECT (.+) FROM "games"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, total, err := s.repository.GetAll(query)
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 0, total)
+	assert.Nil(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *GameTestSuite) TestGameRepo_GetAll_CountFails() {
+	s.mock.ExpectQuery(`SELECT count\(\*\) FROM "games"`).
+		WillReturnError(errorUtils.NewInternalServerError("server_error"))
+
+	data, total, err := s.repository.GetAll(domain.NewGameQuery())
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), data)
+	assert.EqualValues(s.T(), 0, total)
+}
+
 //Test for getting a single game from empty table.
 func (s *GameTestSuite) TestGameRepo_Get_Empty() {
 	rows := sqlmock.NewRows(nil)
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRateLimitsFromFile(t *testing.T) {
+	limits, err := domain.RateLimitsFromFile("../resources/rate-limits-test.yml")
+	require.Nil(t, err)
+
+	external, exists := limits.For("external")
+	assert.True(t, exists)
+	assert.EqualValues(t, domain.RateLimit{Requests: 2, Period: time.Minute, Burst: 3}, external)
+	assert.EqualValues(t, 3, external.Capacity())
+
+	//groups without a limit of their own use the default one
+	auth, exists := limits.For("auth")
+	assert.True(t, exists)
+	assert.EqualValues(t, 100, auth.Capacity())
+}
+
+func TestRateLimits_NoDefault(t *testing.T) {
+	limits := domain.RateLimits{"external": {Requests: 1, Period: time.Second}}
+	_, exists := limits.For("auth")
+	assert.False(t, exists)
+}
+
+func TestRateLimitsFromFile_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"default:\n  requests: 0\n  period: 1m\n",
+		"default:\n  requests: 10\n",
+		"default:\n  requests: 10\n  period: soon\n",
+		"default:\n  requests: 10\n  period: 1m\n  burst: -1\n",
+	} {
+		f, err := ioutil.TempFile("", "rate-limits-*.yml")
+		require.Nil(t, err)
+		_, _ = f.WriteString(content)
+		_ = f.Close()
+
+		_, err = domain.RateLimitsFromFile(f.Name())
+		assert.NotNil(t, err, content)
+		_ = os.Remove(f.Name())
+	}
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseRoleStrategy(t *testing.T) {
+	strategy, err := domain.ParseRoleStrategy("")
+	assert.Nil(t, err)
+	assert.EqualValues(t, domain.RoleStrategyAnyAllow, strategy)
+
+	strategy, err = domain.ParseRoleStrategy("deny-overrides")
+	assert.Nil(t, err)
+	assert.EqualValues(t, domain.RoleStrategyDenyOverrides, strategy)
+
+	_, err = domain.ParseRoleStrategy("first-role")
+	assert.EqualError(t, err, "unknown role strategy 'first-role', expected any-allow or deny-overrides")
+}
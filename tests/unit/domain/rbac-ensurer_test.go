@@ -3,9 +3,11 @@ package domain
 import (
 	"GamesAPI/src/domain"
 	"context"
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"net/http"
+	"strconv"
 	"testing"
 )
 
@@ -47,6 +49,35 @@ func (s *ensurerEnforcerTestSuite) TestEnsurer_QueryComplies() {
 			// we give the context.name = "John"
 			return context.WithValue(context.Background(), domain.ContextKey("name"), "John")
 		},
+	}, {
+		given: "Path: /users/3 without query and Rule: userId=ctx.userId",
+		then:  "QueryComplies must return error, the last path segment is not a query parameter",
+		args: args{
+			url: "http://api.example.com/users/3",
+		},
+		ensurer: domain.Ensurer{
+			Query: []domain.Rule{
+				{Key: "userId", Operator: "=", Value: "ctx.userId"},
+			},
+		},
+		context: func() context.Context {
+			return context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+		},
+		wantErr: true,
+	}, {
+		given: "Query: page=2 and Rule: page exists",
+		then:  "QueryComplies must not return error",
+		args: args{
+			url: "http://api.example.com/games?page=2",
+		},
+		ensurer: domain.Ensurer{
+			Query: []domain.Rule{
+				{Key: "page", Operator: "exists"},
+			},
+		},
+		context: func() context.Context {
+			return context.Background()
+		},
 	}}
 	for _, tt := range tests {
 		s.T().Run(tt.given, func(t *testing.T) {
@@ -63,6 +94,47 @@ func (s *ensurerEnforcerTestSuite) TestEnsurer_QueryComplies() {
 	}
 }
 
+func (s *ensurerEnforcerTestSuite) TestEnsurer_PathComplies() {
+	ensurer := domain.Ensurer{
+		Path: []domain.Rule{
+			{Key: "id", Operator: "=", Value: "ctx.userId"},
+		},
+	}
+	tests := []struct {
+		given   string
+		params  map[string]string
+		wantErr bool
+	}{{
+		given:  "Route param id matches ctx.userId",
+		params: map[string]string{"id": "3"},
+	}, {
+		given:   "Route param id does not match ctx.userId",
+		params:  map[string]string{"id": "4"},
+		wantErr: true,
+	}, {
+		given:   "Route param id is not a number",
+		params:  map[string]string{"id": "me"},
+		wantErr: true,
+	}, {
+		given:   "Route has no id param",
+		params:  map[string]string{},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		s.T().Run(tt.given, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+			ctx = context.WithValue(ctx, domain.RbacRouteParams(), tt.params)
+
+			err := ensurer.PathComplies(ctx)
+			if tt.wantErr {
+				assert.Error(t, err, tt.given)
+			} else {
+				assert.NoError(t, err, tt.given)
+			}
+		})
+	}
+}
+
 func (s *ensurerEnforcerTestSuite) TestEnforcer_QueryComplies() {
 	type args struct {
 		method string
@@ -116,3 +188,160 @@ func (s *ensurerEnforcerTestSuite) TestEnforcer_QueryComplies() {
 		})
 	}
 }
+
+func (s *ensurerEnforcerTestSuite) TestEnsurer_HeaderComplies() {
+	ensurer := domain.Ensurer{
+		Header: []domain.Rule{
+			{Key: "X-User-Id", Operator: "=", Value: "ctx.userId"},
+		},
+	}
+	tests := []struct {
+		given   string
+		headers http.Header
+		wantErr bool
+	}{{
+		given:   "Header matches ctx.userId, whatever its case",
+		headers: http.Header{"X-User-Id": {"3"}},
+	}, {
+		given:   "Header does not match ctx.userId",
+		headers: http.Header{"X-User-Id": {"4"}},
+		wantErr: true,
+	}, {
+		given:   "Header is missing",
+		headers: http.Header{},
+		wantErr: true,
+	}, {
+		given:   "No headers in context",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		s.T().Run(tt.given, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+			if tt.headers != nil {
+				ctx = context.WithValue(ctx, domain.RbacRequestHeaders(), tt.headers)
+			}
+
+			err := ensurer.HeaderComplies(ctx)
+			if tt.wantErr {
+				assert.Error(t, err, tt.given)
+			} else {
+				assert.NoError(t, err, tt.given)
+			}
+		})
+	}
+}
+
+func (s *ensurerEnforcerTestSuite) TestEnforcer_HeaderComplies() {
+	enforcer := domain.Enforcer{
+		Header: []domain.Rule{
+			{Key: "x-tenant", Value: "games"},
+		},
+	}
+	headers := http.Header{"X-Tenant": {"other"}}
+	ctx := context.WithValue(context.Background(), domain.RbacRequestHeaders(), headers)
+
+	err := enforcer.HeaderComplies(ctx)
+	assert.NoError(s.T(), err)
+	assert.EqualValues(s.T(), []string{"games"}, headers["X-Tenant"])
+
+	err = enforcer.HeaderComplies(context.Background())
+	assert.Error(s.T(), err)
+}
+
+func (s *ensurerEnforcerTestSuite) TestEnsurer_BodyComplies() {
+	ensurer := domain.Ensurer{
+		Body: []domain.Rule{
+			{Key: "userid", Operator: "=", Value: "ctx.userId"},
+		},
+	}
+	tests := []struct {
+		given   string
+		body    string
+		wantErr bool
+	}{{
+		given: "Body userid matches ctx.userId",
+		body:  `{"userid": 3, "profileUrl": "https://steamcommunity.com/id/someone"}`,
+	}, {
+		given: "Body userid is a string matching ctx.userId",
+		body:  `{"userid": "3"}`,
+	}, {
+		given:   "Body userid does not match ctx.userId",
+		body:    `{"userid": 4}`,
+		wantErr: true,
+	}, {
+		given:   "Body has no userid",
+		body:    `{"profileUrl": "https://steamcommunity.com/id/someone"}`,
+		wantErr: true,
+	}, {
+		given: "Body userid is bound whatever its case",
+		body:  `{"UserId": 3}`,
+	}, {
+		//the handlers bind the last of the case variants, 5 here
+		given:   "Body has a case variant of userid after it",
+		body:    `{"userid": 3, "USERID": 5}`,
+		wantErr: true,
+	}, {
+		given: "Body has a case variant of userid before it",
+		body:  `{"USERID": 5, "userid": 3}`,
+	}, {
+		given:   "Body is not JSON",
+		body:    `userid=3`,
+		wantErr: true,
+	}, {
+		given:   "Request has no body",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		s.T().Run(tt.given, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+			ctx = context.WithValue(ctx, domain.RbacRequestBody(), []byte(tt.body))
+
+			err := ensurer.BodyComplies(ctx)
+			if tt.wantErr {
+				assert.Error(t, err, tt.given)
+			} else {
+				assert.NoError(t, err, tt.given)
+			}
+		})
+	}
+}
+
+//the rules have to check the value the handlers bind, not another field of the body
+func (s *ensurerEnforcerTestSuite) TestEnsurer_BodyComplies_SameFieldAsHandlers() {
+	bodies := []string{
+		`{"userid": 3, "USERID": 5}`,
+		`{"USERID": 5, "userid": 3}`,
+		`{"userId": 5, "userid": 3, "UserID": 7}`,
+		`{"Userid": 5}`,
+	}
+	for _, body := range bodies {
+		var bound struct {
+			Userid uint64 `json:"userid"`
+		}
+		assert.NoError(s.T(), json.Unmarshal([]byte(body), &bound), body)
+		ensurer := domain.Ensurer{
+			Body: []domain.Rule{{Key: "userid", Operator: "=", Value: strconv.FormatUint(bound.Userid, 10)}},
+		}
+		ctx := context.WithValue(context.Background(), domain.RbacRequestBody(), []byte(body))
+		assert.NoError(s.T(), ensurer.BodyComplies(ctx), body)
+	}
+}
+
+func (s *ensurerEnforcerTestSuite) TestEnsurer_BodyComplies_NestedField() {
+	ensurer := domain.Ensurer{
+		Body: []domain.Rule{
+			{Key: "owner.id", Operator: "=", Value: "ctx.userId"},
+		},
+	}
+	ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(18446744073709551615))
+
+	body := context.WithValue(ctx, domain.RbacRequestBody(), []byte(`{"owner": {"id": 18446744073709551615}}`))
+	assert.NoError(s.T(), ensurer.BodyComplies(body))
+
+	//a float64 would have rounded this one to the same value
+	body = context.WithValue(ctx, domain.RbacRequestBody(), []byte(`{"owner": {"id": 18446744073709551614}}`))
+	assert.Error(s.T(), ensurer.BodyComplies(body))
+
+	body = context.WithValue(ctx, domain.RbacRequestBody(), []byte(`{"owner": 3}`))
+	assert.Error(s.T(), ensurer.BodyComplies(body))
+}
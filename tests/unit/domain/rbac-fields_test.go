@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var testUserFields = &domain.RbacFields{
+	Visible: []string{"id", "name"},
+	Owner:   "id",
+	Own:     []string{"email"},
+}
+
+func TestRbacFields_Project(t *testing.T) {
+	ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+	users := []domain.User{
+		{ID: 3, Name: "me", Email: "me@example.com", PasswordHash: "hash"},
+		{ID: 12345678901234, Name: "other", Email: "other@example.com", PasswordHash: "hash"},
+	}
+
+	projected, err := testUserFields.Project(ctx, users)
+	require.Nil(t, err)
+	raw, _ := json.Marshal(projected)
+	assert.JSONEq(t, `[
+		{"id": 3, "name": "me", "email": "me@example.com"},
+		{"id": 12345678901234, "name": "other"}
+	]`, string(raw))
+
+	projected, err = testUserFields.Project(ctx, &users[1])
+	require.Nil(t, err)
+	raw, _ = json.Marshal(projected)
+	assert.JSONEq(t, `{"id": 12345678901234, "name": "other"}`, string(raw))
+}
+
+func TestRbacFields_Project_Unrestricted(t *testing.T) {
+	user := &domain.User{ID: 3, PasswordHash: "hash"}
+	for _, fields := range []*domain.RbacFields{nil, {Writable: []string{"name"}}, {Visible: []string{}}} {
+		projected, err := fields.Project(context.Background(), user)
+		assert.Nil(t, err)
+		assert.Same(t, user, projected)
+	}
+}
+
+func TestRbacFields_WritableComplies(t *testing.T) {
+	fields := &domain.RbacFields{Writable: []string{"name", "email"}}
+	tests := []struct {
+		given   string
+		body    string
+		wantErr string
+	}{{
+		given: "Writable fields only",
+		body:  `{"name": "me", "email": "me@example.com"}`,
+	}, {
+		given: "No body",
+		body:  "",
+	}, {
+		given:   "A field that is not writable",
+		body:    `{"name": "me", "password_hash": "x", "id": 1}`,
+		wantErr: "field rule violation: 'id' is not writable, expected one of [name, email]",
+	}, {
+		given:   "A body that is not an object",
+		body:    `["name"]`,
+		wantErr: "field rule violation: request body should be a JSON object",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), domain.RbacRequestBody(), []byte(tt.body))
+			err := fields.WritableComplies(ctx)
+			if tt.wantErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.EqualValues(t, tt.wantErr, err.Error())
+		})
+	}
+
+	var unrestricted *domain.RbacFields
+	ctx := context.WithValue(context.Background(), domain.RbacRequestBody(), []byte(`{"password_hash": "x"}`))
+	assert.Nil(t, unrestricted.WritableComplies(ctx))
+}
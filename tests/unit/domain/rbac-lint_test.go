@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var testRbacSurface = &domain.RbacSurface{
+	Resources: map[string]map[string]bool{
+		"sync_games": {"create": true},
+		"library":    {"read": true, "delete": true},
+	},
+	ContextKeys: map[string]bool{"userId": true},
+}
+
+func lint(t *testing.T, policy string, surface *domain.RbacSurface) []string {
+	rbac, err := domain.ParseRbac([]byte(policy))
+	require.Nil(t, err)
+	var issues []string
+	for _, issue := range rbac.Lint(surface) {
+		issues = append(issues, issue.Severity+" "+issue.String())
+	}
+	return issues
+}
+
+func TestRbacLint_Clean(t *testing.T) {
+	issues := lint(t, `
+user:
+  sync_games:
+    create:
+      allow: false
+  library:
+    read:
+      allow: true
+      ensure:
+        path:
+          - key: id
+            operator: "="
+            value: ctx.userId
+    delete:
+      allow: false
+`, testRbacSurface)
+	assert.Empty(t, issues)
+}
+
+func TestRbacLint_UnknownResource(t *testing.T) {
+	issues := lint(t, `
+user:
+  sync_gamees:
+    create:
+      allow: false
+  library:
+    read:
+      allow: true
+    delete:
+      allow: true
+`, testRbacSurface)
+	assert.EqualValues(t, []string{
+		"error user.sync_gamees: resource is not exposed by any route, did you mean sync_games?",
+		"warning user.sync_games.create: missing, requests are denied",
+	}, issues)
+}
+
+func TestRbacLint_Unreachable(t *testing.T) {
+	issues := lint(t, `
+user:
+  sync_games:
+    create:
+      allow: true
+    read:
+      allow: true
+  library:
+    read:
+      allow: true
+    delete:
+      allow: true
+`, testRbacSurface)
+	assert.EqualValues(t, []string{"warning user.sync_games.read: unreachable, no route of sync_games is a read"}, issues)
+}
+
+func TestRbacLint_Rules(t *testing.T) {
+	issues := lint(t, `
+user:
+  sync_games:
+    create:
+      allow: true
+      ensure:
+        query:
+          - key: userId
+            operator: "=="
+            value: ctx.userId
+          - key: steamId
+            operator: "="
+            value: ctxuserId
+        header:
+          - key: x-user
+            operator: "="
+            value: ctx.email
+  library:
+    read:
+      allow: false
+      ensure:
+        path:
+          - key: id
+            operator: "="
+            value: ctx.
+    delete:
+      allow: true
+      enforce:
+        path:
+          - key: id
+            value: ctx.userId
+`, testRbacSurface)
+	assert.EqualValues(t, []string{
+		"error user.library.read: ensure path rule on id has malformed context reference 'ctx.', expected ctx.<name>",
+		"error user.sync_games.create: ensure header rule on x-user refers to ctx.email, which is never in the context",
+		"error user.sync_games.create: ensure query rule on steamId has malformed context reference 'ctxuserId', expected ctx.<name>",
+		"error user.sync_games.create: ensure query rule on userId has unknown operator '=='",
+		"warning user.library.delete: route parameters cannot be enforced, enforce path rules are ignored",
+		"warning user.library.read: ensure rules of a denied permission are never checked",
+	}, issues)
+}
+
+func TestRbacLint_WithoutSurface(t *testing.T) {
+	//without the routes, resources and context values are not checked
+	issues := lint(t, `
+client:
+  inquiry:
+    get:
+      allow: true
+      ensure:
+        query:
+          - key: created_by
+            operator: "="
+            value: ctx.email
+`, nil)
+	assert.Empty(t, issues)
+}
+
+func TestRbacLint_Values(t *testing.T) {
+	issues := lint(t, `
+user:
+  library:
+    read:
+      allow: true
+      ensure:
+        query:
+          - key: platform
+            operator: "="
+            value: [steam, gog]
+          - key: id
+            operator: in
+            value: "3"
+          - key: name
+            operator: regex
+            value: "(["
+          - key: page
+            operator: "<"
+            value: ten
+          - key: status
+            operator: exists
+            value: "yes"
+          - key: role
+            operator: in
+            value: ctx.userRoles
+      enforce:
+        query:
+          - key: status
+            value: [a, b]
+`, nil)
+	assert.EqualValues(t, []string{
+		"error user.library.read: enforce query rule on status sets a single value, not a list",
+		"error user.library.read: ensure query rule on id should have a list of values for in",
+		"error user.library.read: ensure query rule on name has an invalid regex '([': error parsing regexp: missing closing ]: `[`",
+		"error user.library.read: ensure query rule on page should have a number for <, cannot compare 'ten' as a number",
+		"error user.library.read: ensure query rule on platform has a list of values, only in and not in take one",
+		"warning user.library.read: ensure query rule on status checks that the value exists, its value is ignored",
+	}, issues)
+}
+
+func TestRbacLint_InheritanceAndWildcards(t *testing.T) {
+	issues := lint(t, `
+user:
+  sync_games:
+    create:
+      allow: true
+      ensure:
+        body:
+          - key: userid
+            operator: "=="
+            value: ctx.userId
+  library:
+    delete:
+      allow: false
+moderator:
+  inherits: [user]
+  library:
+    "*":
+      allow: true
+admin:
+  "*":
+    "*":
+      allow: true
+`, testRbacSurface)
+	assert.EqualValues(t, []string{
+		"error user.sync_games.create: ensure body rule on userid has unknown operator '=='",
+		"warning moderator.library.delete: inherited from user, takes precedence over moderator.library.*",
+		"warning user.library.read: missing, requests are denied",
+	}, issues)
+}
+
+func TestRbacLint_Fields(t *testing.T) {
+	issues := lint(t, `
+user:
+  library:
+    read:
+      allow: true
+      fields:
+        own: [email]
+    delete:
+      allow: true
+      fields:
+        visible: [id, ""]
+        owner: user_id
+`, nil)
+	assert.EqualValues(t, []string{
+		"error user.library.delete: visible fields have an empty name",
+		"error user.library.read: own fields need an owner field to tell the user's records apart",
+		"warning user.library.delete: owner field user_id has no own fields, it is ignored",
+		"warning user.library.read: own fields are ignored, every field is visible without a visible list",
+	}, issues)
+}
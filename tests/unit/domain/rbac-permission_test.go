@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRbacFromBytes_Valid(t *testing.T) {
+	rbac, err := domain.RbacFromBytes([]byte(`
+user:
+  library:
+    read:
+      allow: true
+      ensure:
+        path:
+          - key: id
+            operator: "="
+            value: ctx.userId
+      enforce:
+        query:
+          - key: status
+            value: New
+`))
+	assert.Nil(t, err)
+	assert.True(t, rbac["user"]["library"]["read"].Allow)
+}
+
+func TestRbacFromBytes_Invalid(t *testing.T) {
+	for name, policy := range map[string]string{
+		"empty":            ``,
+		"role without any": "user:\n",
+		"unknown field":    "user:\n  game:\n    read:\n      alow: true\n",
+		"unknown operator": "user:\n  game:\n    read:\n      allow: true\n      ensure:\n        query:\n          - key: id\n            operator: \"==\"\n            value: ctx.userId\n",
+		"rule without key": "user:\n  game:\n    read:\n      allow: true\n      ensure:\n        path:\n          - operator: \"=\"\n            value: ctx.userId\n",
+		"not a policy":     "user: [a, b]\n",
+	} {
+		_, err := domain.RbacFromBytes([]byte(policy))
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestParseRbac_Inheritance(t *testing.T) {
+	rbac, err := domain.ParseRbac([]byte(`
+user:
+  game:
+    read:
+      allow: true
+    delete:
+      allow: false
+  library:
+    read:
+      allow: true
+support:
+  library:
+    read:
+      allow: false
+    delete:
+      allow: true
+moderator:
+  inherits: [support, user]
+  game:
+    delete:
+      allow: true
+lead:
+  inherits: [moderator]
+`))
+	require.Nil(t, err)
+
+	//own permissions override inherited ones
+	assert.True(t, rbac["moderator"]["game"]["delete"].Allow)
+	assert.EqualValues(t, "moderator", rbac["moderator"]["game"]["delete"].DeclaredBy)
+	assert.True(t, rbac["moderator"]["game"]["read"].Allow)
+	assert.EqualValues(t, "user", rbac["moderator"]["game"]["read"].DeclaredBy)
+	//then the roles listed first
+	assert.False(t, rbac["moderator"]["library"]["read"].Allow)
+	assert.EqualValues(t, "support", rbac["moderator"]["library"]["read"].DeclaredBy)
+	assert.True(t, rbac["moderator"]["library"]["delete"].Allow)
+	//through several levels
+	assert.True(t, rbac["lead"]["game"]["delete"].Allow)
+	assert.EqualValues(t, "moderator", rbac["lead"]["game"]["delete"].DeclaredBy)
+	assert.True(t, rbac["lead"]["game"]["read"].Allow)
+	//the inherited roles are left as they are
+	_, exists := rbac["user"]["game"]["delete"]
+	assert.True(t, exists)
+	_, exists = rbac["support"]["game"]
+	assert.False(t, exists)
+}
+
+func TestParseRbac_InheritanceErrors(t *testing.T) {
+	for policy, want := range map[string]string{
+		"user:\n  inherits: [user]\n": "role user inherits itself: user > user",
+		"admin:\n  inherits: [moderator]\nmoderator:\n  inherits: [user]\nuser:\n  inherits: [admin]\n": "role admin inherits itself: admin > moderator > user > admin",
+		"moderator:\n  inherits: [usr]\n  game:\n    read:\n      allow: true\n":                        "role moderator inherits unknown role 'usr'",
+	} {
+		_, err := domain.ParseRbac([]byte(policy))
+		assert.EqualError(t, err, want)
+	}
+}
+
+func TestRbac_PermissionWildcards(t *testing.T) {
+	rbac, err := domain.RbacFromBytes([]byte(`
+admin:
+  "*":
+    "*":
+      allow: true
+    delete:
+      allow: false
+  game:
+    "*":
+      allow: false
+    read:
+      allow: true
+`))
+	require.Nil(t, err)
+
+	for _, tt := range []struct {
+		resource, endpoint string
+		allow              bool
+	}{
+		{"game", "read", true},
+		{"game", "update", false},
+		{"game", "delete", false},
+		{"library", "delete", false},
+		{"library", "read", true},
+	} {
+		permission, exists := rbac.Permission("admin", tt.resource, tt.endpoint)
+		assert.True(t, exists, tt.resource+"."+tt.endpoint)
+		assert.EqualValues(t, tt.allow, permission.Allow, tt.resource+"."+tt.endpoint)
+	}
+	_, exists := rbac.Permission("user", "game", "read")
+	assert.False(t, exists)
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type RbacPolicyDBTestSuite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository domain.RbacPolicyRepoInterface
+	dsnCount   int64
+}
+
+func (s *RbacPolicyDBTestSuite) BeforeTest(_, _ string) {
+	var (
+		err error
+	)
+	s.dsnCount++
+	dsn := fmt.Sprintf("sqlmock_db_rbacPolicy_%d", s.dsnCount)
+	_, s.mock, err = sqlmock.NewWithDSN(dsn)
+	require.NoError(s.T(), err)
+
+	s.DB, err = gorm.Open("sqlmock", dsn)
+	require.NoError(s.T(), err)
+
+	s.DB.LogMode(true)
+
+	s.repository = domain.NewRbacPolicyRepository(s.DB)
+}
+
+func (s *RbacPolicyDBTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func TestRbacPolicyDBTestSuite(t *testing.T) {
+	suite.Run(t, new(RbacPolicyDBTestSuite))
+}
+
+func (s *RbacPolicyDBTestSuite) TestRepo_GetLatestVersion() {
+	rows := sqlmock.NewRows([]string{"id", "version", "author_id", "comment", "hash", "content"}).
+		AddRow(3, 3, 1, "put role editor", "abc", "user: {}")
+	s.mock.ExpectQuery(`SELECT \* FROM "rbac_policy_versions" ORDER BY version desc`).
+		WillReturnRows(rows)
+
+	version, err := s.repository.GetLatestVersion()
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 3, version.Version)
+	assert.EqualValues(s.T(), "put role editor", version.Comment)
+}
+
+func (s *RbacPolicyDBTestSuite) TestRepo_GetLatestVersion_NeverStored() {
+	s.mock.ExpectQuery(`SELECT \* FROM "rbac_policy_versions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.repository.GetLatestVersion()
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusNotFound, err.Status())
+}
+
+func (s *RbacPolicyDBTestSuite) TestRepo_GetVersions_WithoutContent() {
+	rows := sqlmock.NewRows([]string{"id", "version", "author_id", "comment", "hash"}).
+		AddRow(2, 2, 1, "delete role support", "def").
+		AddRow(1, 1, 0, "imported from role-based-access.yml", "abc")
+	s.mock.ExpectQuery(`SELECT id, version, created_at, author_id, comment, hash FROM "rbac_policy_versions" ORDER BY version desc`).
+		WillReturnRows(rows)
+
+	versions, err := s.repository.GetVersions()
+	require.Nil(s.T(), err)
+	require.Len(s.T(), versions, 2)
+	assert.EqualValues(s.T(), 2, versions[0].Version)
+	assert.Empty(s.T(), versions[0].Content)
+}
+
+func (s *RbacPolicyDBTestSuite) TestRepo_GetRoles() {
+	s.mock.ExpectQuery(`SELECT \* FROM "rbac_roles" ORDER BY "name"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "inherits"}).AddRow(1, "moderator", "user,support"))
+	s.mock.ExpectQuery(`SELECT \* FROM "rbac_resources" WHERE \("role_id" IN \(\?\)\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role_id", "name"}).AddRow(4, 1, "game"))
+	s.mock.ExpectQuery(`SELECT \* FROM "rbac_actions" WHERE \("resource_id" IN \(\?\)\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "resource_id", "name", "allow"}).AddRow(9, 4, "read", true))
+	s.mock.ExpectQuery(`SELECT \* FROM "rbac_rules" WHERE \("action_id" IN \(\?\)\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "action_id", "position", "kind", "target", "rule_key", "operator", "rule_values"}).
+			AddRow(1, 9, 0, domain.RbacRuleEnsure, "query", "genre", "in", `["rpg","strategy, 4X"]`))
+
+	roles, err := s.repository.GetRoles()
+	require.Nil(s.T(), err)
+	require.Len(s.T(), roles, 1)
+	assert.EqualValues(s.T(), domain.RbacRoleNames{"user", "support"}, roles[0].Inherits)
+	rule := roles[0].Resources[0].Actions[0].Rules[0]
+	assert.EqualValues(s.T(), "genre", rule.Key)
+	assert.EqualValues(s.T(), domain.RbacRuleValues{"rpg", "strategy, 4X"}, rule.Values)
+}
+
+func (s *RbacPolicyDBTestSuite) TestRepo_Save_ChangedMeanwhile() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(`SELECT coalesce\(max\(version\), 0\) as version FROM "rbac_policy_versions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	s.mock.ExpectRollback()
+
+	err := s.repository.Save([]domain.RbacRole{{Name: "user"}}, &domain.RbacPolicyVersion{Version: 3})
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusConflict, err.Status())
+	assert.Nil(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *RbacPolicyDBTestSuite) TestRepo_Save() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(`SELECT coalesce\(max\(version\), 0\) as version FROM "rbac_policy_versions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	//the whole policy is replaced
+	s.mock.ExpectExec(`DELETE FROM "rbac_rules"`).WillReturnResult(sqlmock.NewResult(0, 5))
+	s.mock.ExpectExec(`DELETE FROM "rbac_actions"`).WillReturnResult(sqlmock.NewResult(0, 4))
+	s.mock.ExpectExec(`DELETE FROM "rbac_resources"`).WillReturnResult(sqlmock.NewResult(0, 3))
+	s.mock.ExpectExec(`DELETE FROM "rbac_roles"`).WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectExec(`INSERT INTO "rbac_roles"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`INSERT INTO "rbac_resources"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`INSERT INTO "rbac_actions"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectExec(`INSERT INTO "rbac_policy_versions"`).WillReturnResult(sqlmock.NewResult(4, 1))
+	s.mock.ExpectCommit()
+
+	roles := []domain.RbacRole{{
+		Name:      "user",
+		Resources: []domain.RbacResource{{Name: "game", Actions: []domain.RbacAction{{Name: "read", Allow: true}}}},
+	}}
+	err := s.repository.Save(roles, &domain.RbacPolicyVersion{Version: 4, Comment: "put role user"})
+	require.Nil(s.T(), err)
+	assert.Nil(s.T(), s.mock.ExpectationsWereMet())
+}
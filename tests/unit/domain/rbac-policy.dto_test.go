@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"testing"
+)
+
+func TestRbacPolicy_RoundTrip(t *testing.T) {
+	for _, path := range []string{"../resources/rbac-test.yml", "../resources/rbac-roles-test.yml", "../resources/rbac-fields-test.yml", "../../../role-based-access.yml"} {
+		t.Run(path, func(t *testing.T) {
+			content, err := ioutil.ReadFile(path)
+			require.Nil(t, err)
+			policy, err := domain.ParseRbacPolicy(content)
+			require.Nil(t, err)
+
+			//through the tables
+			stored, err := domain.RbacPolicyFromRoles(domain.RbacRolesFromPolicy(policy))
+			require.Nil(t, err)
+			assert.EqualValues(t, policy, stored)
+
+			//through a snapshot
+			marshalled, err := stored.Marshal()
+			require.Nil(t, err)
+			snapshot, err := domain.ParseRbacPolicy(marshalled)
+			require.Nil(t, err)
+			assert.EqualValues(t, policy, snapshot)
+
+			//what gets enforced is the same
+			want, err := domain.ParseRbac(content)
+			require.Nil(t, err)
+			got, err := domain.RbacFromBytes(marshalled)
+			require.Nil(t, err)
+			assert.EqualValues(t, want, got)
+		})
+	}
+}
+
+func TestRbacRolesFromPolicy_RulesInOrder(t *testing.T) {
+	policy := domain.RbacPolicy{"user": {Resources: domain.Resource{"library": domain.Endpoint{"read": {
+		Allow: true,
+		Ensure: domain.Ensurer{
+			Path:  []domain.Rule{{Key: "id", Operator: "=", Value: "ctx.userId"}},
+			Query: []domain.Rule{{Key: "genre", Operator: "in", Values: []string{"rpg", "strategy"}}, {Key: "page", Operator: "exists"}},
+		},
+		Enforce: domain.Enforcer{Query: []domain.Rule{{Key: "page_size", Value: "50"}}},
+	}}}}}
+
+	rules := domain.RbacRolesFromPolicy(policy)[0].Resources[0].Actions[0].Rules
+	require.Len(t, rules, 4)
+	var got []string
+	for i, rule := range rules {
+		assert.EqualValues(t, i, rule.Position)
+		got = append(got, rule.Kind+" "+rule.Target+" "+rule.Key)
+	}
+	assert.EqualValues(t, []string{"ensure query genre", "ensure query page", "ensure path id", "enforce query page_size"}, got)
+	assert.EqualValues(t, domain.RbacRuleValues{"rpg", "strategy"}, rules[0].Values)
+}
+
+func TestRbacPolicyFromRoles_UnknownRule(t *testing.T) {
+	role := func(rule domain.RbacRule) []domain.RbacRole {
+		return []domain.RbacRole{{Name: "user", Resources: []domain.RbacResource{{Name: "game", Actions: []domain.RbacAction{{
+			Name: "read", Allow: true, Rules: []domain.RbacRule{rule},
+		}}}}}}
+	}
+
+	_, err := domain.RbacPolicyFromRoles(role(domain.RbacRule{Kind: "require", Target: "query", Key: "id"}))
+	require.NotNil(t, err)
+	assert.EqualValues(t, "user.game.read: rule on id has unknown kind 'require'", err.Error())
+
+	_, err = domain.RbacPolicyFromRoles(role(domain.RbacRule{Kind: domain.RbacRuleEnsure, Target: "cookie", Key: "id"}))
+	require.NotNil(t, err)
+	assert.EqualValues(t, "user.game.read: rule on id has unknown target 'cookie'", err.Error())
+}
+
+func TestRbacRoleNames_Column(t *testing.T) {
+	value, err := domain.RbacRoleNames{"user", "support"}.Value()
+	require.Nil(t, err)
+	assert.EqualValues(t, "user,support", value)
+
+	var names domain.RbacRoleNames
+	require.Nil(t, names.Scan([]byte("")))
+	assert.Empty(t, names)
+	require.Nil(t, names.Scan("user,support"))
+	assert.EqualValues(t, domain.RbacRoleNames{"user", "support"}, names)
+}
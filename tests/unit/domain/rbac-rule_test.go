@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"testing"
 )
@@ -124,3 +125,94 @@ func (s *rbacRuleTestSuite) TestRule_FromContext() {
 		})
 	}
 }
+
+func (s *rbacRuleTestSuite) TestRule_Check() {
+	tests := []struct {
+		given    string
+		operator string
+		expected interface{}
+		actual   interface{}
+		want     bool
+		wantErr  bool
+	}{
+		{given: "a context id and a route param", operator: "=", expected: uint64(3), actual: "3", want: true},
+		{given: "a context id and another route param", operator: "=", expected: uint64(3), actual: "4", want: false},
+		{given: "a context id and a route param that is not a number", operator: "=", expected: uint64(3), actual: "abc", wantErr: true},
+		{given: "an id too big for a float", operator: "=", expected: uint64(18446744073709551615), actual: "18446744073709551614", want: false},
+		{given: "numeric strings are compared as strings", operator: "=", expected: "0001", actual: "1", want: false},
+		{given: "a number and a decimal string", operator: "=", expected: 1.5, actual: "1.50", want: true},
+		{given: "a boolean and a string", operator: "=", expected: true, actual: "true", want: true},
+		{given: "a boolean and a string that is not one", operator: "!=", expected: true, actual: "yes", wantErr: true},
+		{given: "a value not in the list", operator: "!=", expected: "a", actual: "b", want: true},
+		{given: "nothing and nothing", operator: "=", expected: nil, actual: "", want: true},
+		{given: "a value in the list", operator: "in", expected: []string{"admin", "user"}, actual: "user", want: true},
+		{given: "a value not in the list", operator: "in", expected: []string{"admin", "user"}, actual: "guest", want: false},
+		{given: "a route param in a list of ids", operator: "in", expected: []uint64{1, 3}, actual: "3", want: true},
+		{given: "a value not in a list", operator: "not in", expected: []string{"banned"}, actual: "user", want: true},
+		{given: "in without a list", operator: "in", expected: "admin", actual: "admin", wantErr: true},
+		{given: "a list that cannot hold the value", operator: "in", expected: []uint64{1, 3}, actual: "abc", wantErr: true},
+		{given: "a lower number", operator: "<", expected: "10", actual: "9", want: true},
+		{given: "an equal number", operator: "<=", expected: 10, actual: "10", want: true},
+		{given: "a greater number", operator: ">", expected: "10", actual: "9", want: false},
+		{given: "an equal decimal", operator: ">=", expected: "2.5", actual: "2.50", want: true},
+		{given: "a string that is not a number", operator: ">", expected: "10", actual: "ten", wantErr: true},
+		{given: "a matching pattern", operator: "regex", expected: "^[0-9]+$", actual: "123", want: true},
+		{given: "a pattern not matching", operator: "regex", expected: "^[0-9]+$", actual: "12a", want: false},
+		{given: "an invalid pattern", operator: "regex", expected: "([", actual: "12", wantErr: true},
+		{given: "a prefix", operator: "prefix", expected: "steam:", actual: "steam:42", want: true},
+		{given: "another prefix", operator: "prefix", expected: "steam:", actual: "gog:42", want: false},
+		{given: "a present value", operator: "exists", actual: "x", want: true},
+		{given: "an absent value", operator: "exists", actual: "", want: false},
+		{given: "an unknown operator", operator: "~", expected: "a", actual: "a", wantErr: true},
+	}
+	for _, tt := range tests {
+		s.T().Run(tt.operator+" with "+tt.given, func(t *testing.T) {
+			got, err := domain.Rule{Operator: tt.operator}.Check(tt.expected, tt.actual)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.False(t, domain.Rule{Operator: tt.operator}.Comply(tt.expected, tt.actual))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (s *rbacRuleTestSuite) TestRule_ListValue() {
+	rbac, err := domain.RbacFromBytes([]byte(`
+user:
+  game:
+    read:
+      allow: true
+      ensure:
+        query:
+          - key: platform
+            operator: in
+            value: [steam, gog, 42]
+          - key: page
+            operator: "<="
+            value: 10
+`))
+	require.Nil(s.T(), err)
+	rules := rbac["user"]["game"]["read"].Ensure.Query
+	assert.EqualValues(s.T(), []string{"steam", "gog", "42"}, rules[0].Values)
+	assert.EqualValues(s.T(), "10", rules[1].Value)
+
+	expected, err := rules[0].FromContext(context.Background())
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), []string{"steam", "gog", "42"}, expected)
+
+	_, err = domain.RbacFromBytes([]byte(`
+user:
+  game:
+    read:
+      allow: true
+      ensure:
+        query:
+          - key: platform
+            operator: in
+            value: [[steam], gog]
+`))
+	assert.NotNil(s.T(), err)
+}
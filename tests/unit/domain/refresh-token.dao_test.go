@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+	"time"
+)
+
+type RefreshTokenDBTestSuite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository domain.RefreshTokenRepoInterface
+	dsnCount   int64
+}
+
+func (s *RefreshTokenDBTestSuite) BeforeTest(_, _ string) {
+	var (
+		err error
+	)
+	s.dsnCount++
+	dsn := fmt.Sprintf("sqlmock_db_refreshToken_%d", s.dsnCount)
+	_, s.mock, err = sqlmock.NewWithDSN(dsn)
+	require.NoError(s.T(), err)
+
+	s.DB, err = gorm.Open("sqlmock", dsn)
+	require.NoError(s.T(), err)
+
+	s.DB.LogMode(true)
+
+	s.repository = domain.NewRefreshTokenDBRepository(s.DB)
+}
+
+func (s *RefreshTokenDBTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func TestRefreshTokenDBTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenDBTestSuite))
+}
+
+func (s *RefreshTokenDBTestSuite) TestRepo_MarkUsed_FirstUse() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`UPDATE "refresh_tokens" SET "used_at" = (.+) WHERE \(token = (.+) AND used_at IS NULL\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	firstUse, err := s.repository.MarkUsed("abc", time.Now())
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), firstUse)
+}
+
+func (s *RefreshTokenDBTestSuite) TestRepo_MarkUsed_AlreadyUsed() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`UPDATE "refresh_tokens"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s.mock.ExpectCommit()
+
+	firstUse, err := s.repository.MarkUsed("abc", time.Now())
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), firstUse)
+}
+
+func (s *RefreshTokenDBTestSuite) TestRepo_DeleteByFamily() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`DELETE FROM "refresh_tokens" WHERE \(family_id = `).
+		WithArgs("family").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectCommit()
+
+	deleted, err := s.repository.DeleteByFamily("family")
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 2, deleted)
+}
+
+//only one of many concurrent refreshes with the same token may go through
+func TestRefreshTokenRepo_MarkUsed_Concurrent(t *testing.T) {
+	repository := domain.NewRefreshTokenRepository()
+	_, _ = repository.Create(&domain.RefreshToken{Token: "abc", FamilyId: "family", UserId: 1})
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	successes := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			firstUse, _ := repository.MarkUsed("abc", time.Now())
+			if firstUse {
+				mutex.Lock()
+				successes++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, successes)
+}
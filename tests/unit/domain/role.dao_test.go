@@ -246,6 +246,23 @@ func (s *UserRoleTestSuite) TestUserRoleRepo_Update_Exists() {
 	assert.Equal(s.T(), expected, userRole)
 }
 
+//Test for updating an existing userRole, the changes are the ones saved
+func (s *UserRoleTestSuite) TestUserRoleRepo_Update_KeepsChanges() {
+	selectRows := sqlmock.NewRows([]string{"id", "user_id", "roleName"}).
+		AddRow(1, 1, "admin")
+	s.mock.ExpectQuery(`SELECT`).WillReturnRows(selectRows)
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`UPDATE "user_roles" SET (.+)"roleName" = \?`).
+		WithArgs(sqlmock.AnyArg(), nil, 1, "user", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	userRole, err := s.repository.Update(&domain.UserRole{ID: 1, UserID: 1, Name: "user"})
+	require.True(s.T(), err == nil)
+	assert.EqualValues(s.T(), "user", userRole.Name)
+	assert.Nil(s.T(), s.mock.ExpectationsWereMet())
+}
+
 //Test for inserting a new userRole
 func (s *UserRoleTestSuite) TestUserRoleRepo_Insert_Succeeds() {
 	s.mock.ExpectBegin()
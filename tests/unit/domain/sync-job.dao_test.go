@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type SyncJobTestSuite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository domain.SyncJobRepoInterface
+	dsnCount   int64
+}
+
+func (s *SyncJobTestSuite) BeforeTest(_, _ string) {
+	var (
+		err error
+	)
+	s.dsnCount++
+	dsn := fmt.Sprintf("sqlmock_db_syncJob_%d", s.dsnCount)
+	_, s.mock, err = sqlmock.NewWithDSN(dsn)
+	require.NoError(s.T(), err)
+
+	s.DB, err = gorm.Open("sqlmock", dsn)
+	require.NoError(s.T(), err)
+
+	s.DB.LogMode(true)
+
+	s.repository = domain.NewSyncJobRepository(s.DB)
+}
+
+func (s *SyncJobTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func TestSyncJobsTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncJobTestSuite))
+}
+
+func (s *SyncJobTestSuite) TestSyncJobRepo_Get() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "sync_jobs" WHERE (.+)id = `).
+		WithArgs(12).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status", "total", "errored"}).
+			AddRow(12, 3, "running", 10, 1))
+	s.mock.ExpectQuery(`SELECT (.+) FROM "sync_job_errors"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "job_id", "app_id", "message"}).
+			AddRow(1, 12, "30", "steam store unavailable"))
+
+	job, err := s.repository.Get(12)
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), domain.SyncJobRunning, job.Status)
+	assert.Len(s.T(), job.Errors, 1)
+	assert.EqualValues(s.T(), "30", job.Errors[0].AppId)
+}
+
+func (s *SyncJobTestSuite) TestSyncJobRepo_Get_NotFound() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "sync_jobs"`).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	job, err := s.repository.Get(12)
+	assert.Nil(s.T(), job)
+	assert.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), "not_found", err.Error())
+}
+
+func (s *SyncJobTestSuite) TestSyncJobRepo_GetByInstance() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "sync_jobs" WHERE \(instance = \? AND status IN `).
+		WithArgs("api-1", "queued", "running").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "queued").AddRow(2, "running"))
+
+	jobs, err := s.repository.GetByInstance("api-1", domain.SyncJobQueued, domain.SyncJobRunning)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), jobs, 2)
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type UserGameTestSuite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository domain.UserGameRepoInterface
+	dsnCount   int64
+}
+
+func (s *UserGameTestSuite) BeforeTest(_, _ string) {
+	var (
+		err error
+	)
+	s.dsnCount++
+	dsn := fmt.Sprintf("sqlmock_db_userGame_%d", s.dsnCount)
+	_, s.mock, err = sqlmock.NewWithDSN(dsn)
+	require.NoError(s.T(), err)
+
+	s.DB, err = gorm.Open("sqlmock", dsn)
+	require.NoError(s.T(), err)
+
+	s.DB.LogMode(true)
+
+	s.repository = domain.NewUserGameRepository(s.DB)
+}
+
+func (s *UserGameTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func TestUserGamesTestSuite(t *testing.T) {
+	suite.Run(t, new(UserGameTestSuite))
+}
+
+func (s *UserGameTestSuite) TestUserGameRepo_GetByUserID() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "user_games" WHERE (.+)user_id = `).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "game_id", "source", "playtime_forever"}).
+			AddRow(1, 3, 10, "steam", 120))
+	s.mock.ExpectQuery(`SELECT (.+) FROM "games"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(10, "Rocket League"))
+
+	library, err := s.repository.GetByUserID(3)
+	require.Nil(s.T(), err)
+	assert.Len(s.T(), library, 1)
+	assert.EqualValues(s.T(), 120, library[0].PlaytimeForever)
+	assert.EqualValues(s.T(), "Rocket League", library[0].Game.Title)
+}
+
+func (s *UserGameTestSuite) TestUserGameRepo_GetByUserAndGame_NotFound() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "user_games"`).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	entry, err := s.repository.GetByUserAndGame(3, 10, "steam")
+	assert.Nil(s.T(), entry)
+	assert.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), "not_found", err.Error())
+}
+
+func (s *UserGameTestSuite) TestUserGameRepo_GetByUserAndGame_Failure() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "user_games"`).
+		WillReturnError(fmt.Errorf("connection reset"))
+
+	entry, err := s.repository.GetByUserAndGame(3, 10, "steam")
+	assert.Nil(s.T(), entry)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusInternalServerError, err.Status())
+}
+
+//the entry is deleted for good, so the next sync can add the game again without hitting the unique index
+func (s *UserGameTestSuite) TestUserGameRepo_DeleteThenResync() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "user_games" WHERE (.+)id = `).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "game_id", "source"}).AddRow(7, 3, 10, "steam"))
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`DELETE FROM "user_games" WHERE (.+)id(.+)`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+	s.mock.ExpectQuery(`SELECT (.+) FROM "user_games" WHERE (.+)user_id = (.+)`).
+		WillReturnRows(sqlmock.NewRows(nil))
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`INSERT INTO "user_games"`).WillReturnResult(sqlmock.NewResult(8, 1))
+	s.mock.ExpectCommit()
+
+	require.Nil(s.T(), s.repository.Delete(7))
+	_, err := s.repository.GetByUserAndGame(3, 10, "steam")
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusNotFound, err.Status())
+	_, err = s.repository.Create(&domain.UserGame{UserID: 3, GameID: 10, Source: "steam"})
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *UserGameTestSuite) TestUserGameRepo_Create() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`INSERT INTO "user_games"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	entry, err := s.repository.Create(&domain.UserGame{UserID: 3, GameID: 10, Source: "steam"})
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 3, entry.UserID)
+	assert.Nil(s.T(), s.mock.ExpectationsWereMet())
+}
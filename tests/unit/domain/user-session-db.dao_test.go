@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"GamesAPI/src/domain"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type UserSessionDBTestSuite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository domain.UserSessionRepoInterface
+	dsnCount   int64
+}
+
+func (s *UserSessionDBTestSuite) BeforeTest(_, _ string) {
+	var (
+		err error
+	)
+	s.dsnCount++
+	dsn := fmt.Sprintf("sqlmock_db_userSession_%d", s.dsnCount)
+	_, s.mock, err = sqlmock.NewWithDSN(dsn)
+	require.NoError(s.T(), err)
+
+	s.DB, err = gorm.Open("sqlmock", dsn)
+	require.NoError(s.T(), err)
+
+	s.DB.LogMode(true)
+
+	s.repository = domain.NewUserSessionDBRepository(s.DB)
+}
+
+func (s *UserSessionDBTestSuite) TearDownTest() {
+	s.DB.Close()
+}
+
+func TestUserSessionDBTestSuite(t *testing.T) {
+	suite.Run(t, new(UserSessionDBTestSuite))
+}
+
+func (s *UserSessionDBTestSuite) TestRepo_Get() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "user_sessions" WHERE \(token = `).
+		WithArgs("abcdef").
+		WillReturnRows(sqlmock.NewRows([]string{"token", "user_id", "expires_at"}).AddRow("abcdef", 1, 42))
+
+	session, err := s.repository.Get("abcdef")
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 1, session.UserId)
+	assert.EqualValues(s.T(), 42, session.ExpiresAt)
+}
+
+func (s *UserSessionDBTestSuite) TestRepo_Get_NotFound() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "user_sessions"`).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	session, err := s.repository.Get("abcdef")
+	assert.Nil(s.T(), session)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusNotFound, err.Status())
+}
+
+func (s *UserSessionDBTestSuite) TestRepo_Create() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`INSERT INTO "user_sessions"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	s.mock.ExpectCommit()
+
+	session, err := s.repository.Create("abcdef", &domain.UserSession{UserId: 1, ExpiresAt: 42})
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), "abcdef", session.Token)
+}
+
+func (s *UserSessionDBTestSuite) TestRepo_Exists() {
+	s.mock.ExpectQuery(`SELECT count\(\*\) FROM "user_sessions" WHERE \(token = `).
+		WithArgs("abcdef").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	assert.True(s.T(), s.repository.Exists("abcdef"))
+}
+
+func (s *UserSessionDBTestSuite) TestRepo_Delete() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`DELETE FROM "user_sessions" WHERE \(token = `).
+		WithArgs("abcdef").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	s.mock.ExpectCommit()
+
+	assert.Nil(s.T(), s.repository.Delete("abcdef"))
+	assert.Nil(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func (s *UserSessionDBTestSuite) TestRepo_DeleteExpired() {
+	current := time.Now()
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`DELETE FROM "user_sessions" WHERE \(expires_at < `).
+		WithArgs(current.UnixNano()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	s.mock.ExpectCommit()
+
+	deleted, err := s.repository.DeleteExpired(current)
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 3, deleted)
+}
+
+func (s *UserSessionDBTestSuite) TestRepo_GetByUserID() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "user_sessions" WHERE \(user_id = `).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"token", "user_id", "expires_at"}).AddRow("a", 1, 42).AddRow("b", 1, 43))
+
+	sessions, err := s.repository.GetByUserID(1)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), sessions, 2)
+}
+
+func (s *UserSessionDBTestSuite) TestRepo_DeleteByUserID() {
+	s.mock.ExpectBegin()
+	s.mock.ExpectExec(`DELETE FROM "user_sessions" WHERE \(user_id = `).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	s.mock.ExpectCommit()
+
+	deleted, err := s.repository.DeleteByUserID(1)
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 2, deleted)
+}
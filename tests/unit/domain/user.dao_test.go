@@ -90,6 +90,29 @@ func (s *UserTestSuite) TestUserRepo_GetAll_NotEmpty() {
 	assert.Equal(s.T(), expected, data)
 }
 
+func (s *UserTestSuite) TestUserRepo_GetByEmail_Found() {
+	rows := sqlmock.NewRows([]string{"id", "email", "name"}).
+		AddRow(4, "devgolang@test.com", "devgolang")
+	s.mock.ExpectQuery(`SELECT (.+) FROM "users" WHERE (.+)email = \?`).
+		WithArgs("devgolang@test.com").
+		WillReturnRows(rows)
+
+	user, err := s.repository.GetByEmail("devgolang@test.com")
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 4, user.ID)
+}
+
+func (s *UserTestSuite) TestUserRepo_GetByEmail_NotFound() {
+	s.mock.ExpectQuery(`SELECT (.+) FROM "users"`).
+		WithArgs("nobody@test.com").
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	user, err := s.repository.GetByEmail("nobody@test.com")
+	assert.Nil(s.T(), user)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), 404, err.Status())
+}
+
 //Test for getting a single user from empty table.
 func (s *UserTestSuite) TestUserRepo_Get_Empty() {
 	rows := sqlmock.NewRows(nil)
@@ -2,9 +2,11 @@ package domain
 
 import (
 	"GamesAPI/src/domain"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 )
@@ -111,3 +113,43 @@ func (s *UATS) TestRepo_Exists_NotExists() {
 	key := "bji"
 	assert.False(s.T(), domain.UserSessionRepo.Exists(key))
 }
+
+func (s *UATS) TestRepo_DeleteExpired() {
+	current := time.Now()
+	_, _ = domain.UserSessionRepo.Create("expired", &domain.UserSession{Token: "expired", UserId: 1, ExpiresAt: current.Add(-time.Minute).UnixNano()})
+	_, _ = domain.UserSessionRepo.Create("valid", &domain.UserSession{Token: "valid", UserId: 1, ExpiresAt: current.Add(time.Minute).UnixNano()})
+
+	deleted, err := domain.UserSessionRepo.DeleteExpired(current)
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 1, deleted)
+	assert.False(s.T(), domain.UserSessionRepo.Exists("expired"))
+	assert.True(s.T(), domain.UserSessionRepo.Exists("valid"))
+}
+
+func (s *UATS) TestRepo_Delete_FreesEntry() {
+	key := "bji"
+	_, _ = domain.UserSessionRepo.Create(key, s.userAuthToken)
+	_ = domain.UserSessionRepo.Delete(key)
+
+	//a deleted session is gone for good, the reaper has nothing left to free
+	deleted, _ := domain.UserSessionRepo.DeleteExpired(time.Now())
+	assert.EqualValues(s.T(), 0, deleted)
+}
+
+func (s *UATS) TestRepo_ConcurrentAccess() {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i)
+			_, _ = domain.UserSessionRepo.Create(key, &domain.UserSession{Token: key, UserId: 1, ExpiresAt: now})
+			_ = domain.UserSessionRepo.Exists(key)
+			_, _ = domain.UserSessionRepo.Get(key)
+			_ = domain.UserSessionRepo.Delete(key)
+			_, _ = domain.UserSessionRepo.DeleteExpired(time.Now())
+		}(i)
+	}
+	wg.Wait()
+	assert.False(s.T(), domain.UserSessionRepo.Exists("key-0"))
+}
@@ -4,6 +4,7 @@ import (
 	"GamesAPI/src/External/Steam"
 	"GamesAPI/src/domain"
 	"GamesAPI/tests/unit/mocks"
+	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -31,7 +32,7 @@ func (s *SteamUserAPITestSuite) TestGetSteamUserID_Success() {
 		return "76561197960287939", nil
 	})
 	t := s.T()
-	steamUserID, err := Steam.ExternalSteamUserService.GetUserID("gabelogannewell")
+	steamUserID, err := Steam.ExternalSteamUserService.GetUserID(context.Background(), "gabelogannewell")
 	assert.Nil(t, err)
 	assert.EqualValues(t, "76561197960287939", steamUserID)
 }
@@ -41,40 +42,41 @@ func (s *SteamUserAPITestSuite) TestGetSteamUserID_Fail() {
 		return "", nil
 	})
 	t := s.T()
-	steamUserID, err := Steam.ExternalSteamUserService.GetUserID("invalidUserURL")
+	steamUserID, err := Steam.ExternalSteamUserService.GetUserID(context.Background(), "invalidUserURL")
 	assert.Nil(t, err)
 	assert.EqualValues(t, "", steamUserID)
 }
 
 func (s *SteamUserAPITestSuite) TestGetSteamUserOwnedGames_Success() {
-	s.mock.SetGetUserOwnedGames(func(personalURL string) ([]string, error) {
-		return []string{"44", "22"}, nil
+	s.mock.SetGetUserOwnedGames(func(personalURL string) ([]Steam.OwnedGame, error) {
+		return []Steam.OwnedGame{{AppId: "44", PlaytimeForever: 120}, {AppId: "22"}}, nil
 	})
 	steamUserID := "76561198017133337"
-	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(steamUserID)
+	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(context.Background(), steamUserID)
 	t := s.T()
 	assert.Nil(t, err)
-	assert.EqualValues(t, "44", steamGamesIDs[0])
-	assert.EqualValues(t, "22", steamGamesIDs[1])
+	assert.EqualValues(t, "44", steamGamesIDs[0].AppId)
+	assert.EqualValues(t, 120, steamGamesIDs[0].PlaytimeForever)
+	assert.EqualValues(t, "22", steamGamesIDs[1].AppId)
 }
 
 func (s *SteamUserAPITestSuite) TestGetSteamUserOwnedGames_OwnesNoGames() {
-	s.mock.SetGetUserOwnedGames(func(personalURL string) ([]string, error) {
-		return []string{}, nil
+	s.mock.SetGetUserOwnedGames(func(personalURL string) ([]Steam.OwnedGame, error) {
+		return []Steam.OwnedGame{}, nil
 	})
 	steamUserID := "76561197960287930"
-	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(steamUserID)
+	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(context.Background(), steamUserID)
 	t := s.T()
 	assert.Nil(t, err)
 	assert.EqualValues(t, 0, len(steamGamesIDs))
 }
 
 func (s *SteamUserAPITestSuite) TestGetSteamUserOwnedGames_BadUserID() {
-	s.mock.SetGetUserOwnedGames(func(personalURL string) ([]string, error) {
-		return []string{}, nil
+	s.mock.SetGetUserOwnedGames(func(personalURL string) ([]Steam.OwnedGame, error) {
+		return []Steam.OwnedGame{}, nil
 	})
 	steamUserID := "thishavenochanceofbeingarealsteamid1324567899876544321"
-	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(steamUserID)
+	steamGamesIDs, err := Steam.ExternalSteamUserService.GetUserOwnedGames(context.Background(), steamUserID)
 	t := s.T()
 	assert.Nil(t, err)
 	assert.EqualValues(t, 0, len(steamGamesIDs))
@@ -94,7 +96,7 @@ func (s *SteamUserAPITestSuite) TestGetSteamGame_Success(){
 		}, nil
 	})
 	gameID := "524220"
-	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(gameID)
+	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(context.Background(), gameID)
 	t := s.T()
 	assert.Nil(t, err)
 	assert.EqualValues(t, "NieR:Automata™", gameInfo.Title)
@@ -117,7 +119,7 @@ func (s *SteamUserAPITestSuite) TestGetSteamGame_SuccessSecondGame(){
 		}, nil
 	})
 	gameID := "218620"
-	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(gameID)
+	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(context.Background(), gameID)
 	t := s.T()
 	assert.Nil(t, err)
 	assert.EqualValues(t, "PAYDAY 2", gameInfo.Title)
@@ -131,7 +133,7 @@ func (s *SteamUserAPITestSuite) TestGetSteamGame_BadGameID(){
 		return domain.Game{}, errors.New("bad Game ID")
 	})
 	gameID := "65465156435"
-	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(gameID)
+	gameInfo, err := Steam.ExternalSteamUserService.GetGameInfo(context.Background(), gameID)
 	t := s.T()
 	assert.NotNil(t, err)
 	assert.EqualValues(t, domain.Game{}, gameInfo)
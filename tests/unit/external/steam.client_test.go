@@ -0,0 +1,219 @@
+package external
+
+import (
+	"GamesAPI/src/External/Steam"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type SteamClientTestSuite struct {
+	suite.Suite
+	server  *httptest.Server
+	handler http.HandlerFunc
+	calls   int32
+	service Steam.ExternalSteamUserServiceInterface
+}
+
+func TestSteamClientTestSuite(t *testing.T) {
+	suite.Run(t, new(SteamClientTestSuite))
+}
+
+//every test points the service at a local fake Steam, the handler is swapped per test
+func (s *SteamClientTestSuite) SetupSuite() {
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.calls, 1)
+		s.handler(w, r)
+	}))
+	maxRetries := 2
+	s.service = Steam.NewExternalSteamUserService(Steam.NewSteamClient(Steam.SteamClientConfig{
+		APIBaseURL:   s.server.URL,
+		StoreBaseURL: s.server.URL,
+		APIKey:       "test-key",
+		Timeout:      time.Second,
+		MaxRetries:   &maxRetries,
+		BaseBackoff:  time.Millisecond,
+	}))
+}
+
+func (s *SteamClientTestSuite) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *SteamClientTestSuite) BeforeTest(_, _ string) {
+	atomic.StoreInt32(&s.calls, 0)
+}
+
+func (s *SteamClientTestSuite) TestGetUserOwnedGames_Success() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(s.T(), "/IPlayerService/GetOwnedGames/v0001/", r.URL.Path)
+		assert.EqualValues(s.T(), "test-key", r.URL.Query().Get("key"))
+		assert.EqualValues(s.T(), "76561198017133337", r.URL.Query().Get("steamid"))
+		fmt.Fprint(w, `{"response":{"game_count":2,"games":[{"appid":2100,"playtime_forever":42},{"appid":2130}]}}`)
+	}
+
+	games, err := s.service.GetUserOwnedGames(context.Background(), "76561198017133337")
+	t := s.T()
+	require.Nil(t, err)
+	assert.Len(t, games, 2)
+	assert.EqualValues(t, "2100", games[0].AppId)
+	assert.EqualValues(t, 42, games[0].PlaytimeForever)
+}
+
+func (s *SteamClientTestSuite) TestGetUserID_RetriesWhenThrottled() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&s.calls) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"response":{"steamid":"76561197960287930","success":1}}`)
+	}
+
+	steamID, err := s.service.GetUserID(context.Background(), "gabelogannewell")
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "76561197960287930", steamID)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&s.calls))
+}
+
+func (s *SteamClientTestSuite) TestGetUserID_GivesUpOnServerErrors() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	steamID, err := s.service.GetUserID(context.Background(), "gabelogannewell")
+	t := s.T()
+	assert.EqualValues(t, "", steamID)
+	require.NotNil(t, err)
+	assert.IsType(t, &Steam.ErrSteamStatus{}, err)
+	//first call + 2 retries
+	assert.EqualValues(t, 3, atomic.LoadInt32(&s.calls))
+}
+
+func (s *SteamClientTestSuite) TestGetUserID_DoesNotRetryClientErrors() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}
+
+	_, err := s.service.GetUserID(context.Background(), "gabelogannewell")
+	t := s.T()
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&s.calls))
+}
+
+func (s *SteamClientTestSuite) TestGetUserID_NoRetriesFromEnv() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	require.Nil(s.T(), os.Setenv("STEAM_MAX_RETRIES", "0"))
+	defer os.Unsetenv("STEAM_MAX_RETRIES")
+	config := Steam.ConfigFromEnv()
+	config.APIBaseURL = s.server.URL
+	service := Steam.NewExternalSteamUserService(Steam.NewSteamClient(config))
+
+	_, err := service.GetUserID(context.Background(), "gabelogannewell")
+	assert.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), 1, atomic.LoadInt32(&s.calls))
+}
+
+func (s *SteamClientTestSuite) TestGetUserID_NoRetriesFromConfig() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	noRetries := 0
+	service := Steam.NewExternalSteamUserService(Steam.NewSteamClient(Steam.SteamClientConfig{
+		APIBaseURL: s.server.URL,
+		MaxRetries: &noRetries,
+	}))
+
+	_, err := service.GetUserID(context.Background(), "gabelogannewell")
+	assert.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), 1, atomic.LoadInt32(&s.calls))
+}
+
+func (s *SteamClientTestSuite) TestGetUserID_DefaultRetriesWhenUnset() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	require.Nil(s.T(), os.Unsetenv("STEAM_MAX_RETRIES"))
+	config := Steam.ConfigFromEnv()
+	config.APIBaseURL = s.server.URL
+	config.BaseBackoff = time.Millisecond
+	service := Steam.NewExternalSteamUserService(Steam.NewSteamClient(config))
+
+	_, err := service.GetUserID(context.Background(), "gabelogannewell")
+	assert.NotNil(s.T(), err)
+	//the first call and 3 retries
+	assert.EqualValues(s.T(), 4, atomic.LoadInt32(&s.calls))
+}
+
+func (s *SteamClientTestSuite) TestGetUserID_TransportErrorHidesKey() {
+	//nothing listens there anymore
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	noRetries := 0
+	service := Steam.NewExternalSteamUserService(Steam.NewSteamClient(Steam.SteamClientConfig{
+		APIBaseURL: closed.URL,
+		APIKey:     "secret-steam-key",
+		MaxRetries: &noRetries,
+	}))
+
+	_, err := service.GetUserID(context.Background(), "gabelogannewell")
+	require.NotNil(s.T(), err)
+	assert.NotContains(s.T(), err.Error(), "secret-steam-key")
+	assert.Contains(s.T(), err.Error(), "key=REDACTED")
+}
+
+func (s *SteamClientTestSuite) TestGetGameInfo_CancelledContext() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.service.GetGameInfo(ctx, "524220")
+	assert.EqualValues(s.T(), context.Canceled, err)
+}
+
+func (s *SteamClientTestSuite) TestGetGameInfo_Success() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		assert.EqualValues(s.T(), "/api/appdetails", r.URL.Path)
+		assert.EqualValues(s.T(), "", r.URL.Query().Get("key"))
+		fmt.Fprint(w, `{"524220":{"success":true,"data":{"name":"NieR:Automata","developers":["Square Enix","PlatinumGames Inc."],"publishers":["Square Enix"]}}}`)
+	}
+
+	game, err := s.service.GetGameInfo(context.Background(), "524220")
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "NieR:Automata", game.Title)
+	assert.EqualValues(t, "Square Enix | PlatinumGames Inc.", game.Developer)
+	assert.EqualValues(t, "524220", game.SteamId)
+}
+
+func (s *SteamClientTestSuite) TestTokenBucket_WaitsForRefill() {
+	bucket := Steam.NewTokenBucket(2, 100*time.Millisecond)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.Nil(s.T(), bucket.Wait(ctx))
+	}
+	//the first two tokens are free, the third one needs 50ms to come back
+	assert.True(s.T(), time.Since(start) >= 40*time.Millisecond)
+}
+
+func (s *SteamClientTestSuite) TestTokenBucket_StopsWithContext() {
+	bucket := Steam.NewTokenBucket(1, time.Hour)
+	require.Nil(s.T(), bucket.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.EqualValues(s.T(), context.DeadlineExceeded, bucket.Wait(ctx))
+}
@@ -1,10 +1,11 @@
 package middleware
 
 import (
+	"GamesAPI/src/domain"
 	"GamesAPI/src/middleware"
 	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
 	"GamesAPI/tests/unit/mocks"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -15,9 +16,10 @@ import (
 
 type TestMiddlewareApiTokenSuite struct {
 	suite.Suite
-	mockService mocks.TokenServiceMockInterface
+	mockService mocks.ApiKeyServiceMockInterface
 	r           *gin.Engine
 	rr          *httptest.ResponseRecorder
+	client      *domain.ApiClient
 }
 
 func BidonHandler(ctx *gin.Context) {
@@ -26,6 +28,7 @@ func BidonHandler(ctx *gin.Context) {
 
 func (t *TestMiddlewareApiTokenSuite) BeforeTest(_, _ string) {
 	t.rr = httptest.NewRecorder()
+	t.client = nil
 }
 
 func TestMiddlewareApiTokenTestSuite(t *testing.T) {
@@ -33,73 +36,65 @@ func TestMiddlewareApiTokenTestSuite(t *testing.T) {
 }
 
 func (t *TestMiddlewareApiTokenSuite) SetupSuite() {
-	mock := &mocks.TokenServiceMock{}
+	mock := &mocks.ApiKeyServiceMock{}
 
-	t.mockService = mock         //set this so we can swap the methods
-	services.TokenService = mock //set this so the tested code calls the swapped methods
+	t.mockService = mock          //set this so we can swap the methods
+	services.ApiKeyService = mock //set this so the tested code calls the swapped methods
 	t.r = gin.Default()
 	t.r.Use(middleware.MiddlewareHandler)
-	t.r.GET("/", BidonHandler)
+	t.r.GET("/", func(c *gin.Context) {
+		t.client, _ = c.Request.Context().Value(domain.ApiClientKey()).(*domain.ApiClient)
+		BidonHandler(c)
+	})
 
 }
 
 func (t *TestMiddlewareApiTokenSuite) TestMiddlewareService_Authentication_TokenValid() {
-	t.mockService.SetValidateToken(func(string) (bool, error) {
-		return true, nil
+	t.mockService.SetAuthenticate(func(rawKey string) (*domain.ApiKey, errorUtils.EntityError) {
+		assert.Equal(t.T(), "gapi_0a1b2c3d4e5f_secret", rawKey)
+		return &domain.ApiKey{ID: 4, OwnerId: 2, Label: "mobile app", Prefix: "0a1b2c3d4e5f", Scopes: domain.ApiScopes{"games"}}, nil
 	})
 
-	tokenApi := "1234"
 	req, _ := http.NewRequest(http.MethodGet, "/", nil)
-	req.Header.Add("x-api-key", tokenApi)
+	req.Header.Add("x-api-key", "gapi_0a1b2c3d4e5f_secret")
 	t.r.ServeHTTP(t.rr, req)
 
 	assert.Equal(t.T(), http.StatusOK, t.rr.Code)
-	assert.NotEqual(t.T(), 400, t.rr.Code)
-	assert.NotEqual(t.T(), 401, t.rr.Code)
-	assert.NotEqual(t.T(), 500, t.rr.Code)
+	//the client is known to the rest of the request
+	if assert.NotNil(t.T(), t.client) {
+		assert.EqualValues(t.T(), 4, t.client.KeyId)
+		assert.EqualValues(t.T(), 2, t.client.OwnerId)
+		assert.EqualValues(t.T(), "mobile app", t.client.Label)
+		assert.True(t.T(), t.client.Scopes.Has("games"))
+	}
 }
-func (t *TestMiddlewareApiTokenSuite) TestMiddlewareService_Authentication_MissingToken() {
-	t.mockService.SetValidateToken(func(string) (bool, error) {
-		return false, nil
-	})
 
+func (t *TestMiddlewareApiTokenSuite) TestMiddlewareService_Authentication_MissingToken() {
 	req, _ := http.NewRequest(http.MethodGet, "/", nil)
 	t.r.ServeHTTP(t.rr, req)
 
 	assert.Equal(t.T(), 400, t.rr.Code)
-	assert.NotEqual(t.T(), 500, t.rr.Code)
-	assert.NotEqual(t.T(), 401, t.rr.Code)
-	assert.NotEqual(t.T(), http.StatusOK, t.rr.Code)
 }
 
 func (t *TestMiddlewareApiTokenSuite) TestMiddlewareService_Authentication_ErrorValidationToken() {
-	t.mockService.SetValidateToken(func(string) (bool, error) {
-		return true, errors.New("Environment  Api key Token is not find.")
+	t.mockService.SetAuthenticate(func(rawKey string) (*domain.ApiKey, errorUtils.EntityError) {
+		return nil, errorUtils.NewInternalServerError("database is down")
 	})
-	tokenApi := "1245"
 	req, _ := http.NewRequest(http.MethodGet, "/", nil)
-	req.Header.Add("x-api-key", tokenApi)
+	req.Header.Add("x-api-key", "gapi_0a1b2c3d4e5f_secret")
 	t.r.ServeHTTP(t.rr, req)
 
 	assert.Equal(t.T(), 500, t.rr.Code)
-	assert.NotEqual(t.T(), 400, t.rr.Code)
-	assert.NotEqual(t.T(), 401, t.rr.Code)
-	assert.NotEqual(t.T(), http.StatusOK, t.rr.Code)
-
 }
 
 func (t *TestMiddlewareApiTokenSuite) TestMiddlewareService_Authentication_InvalidToken() {
-	t.mockService.SetValidateToken(func(string) (bool, error) {
-		return false, nil
+	t.mockService.SetAuthenticate(func(rawKey string) (*domain.ApiKey, errorUtils.EntityError) {
+		return nil, errorUtils.NewUnauthorizedError("Invalid API key")
 	})
-	tokenApi := "1245"
 	req, _ := http.NewRequest(http.MethodGet, "/", nil)
-	req.Header.Add("x-api-key", tokenApi)
+	req.Header.Add("x-api-key", "1245")
 	t.r.ServeHTTP(t.rr, req)
 
 	assert.Equal(t.T(), 401, t.rr.Code)
-	assert.NotEqual(t.T(), 400, t.rr.Code)
-	assert.NotEqual(t.T(), 500, t.rr.Code)
-	assert.NotEqual(t.T(), http.StatusOK, t.rr.Code)
-
+	assert.Nil(t.T(), t.client)
 }
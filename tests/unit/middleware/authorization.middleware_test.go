@@ -3,6 +3,7 @@ package middleware
 import (
 	"GamesAPI/src/domain"
 	"GamesAPI/src/middleware"
+	"GamesAPI/src/router"
 	"GamesAPI/src/services"
 	"GamesAPI/src/utils/errorUtils"
 	"GamesAPI/tests/unit/mocks"
@@ -11,9 +12,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -38,10 +41,21 @@ func (s *AuthTestSuite) SetupSuite() {
 	services.UserRoleService = mockUserRoles
 	s.r = gin.Default()
 	s.r.Use(middleware.AuthorizationHandler)
-	s.r.GET("/games", BidonController)
+	router.Rbac(&s.r.RouterGroup, "game").GET("/games", BidonController)
+	//routes which do not declare a resource
 	s.r.GET("/achievements", BidonController)
 	s.r.HEAD("/games", BidonController)
-
+	router.Rbac(&s.r.RouterGroup, "library").GET("/users/:id/library", BidonController)
+	router.Rbac(&s.r.RouterGroup, "library").GET("/users/:id/games", BidonController)
+	router.Rbac(&s.r.RouterGroup, "user_logout").POST("/users/:id/logout", BidonController)
+	router.Rbac(&s.r.RouterGroup, "library").Handle(http.MethodPost, "sync", "/SyncGames", func(c *gin.Context) {
+		body, _ := ioutil.ReadAll(c.Request.Body)
+		c.String(200, string(body))
+	})
+	router.Rbac(&s.r.RouterGroup, "sync_job").GET("/sync-jobs/:id", func(c *gin.Context) {
+		decision, _ := domain.RbacDecisionFromContext(c.Request.Context())
+		c.JSON(200, decision)
+	})
 }
 
 func BidonController(c *gin.Context) {
@@ -126,8 +140,8 @@ func (s *AuthTestSuite) TestAuth_ForbiddenAccess() {
 			},
 		}, nil
 	})
-	s.mockAuthService.SetAuthorize(func(ctx context.Context, url *url.URL, role string, resource string, endpoint string) error {
-		return errors.New("query does not comply")
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		return &domain.RbacDecision{Roles: roles}, errors.New("query does not comply")
 	})
 	resource := "/games"
 	endpoint := http.MethodGet
@@ -150,8 +164,8 @@ func (s *AuthTestSuite) TestAuth_GrantedAccess() {
 			},
 		}, nil
 	})
-	s.mockAuthService.SetAuthorize(func(ctx context.Context, url *url.URL, role string, resource string, endpoint string) error {
-		return nil
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		return &domain.RbacDecision{Role: roles[0], Roles: roles}, nil
 	})
 	resource := "/games"
 	endpoint := http.MethodGet
@@ -163,3 +177,204 @@ func (s *AuthTestSuite) TestAuth_GrantedAccess() {
 	t := s.T()
 	assert.EqualValues(t, 200, s.rr.Code)
 }
+
+func (s *AuthTestSuite) TestAuth_LibraryRouteParams() {
+	s.mockUserRoleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 1, UserID: 1, Name: "User"}}, nil
+	})
+	var receivedResource string
+	var receivedParams map[string]string
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		receivedResource = resource
+		receivedParams, _ = ctx.Value(domain.RbacRouteParams()).(map[string]string)
+		return &domain.RbacDecision{Role: roles[0], Roles: roles}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/3/library", nil)
+	req = req.WithContext(context.WithValue(context.Background(), domain.RbacUserId(), uint64(1)))
+	s.r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, 200, s.rr.Code)
+	assert.EqualValues(t, "library", receivedResource)
+	assert.EqualValues(t, "3", receivedParams["id"])
+}
+
+func (s *AuthTestSuite) TestAuth_RolesFromJwt() {
+	s.mockUserRoleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		assert.Fail(s.T(), "roles carried by a JWT should not be looked up")
+		return nil, errorUtils.NewNotFoundError("user cannot be found")
+	})
+	var receivedRole string
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		receivedRole = roles[0]
+		return &domain.RbacDecision{Role: roles[0], Roles: roles}, nil
+	})
+
+	ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(1))
+	ctx = context.WithValue(ctx, domain.RbacUserRoles(), []string{"Admin"})
+	req, _ := http.NewRequest(http.MethodGet, "/games", nil)
+	req = req.WithContext(ctx)
+	s.r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, 200, s.rr.Code)
+	assert.EqualValues(t, "admin", receivedRole)
+}
+
+func (s *AuthTestSuite) TestAuth_ForceLogoutResource() {
+	s.mockUserRoleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 1, UserID: 1, Name: "Admin"}}, nil
+	})
+	var receivedResource, receivedEndpoint string
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		receivedResource, receivedEndpoint = resource, endpoint
+		return &domain.RbacDecision{Role: roles[0], Roles: roles}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/3/logout", nil)
+	req = req.WithContext(context.WithValue(context.Background(), domain.RbacUserId(), uint64(1)))
+	s.r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, 200, s.rr.Code)
+	assert.EqualValues(t, "user_logout", receivedResource)
+	assert.EqualValues(t, "create", receivedEndpoint)
+}
+
+func (s *AuthTestSuite) TestAuth_RouteParamsAndHeadersInContext() {
+	s.mockUserRoleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 1, UserID: 1, Name: "user"}}, nil
+	})
+	var params map[string]string
+	var headers http.Header
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		params, _ = ctx.Value(domain.RbacRouteParams()).(map[string]string)
+		headers, _ = ctx.Value(domain.RbacRequestHeaders()).(http.Header)
+		return &domain.RbacDecision{Role: roles[0], Roles: roles}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/3/library", nil)
+	req.Header.Set("X-Client", "mobile")
+	req = req.WithContext(context.WithValue(context.Background(), domain.RbacUserId(), uint64(1)))
+	s.r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, 200, s.rr.Code)
+	assert.EqualValues(t, map[string]string{"id": "3"}, params)
+	assert.EqualValues(t, "mobile", headers.Get("x-client"))
+}
+
+func (s *AuthTestSuite) TestAuth_BodyInContextAndReadableByHandler() {
+	s.mockUserRoleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 1, UserID: 1, Name: "user"}}, nil
+	})
+	var body []byte
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		body, _ = ctx.Value(domain.RbacRequestBody()).([]byte)
+		return &domain.RbacDecision{Role: roles[0], Roles: roles}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/SyncGames", strings.NewReader(`{"userid": 1}`))
+	req = req.WithContext(context.WithValue(context.Background(), domain.RbacUserId(), uint64(1)))
+	s.r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, 200, s.rr.Code)
+	assert.EqualValues(t, `{"userid": 1}`, string(body))
+	assert.EqualValues(t, `{"userid": 1}`, s.rr.Body.String())
+}
+
+func (s *AuthTestSuite) TestAuth_BodyTooLarge() {
+	s.mockUserRoleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 1, UserID: 1, Name: "user"}}, nil
+	})
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		return &domain.RbacDecision{Role: roles[0], Roles: roles}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/SyncGames", strings.NewReader(strings.Repeat(" ", 1<<20+1)))
+	req = req.WithContext(context.WithValue(context.Background(), domain.RbacUserId(), uint64(1)))
+	s.r.ServeHTTP(s.rr, req)
+
+	assert.EqualValues(s.T(), http.StatusRequestEntityTooLarge, s.rr.Code)
+}
+
+func (s *AuthTestSuite) TestAuth_AllRolesAndDecisionInContext() {
+	s.mockUserRoleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 1, UserID: 1, Name: "User"}, {ID: 2, UserID: 1, Name: "Moderator"}}, nil
+	})
+	var receivedRoles []string
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		receivedRoles = roles
+		return &domain.RbacDecision{Role: "moderator", Roles: roles, Strategy: domain.RoleStrategyAnyAllow, Resource: resource, Endpoint: endpoint}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/sync-jobs/1", nil)
+	req = req.WithContext(context.WithValue(context.Background(), domain.RbacUserId(), uint64(1)))
+	s.r.ServeHTTP(s.rr, req)
+
+	t := s.T()
+	assert.EqualValues(t, 200, s.rr.Code)
+	assert.EqualValues(t, []string{"user", "moderator"}, receivedRoles)
+	assert.JSONEq(t, `{"role": "moderator", "roles": ["user", "moderator"], "strategy": "any-allow", "resource": "sync_job", "endpoint": "read"}`, s.rr.Body.String())
+}
+
+func (s *AuthTestSuite) TestAuth_DeclaredResourceAndAction() {
+	s.mockUserRoleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 1, UserID: 1, Name: "User"}}, nil
+	})
+	var receivedResource, receivedEndpoint string
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		receivedResource, receivedEndpoint = resource, endpoint
+		return &domain.RbacDecision{Role: roles[0], Roles: roles}, nil
+	})
+	t := s.T()
+
+	//the path mentions games, the route says what it is
+	req, _ := http.NewRequest(http.MethodGet, "/users/3/games", nil)
+	req = req.WithContext(context.WithValue(context.Background(), domain.RbacUserId(), uint64(1)))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(t, 200, s.rr.Code)
+	assert.EqualValues(t, "library", receivedResource)
+	assert.EqualValues(t, "read", receivedEndpoint)
+
+	s.rr = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/SyncGames", strings.NewReader(`{"userid": 1}`))
+	req = req.WithContext(context.WithValue(context.Background(), domain.RbacUserId(), uint64(1)))
+	s.r.ServeHTTP(s.rr, req)
+	assert.EqualValues(t, 200, s.rr.Code)
+	assert.EqualValues(t, "library", receivedResource)
+	assert.EqualValues(t, "sync", receivedEndpoint)
+}
+
+func (s *AuthTestSuite) TestAuth_ApiKeyScopes() {
+	s.mockUserRoleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 1, UserID: 1, Name: "Admin"}}, nil
+	})
+	s.mockAuthService.SetAuthorizeRoles(func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+		return &domain.RbacDecision{Role: roles[0], Roles: roles}, nil
+	})
+	tests := []struct {
+		scopes domain.ApiScopes
+		status int
+	}{
+		{nil, 200},
+		{domain.ApiScopes{"game"}, 200},
+		{domain.ApiScopes{"library", "game:read"}, 200},
+		{domain.ApiScopes{"library"}, 403},
+		{domain.ApiScopes{"game:update"}, 403},
+	}
+	for _, test := range tests {
+		rr := httptest.NewRecorder()
+		ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(1))
+		ctx = context.WithValue(ctx, domain.ApiClientKey(), &domain.ApiClient{KeyId: 4, Prefix: "gk_abcd", OwnerId: 1, Scopes: test.scopes})
+		req, _ := http.NewRequest(http.MethodGet, "/games", nil)
+		s.r.ServeHTTP(rr, req.WithContext(ctx))
+
+		assert.EqualValues(s.T(), test.status, rr.Code, test.scopes)
+		if test.status == 403 {
+			assert.Contains(s.T(), rr.Body.String(), "api key gk_abcd is not scoped for game.read", test.scopes)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"GamesAPI/src/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+type ClientIPTestSuite struct {
+	suite.Suite
+	r *gin.Engine
+}
+
+func TestClientIPTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientIPTestSuite))
+}
+
+func (s *ClientIPTestSuite) SetupSuite() {
+	s.r = gin.New()
+	middleware.InitClientIP(s.r)
+	s.r.GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+}
+
+func (s *ClientIPTestSuite) TearDownTest() {
+	middleware.TrustedProxies = nil
+}
+
+func (s *ClientIPTestSuite) clientIP(remoteAddr string, headers map[string]string) string {
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/ip", nil)
+	req.RemoteAddr = remoteAddr
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	s.r.ServeHTTP(rr, req)
+	return rr.Body.String()
+}
+
+func (s *ClientIPTestSuite) trust(proxies string) {
+	require.Nil(s.T(), os.Setenv("TRUSTED_PROXIES", proxies))
+	defer os.Unsetenv("TRUSTED_PROXIES")
+	trusted, err := middleware.TrustedProxiesFromEnv()
+	require.Nil(s.T(), err)
+	middleware.TrustedProxies = trusted
+}
+
+func (s *ClientIPTestSuite) TestForwardedHeadersIgnoredByDefault() {
+	ip := s.clientIP("192.0.2.1:4242", map[string]string{"X-Forwarded-For": "203.0.113.9", "X-Real-Ip": "203.0.113.10"})
+
+	assert.EqualValues(s.T(), "192.0.2.1", ip)
+}
+
+func (s *ClientIPTestSuite) TestForwardedByTrustedProxy() {
+	s.trust("10.0.0.0/8, 192.0.2.1")
+	t := s.T()
+
+	//the forged first hop is skipped, the proxy appended the address it got the request from
+	assert.EqualValues(t, "198.51.100.7", s.clientIP("10.0.0.2:80", map[string]string{"X-Forwarded-For": "203.0.113.9, 198.51.100.7, 10.0.0.3"}))
+	assert.EqualValues(t, "198.51.100.7", s.clientIP("192.0.2.1:80", map[string]string{"X-Real-Ip": "198.51.100.7"}))
+	assert.EqualValues(t, "192.0.2.2", s.clientIP("192.0.2.2:80", map[string]string{"X-Forwarded-For": "203.0.113.9"}))
+}
+
+func (s *ClientIPTestSuite) TestTrustedProxiesFromEnv_Invalid() {
+	require.Nil(s.T(), os.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,proxy.local"))
+	defer os.Unsetenv("TRUSTED_PROXIES")
+
+	_, err := middleware.TrustedProxiesFromEnv()
+	assert.EqualError(s.T(), err, "trusted proxy 'proxy.local' is not an IP or a CIDR range")
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/middleware"
+	"GamesAPI/src/services"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+	r *gin.Engine
+}
+
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
+
+//failingRateLimitStore stands in for a shared backend that is down
+type failingRateLimitStore struct{}
+
+func (f failingRateLimitStore) Take([]string, domain.RateLimit, time.Time) (services.RateLimitResult, error) {
+	return services.RateLimitResult{}, errors.New("connection refused")
+}
+
+func (f failingRateLimitStore) DeleteExpired(time.Time) int {
+	return 0
+}
+
+func (s *RateLimitTestSuite) SetupSuite() {
+	s.r = gin.Default()
+	//stands in for the API key and session handlers, ?user= authenticates the request
+	s.r.Use(func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), domain.ApiClientKey(), &domain.ApiClient{KeyId: 1})
+		if c.Query("user") != "" {
+			ctx = context.WithValue(ctx, domain.RbacUserId(), uint64(len(c.Query("user"))))
+		}
+		c.Request = c.Request.WithContext(ctx)
+	})
+	g := s.r.Group("")
+	middleware.InitRateLimit(g, "external")
+	g.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+}
+
+func (s *RateLimitTestSuite) TearDownTest() {
+	services.RateLimitService = services.NewRateLimitService(nil, services.NewMemoryRateLimitStore())
+}
+
+func (s *RateLimitTestSuite) get(url string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	s.r.ServeHTTP(rr, req)
+	return rr
+}
+
+func (s *RateLimitTestSuite) TestRateLimit_Headers() {
+	services.RateLimitService = services.NewRateLimitService(domain.RateLimits{
+		"external": {Requests: 2, Period: time.Minute},
+	}, services.NewMemoryRateLimitStore())
+
+	rr := s.get("/")
+	t := s.T()
+	assert.EqualValues(t, http.StatusOK, rr.Code)
+	assert.EqualValues(t, "2", rr.Header().Get("RateLimit-Limit"))
+	assert.EqualValues(t, "1", rr.Header().Get("RateLimit-Remaining"))
+	assert.EqualValues(t, "30", rr.Header().Get("RateLimit-Reset"))
+
+	s.get("/")
+	rr = s.get("/")
+	assert.EqualValues(t, http.StatusTooManyRequests, rr.Code)
+	assert.EqualValues(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	assert.EqualValues(t, "30", rr.Header().Get("Retry-After"))
+}
+
+func (s *RateLimitTestSuite) TestRateLimit_PerUser() {
+	services.RateLimitService = services.NewRateLimitService(domain.RateLimits{
+		"external": {Requests: 3, Period: time.Minute},
+	}, services.NewMemoryRateLimitStore())
+
+	//users share the key, the key runs out first
+	assert.EqualValues(s.T(), http.StatusOK, s.get("/?user=a").Code)
+	assert.EqualValues(s.T(), http.StatusOK, s.get("/?user=a").Code)
+	rr := s.get("/?user=bb")
+	assert.EqualValues(s.T(), http.StatusOK, rr.Code)
+	//the most restrictive bucket is the one reported
+	assert.EqualValues(s.T(), "0", rr.Header().Get("RateLimit-Remaining"))
+	assert.EqualValues(s.T(), http.StatusTooManyRequests, s.get("/?user=bb").Code)
+}
+
+func (s *RateLimitTestSuite) TestRateLimit_RefusedByUserKeepsKeyTokens() {
+	services.RateLimitService = services.NewRateLimitService(domain.RateLimits{
+		"external": {Requests: 3, Period: time.Minute},
+	}, services.NewMemoryRateLimitStore())
+	//user a is out before the key it shares with user bb
+	services.RateLimitService.Allow("external", []string{"user:1"}, time.Now())
+	services.RateLimitService.Allow("external", []string{"user:1"}, time.Now())
+
+	assert.EqualValues(s.T(), http.StatusOK, s.get("/?user=a").Code)
+	assert.EqualValues(s.T(), http.StatusTooManyRequests, s.get("/?user=a").Code)
+	assert.EqualValues(s.T(), http.StatusTooManyRequests, s.get("/?user=a").Code)
+	//the refused requests took nothing from the key
+	rr := s.get("/?user=bb")
+	assert.EqualValues(s.T(), http.StatusOK, rr.Code)
+	assert.EqualValues(s.T(), "1", rr.Header().Get("RateLimit-Remaining"))
+}
+
+func (s *RateLimitTestSuite) TestRateLimit_NotConfigured() {
+	rr := s.get("/")
+	assert.EqualValues(s.T(), http.StatusOK, rr.Code)
+	assert.Empty(s.T(), rr.Header().Get("RateLimit-Limit"))
+}
+
+func (s *RateLimitTestSuite) TestRateLimit_StoreDown() {
+	services.RateLimitService = services.NewRateLimitService(domain.RateLimits{
+		"external": {Requests: 1, Period: time.Minute},
+	}, failingRateLimitStore{})
+
+	assert.EqualValues(s.T(), http.StatusOK, s.get("/").Code)
+	assert.EqualValues(s.T(), http.StatusOK, s.get("/").Code)
+}
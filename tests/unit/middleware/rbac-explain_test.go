@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/middleware"
+	"GamesAPI/src/router"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func declareExplainRoutes() {
+	r := gin.New()
+	g := r.Group("/explain-test")
+	router.Rbac(g, "game").GET("/games/:id", BidonController)
+	router.Rbac(g, "game").Handle(http.MethodPost, "search", "/games/search", BidonController)
+	router.Rbac(g, "library").DELETE("/users/:id/library/:gameId", BidonController)
+	router.Rbac(g, "file").GET("/files/*path", BidonController)
+}
+
+func TestMatchRbacRoute(t *testing.T) {
+	declareExplainRoutes()
+	tests := []struct {
+		method     string
+		path       string
+		wantAction string
+		wantParams map[string]string
+	}{
+		{method: "GET", path: "/explain-test/games/12", wantAction: "read", wantParams: map[string]string{"id": "12"}},
+		{method: "POST", path: "/explain-test/games/search", wantAction: "search", wantParams: map[string]string{}},
+		{method: "DELETE", path: "/explain-test/users/3/library/7/", wantAction: "delete", wantParams: map[string]string{"id": "3", "gameId": "7"}},
+		{method: "GET", path: "/explain-test/files/a/b", wantAction: "read", wantParams: map[string]string{"path": "/a/b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			route, params, matched := middleware.MatchRbacRoute(tt.method, tt.path)
+			require.True(t, matched)
+			assert.EqualValues(t, tt.wantAction, route.Action)
+			assert.EqualValues(t, tt.wantParams, params)
+		})
+	}
+
+	for _, path := range []string{"/explain-test/games", "/explain-test/games/12/extra", "/explain-test/users/3/library"} {
+		_, _, matched := middleware.MatchRbacRoute("GET", path)
+		assert.False(t, matched, path)
+	}
+	_, _, matched := middleware.MatchRbacRoute("POST", "/explain-test/games/12")
+	assert.False(t, matched)
+}
+
+func TestExplainAuthorization(t *testing.T) {
+	declareExplainRoutes()
+	authorizationMock := &mocks.AuthorizationServiceMock{}
+	roleMock := &mocks.UserRoleMock{}
+	services.AuthorizationService = authorizationMock
+	services.UserRoleService = roleMock
+	roleMock.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{UserID: userId, Name: "User"}}, nil
+	})
+	var gotCtx context.Context
+	var gotUrl *url.URL
+	var gotRoles []string
+	authorizationMock.SetExplain(func(ctx context.Context, u *url.URL, roles []string, resource string, endpoint string) *domain.RbacTrace {
+		gotCtx, gotUrl, gotRoles = ctx, u, roles
+		return &domain.RbacTrace{Resource: resource, Endpoint: endpoint}
+	})
+
+	trace, err := middleware.ExplainAuthorization(middleware.RbacExplainRequest{
+		UserId:  3,
+		Method:  "delete",
+		Path:    "/explain-test/users/3/library/7?force=true",
+		Headers: http.Header{"X-Trace": []string{"on"}},
+		Body:    []byte(`{"userid": 3}`),
+	})
+	require.Nil(t, err)
+	assert.EqualValues(t, "library", trace.Resource)
+	assert.EqualValues(t, "delete", trace.Endpoint)
+	//the user's roles are looked up and lowercased, as for a request
+	assert.EqualValues(t, []string{"user"}, gotRoles)
+	assert.EqualValues(t, "true", gotUrl.Query().Get("force"))
+	assert.EqualValues(t, uint64(3), gotCtx.Value(domain.RbacUserId()))
+	assert.EqualValues(t, map[string]string{"id": "3", "gameId": "7"}, gotCtx.Value(domain.RbacRouteParams()))
+	assert.EqualValues(t, "on", gotCtx.Value(domain.RbacRequestHeaders()).(http.Header).Get("x-trace"))
+	assert.EqualValues(t, `{"userid": 3}`, gotCtx.Value(domain.RbacRequestBody()))
+
+	//roles given instead of the user's
+	_, err = middleware.ExplainAuthorization(middleware.RbacExplainRequest{Roles: []string{"Admin"}, Method: "GET", Path: "/explain-test/games/1"})
+	require.Nil(t, err)
+	assert.EqualValues(t, []string{"admin"}, gotRoles)
+
+	_, err = middleware.ExplainAuthorization(middleware.RbacExplainRequest{UserId: 3, Method: "GET", Path: "/explain-test/nowhere"})
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+
+	_, err = middleware.ExplainAuthorization(middleware.RbacExplainRequest{UserId: 3, Method: "GET", Path: "games/1"})
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, err.Status())
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/middleware"
+	"GamesAPI/src/router"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestRbacSurface(t *testing.T) {
+	r := gin.New()
+	r.POST("/auth/login", BidonController)
+	library := r.Group("/users/:id/library")
+	router.Rbac(library, "library").GET("", BidonController)
+	router.Rbac(library, "library").DELETE("/:gameId", BidonController)
+	router.Rbac(&r.RouterGroup, "library").Handle(http.MethodPost, "sync", "/SyncGames", BidonController)
+	router.Rbac(&r.RouterGroup, "game").PUT("/games/:id/", BidonController)
+
+	surface := middleware.RbacSurface(r.Routes())
+	assert.EqualValues(t, map[string]map[string]bool{
+		"library": {"read": true, "delete": true, "sync": true},
+		"game":    {"update": true},
+	}, surface.Resources)
+	assert.True(t, surface.ContextKeys["userId"])
+}
+
+//the shipped policy should cover every route, and nothing else
+func TestRbacSurface_ShippedPolicy(t *testing.T) {
+	r := gin.New()
+	router.InitAllRoutes(r)
+
+	issues, err := domain.LintRbacFile("../../../role-based-access.yml", middleware.RbacSurface(r.Routes()))
+	require.Nil(t, err)
+	assert.Empty(t, issues)
+}
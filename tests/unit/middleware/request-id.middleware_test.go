@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+type RequestIdTestSuite struct {
+	suite.Suite
+	r *gin.Engine
+}
+
+func TestRequestIdTestSuite(t *testing.T) {
+	suite.Run(t, new(RequestIdTestSuite))
+}
+
+func (s *RequestIdTestSuite) SetupSuite() {
+	s.r = gin.Default()
+	middleware.InitRequestId(s.r)
+	s.r.GET("/request-id", func(c *gin.Context) {
+		requestId, _ := c.Request.Context().Value(domain.RequestIdKey()).(string)
+		c.String(http.StatusOK, requestId)
+	})
+}
+
+func (s *RequestIdTestSuite) serve(requestId string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/request-id", nil)
+	if requestId != "" {
+		req.Header.Set(middleware.RequestIdHeader, requestId)
+	}
+	s.r.ServeHTTP(rr, req)
+	return rr
+}
+
+func (s *RequestIdTestSuite) TestKeepsClientId() {
+	rr := s.serve("trace-42.a:b")
+
+	assert.EqualValues(s.T(), "trace-42.a:b", rr.Body.String())
+	assert.EqualValues(s.T(), "trace-42.a:b", rr.Header().Get(middleware.RequestIdHeader))
+}
+
+func (s *RequestIdTestSuite) TestGeneratesId() {
+	generated := regexp.MustCompile(`^[0-9a-f]{32}$`)
+	for _, requestId := range []string{"", "has spaces", "<script>"} {
+		rr := s.serve(requestId)
+
+		assert.Regexp(s.T(), generated, rr.Body.String(), requestId)
+		assert.EqualValues(s.T(), rr.Body.String(), rr.Header().Get(middleware.RequestIdHeader), requestId)
+	}
+	assert.NotEqual(s.T(), s.serve("").Body.String(), s.serve("").Body.String())
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/middleware"
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestLogFormatter_ApiKey(t *testing.T) {
+	ctx := context.WithValue(context.Background(), domain.RequestIdKey(), "req-1")
+	ctx = context.WithValue(ctx, domain.RbacUserId(), uint64(3))
+	ctx = context.WithValue(ctx, domain.ApiClientKey(), &domain.ApiClient{KeyId: 4, Prefix: "gk_abcd", OwnerId: 3})
+	req, _ := http.NewRequest(http.MethodGet, "/games", nil)
+
+	line := middleware.RequestLogFormatter(gin.LogFormatterParams{Request: req.WithContext(ctx), StatusCode: 200, Method: http.MethodGet, Path: "/games"})
+
+	assert.Contains(t, line, "| req-1 | user 3 key 4 (gk_abcd)\n")
+}
+
+func TestRequestLogFormatter_Session(t *testing.T) {
+	ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+	req, _ := http.NewRequest(http.MethodGet, "/games", nil)
+
+	line := middleware.RequestLogFormatter(gin.LogFormatterParams{Request: req.WithContext(ctx), StatusCode: 200, Method: http.MethodGet, Path: "/games"})
+
+	assert.Contains(t, line, "| - | user 3\n")
+}
+
+func TestRequestLog_SeesContextOfLaterMiddlewares(t *testing.T) {
+	var logged bytes.Buffer
+	r := gin.New()
+	r.Use(gin.LoggerWithConfig(gin.LoggerConfig{Formatter: middleware.RequestLogFormatter, Output: &logged}))
+	middleware.InitRequestId(r)
+	r.GET("/games", func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), domain.ApiClientKey(), &domain.ApiClient{KeyId: 4, Prefix: "gk_abcd", OwnerId: 3})
+		c.Request = c.Request.WithContext(ctx)
+		c.Status(http.StatusNoContent)
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/games", nil)
+	req.Header.Set(middleware.RequestIdHeader, "req-2")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, logged.String(), "| req-2 | user 3 key 4 (gk_abcd)\n")
+}
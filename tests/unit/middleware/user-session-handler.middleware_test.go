@@ -4,14 +4,19 @@ import (
 	"GamesAPI/src/domain"
 	"GamesAPI/src/middleware"
 	"GamesAPI/src/services"
+	"GamesAPI/src/utils/authUtils"
 	"GamesAPI/src/utils/errorUtils"
 	"GamesAPI/tests/unit/mocks"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -21,6 +26,10 @@ type UserSessionHandlerTestSuite struct {
 	mockService mocks.UserSessionServiceMockInterface
 	r           *gin.Engine
 	rr          *httptest.ResponseRecorder
+	jwtService  services.JwtServiceInterface
+	//context values seen by the last request that went through
+	userId interface{}
+	roles  interface{}
 }
 
 func UserSessionBidonHandler(c *gin.Context) {
@@ -43,6 +52,21 @@ func (s *UserSessionHandlerTestSuite) SetupSuite() {
 	s.r = gin.Default()
 	s.r.Use(middleware.UserSessionHandler)
 	s.r.GET("/", UserSessionBidonHandler)
+	s.r.GET("/whoami", func(c *gin.Context) {
+		s.userId = c.Request.Context().Value(domain.RbacUserId())
+		s.roles = c.Request.Context().Value(domain.RbacUserRoles())
+		c.Status(http.StatusOK)
+	})
+
+	key, err := authUtils.NewJwtKey(authUtils.JwtAlgorithmHS256, "test", []byte("a-test-secret-that-is-long-enough-for-hs256"))
+	require.Nil(s.T(), err)
+	keys, err := authUtils.NewJwtKeySet("test", key)
+	require.Nil(s.T(), err)
+	s.jwtService = services.NewJwtService(keys)
+}
+
+func (s *UserSessionHandlerTestSuite) TearDownTest() {
+	services.JwtService = services.NewJwtService(nil)
 }
 
 func (s *UserSessionHandlerTestSuite) TestUserSessionHandler_NoAuthHeader() {
@@ -128,3 +152,61 @@ func (s *UserSessionHandlerTestSuite) TestUserSessionHandler_SessionIsNotExpired
 	s.r.ServeHTTP(s.rr, req)
 	assert.Equal(s.T(), http.StatusOK, s.rr.Code)
 }
+
+func (s *UserSessionHandlerTestSuite) TestUserSessionHandler_ValidJwt() {
+	services.JwtService = s.jwtService
+	s.mockService.SetExistsSession(func(key string) bool {
+		assert.Fail(s.T(), "a JWT should not be looked up in the session store")
+		return false
+	})
+	token, err := s.jwtService.IssueAccessToken(3, []string{"user"}, time.Now().Add(time.Minute))
+	require.Nil(s.T(), err)
+
+	req, _ := http.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Add("Authorization", "Bearer "+token)
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusOK, s.rr.Code)
+	assert.EqualValues(s.T(), uint64(3), s.userId)
+	assert.EqualValues(s.T(), []string{"user"}, s.roles)
+}
+
+func (s *UserSessionHandlerTestSuite) TestUserSessionHandler_TamperedJwt() {
+	services.JwtService = s.jwtService
+	token, err := s.jwtService.IssueAccessToken(3, []string{"user"}, time.Now().Add(time.Minute))
+	require.Nil(s.T(), err)
+	parts := strings.Split(token, ".")
+	forgedClaims := authUtils.UserClaims{UserId: 3, Roles: []string{"admin"}}
+	forgedClaims.Issuer = authUtils.JwtIssuer
+	forgedPayload, _ := json.Marshal(forgedClaims)
+	parts[1] = base64.RawURLEncoding.EncodeToString(forgedPayload)
+
+	req, _ := http.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Add("Authorization", "Bearer "+strings.Join(parts, "."))
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusUnauthorized, s.rr.Code)
+}
+
+func (s *UserSessionHandlerTestSuite) TestUserSessionHandler_ExpiredJwt() {
+	services.JwtService = s.jwtService
+	token, err := s.jwtService.IssueAccessToken(3, []string{"user"}, time.Now().Add(-time.Minute))
+	require.Nil(s.T(), err)
+
+	req, _ := http.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Add("Authorization", "Bearer "+token)
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusUnauthorized, s.rr.Code)
+}
+
+func (s *UserSessionHandlerTestSuite) TestUserSessionHandler_JwtDisabled() {
+	//with JWT authentication off, a JWT is just an unknown session token
+	token, err := s.jwtService.IssueAccessToken(3, []string{"user"}, time.Now().Add(time.Minute))
+	require.Nil(s.T(), err)
+	s.mockService.SetExistsSession(func(key string) bool {
+		return false
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Add("Authorization", "Bearer "+token)
+	s.r.ServeHTTP(s.rr, req)
+	assert.Equal(s.T(), http.StatusUnauthorized, s.rr.Code)
+}
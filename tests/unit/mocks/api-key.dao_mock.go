@@ -0,0 +1,80 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+	"time"
+)
+
+type ApiKeyRepoMockInterface interface {
+	SetGet(func(id uint64) (*domain.ApiKey, errorUtils.EntityError))
+	SetGetByPrefix(func(prefix string) (*domain.ApiKey, errorUtils.EntityError))
+	SetGetAll(func() ([]domain.ApiKey, errorUtils.EntityError))
+	SetGetByOwner(func(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError))
+	SetCreate(func(key *domain.ApiKey) (*domain.ApiKey, errorUtils.EntityError))
+	SetRevoke(func(id uint64, at time.Time) errorUtils.EntityError)
+	SetTouchLastUsed(func(id uint64, at time.Time) errorUtils.EntityError)
+}
+
+type ApiKeyRepoMock struct {
+	get           func(id uint64) (*domain.ApiKey, errorUtils.EntityError)
+	getByPrefix   func(prefix string) (*domain.ApiKey, errorUtils.EntityError)
+	getAll        func() ([]domain.ApiKey, errorUtils.EntityError)
+	getByOwner    func(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError)
+	create        func(key *domain.ApiKey) (*domain.ApiKey, errorUtils.EntityError)
+	revoke        func(id uint64, at time.Time) errorUtils.EntityError
+	touchLastUsed func(id uint64, at time.Time) errorUtils.EntityError
+}
+
+func (m *ApiKeyRepoMock) SetGet(f func(id uint64) (*domain.ApiKey, errorUtils.EntityError)) {
+	m.get = f
+}
+
+func (m *ApiKeyRepoMock) SetGetByPrefix(f func(prefix string) (*domain.ApiKey, errorUtils.EntityError)) {
+	m.getByPrefix = f
+}
+
+func (m *ApiKeyRepoMock) SetGetAll(f func() ([]domain.ApiKey, errorUtils.EntityError)) {
+	m.getAll = f
+}
+
+func (m *ApiKeyRepoMock) SetGetByOwner(f func(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError)) {
+	m.getByOwner = f
+}
+
+func (m *ApiKeyRepoMock) SetCreate(f func(key *domain.ApiKey) (*domain.ApiKey, errorUtils.EntityError)) {
+	m.create = f
+}
+
+func (m *ApiKeyRepoMock) SetRevoke(f func(id uint64, at time.Time) errorUtils.EntityError) {
+	m.revoke = f
+}
+
+func (m *ApiKeyRepoMock) SetTouchLastUsed(f func(id uint64, at time.Time) errorUtils.EntityError) {
+	m.touchLastUsed = f
+}
+
+//ApiKeyRepoInterface implementation (redirects all calls to the swappable methods)
+func (m *ApiKeyRepoMock) Get(id uint64) (*domain.ApiKey, errorUtils.EntityError) {
+	return m.get(id)
+}
+func (m *ApiKeyRepoMock) GetByPrefix(prefix string) (*domain.ApiKey, errorUtils.EntityError) {
+	return m.getByPrefix(prefix)
+}
+func (m *ApiKeyRepoMock) GetAll() ([]domain.ApiKey, errorUtils.EntityError) {
+	return m.getAll()
+}
+func (m *ApiKeyRepoMock) GetByOwner(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError) {
+	return m.getByOwner(ownerId)
+}
+func (m *ApiKeyRepoMock) Create(key *domain.ApiKey) (*domain.ApiKey, errorUtils.EntityError) {
+	return m.create(key)
+}
+func (m *ApiKeyRepoMock) Revoke(id uint64, at time.Time) errorUtils.EntityError {
+	return m.revoke(id, at)
+}
+func (m *ApiKeyRepoMock) TouchLastUsed(id uint64, at time.Time) errorUtils.EntityError {
+	return m.touchLastUsed(id, at)
+}
+func (m *ApiKeyRepoMock) Initialize(_ *gorm.DB) {}
@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+)
+
+type ApiKeyServiceMockInterface interface {
+	SetIssueKey(f func(key *domain.ApiKey) (string, *domain.ApiKey, errorUtils.EntityError))
+	SetGetKeys(f func(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError))
+	SetRevokeKey(f func(id uint64) errorUtils.EntityError)
+	SetAuthenticate(f func(rawKey string) (*domain.ApiKey, errorUtils.EntityError))
+}
+
+type ApiKeyServiceMock struct {
+	issueKey     func(key *domain.ApiKey) (string, *domain.ApiKey, errorUtils.EntityError)
+	getKeys      func(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError)
+	revokeKey    func(id uint64) errorUtils.EntityError
+	authenticate func(rawKey string) (*domain.ApiKey, errorUtils.EntityError)
+}
+
+func (m *ApiKeyServiceMock) IssueKey(key *domain.ApiKey) (string, *domain.ApiKey, errorUtils.EntityError) {
+	return m.issueKey(key)
+}
+
+func (m *ApiKeyServiceMock) GetKeys(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError) {
+	return m.getKeys(ownerId)
+}
+
+func (m *ApiKeyServiceMock) RevokeKey(id uint64) errorUtils.EntityError {
+	return m.revokeKey(id)
+}
+
+func (m *ApiKeyServiceMock) Authenticate(rawKey string) (*domain.ApiKey, errorUtils.EntityError) {
+	return m.authenticate(rawKey)
+}
+
+func (m *ApiKeyServiceMock) SetIssueKey(f func(key *domain.ApiKey) (string, *domain.ApiKey, errorUtils.EntityError)) {
+	m.issueKey = f
+}
+
+func (m *ApiKeyServiceMock) SetGetKeys(f func(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError)) {
+	m.getKeys = f
+}
+
+func (m *ApiKeyServiceMock) SetRevokeKey(f func(id uint64) errorUtils.EntityError) {
+	m.revokeKey = f
+}
+
+func (m *ApiKeyServiceMock) SetAuthenticate(f func(rawKey string) (*domain.ApiKey, errorUtils.EntityError)) {
+	m.authenticate = f
+}
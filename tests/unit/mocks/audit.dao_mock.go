@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+)
+
+type AuditRepoMockInterface interface {
+	SetAppend(func(entry *domain.AuditEntry) (*domain.AuditEntry, errorUtils.EntityError))
+	SetFind(func(query *domain.AuditQuery) ([]domain.AuditEntry, uint64, errorUtils.EntityError))
+}
+
+type AuditRepoMock struct {
+	append func(entry *domain.AuditEntry) (*domain.AuditEntry, errorUtils.EntityError)
+	find   func(query *domain.AuditQuery) ([]domain.AuditEntry, uint64, errorUtils.EntityError)
+}
+
+//AuditRepoMockInterface implementation, so we can swap the methods around and get the desired behavior from the repository
+func (m *AuditRepoMock) SetAppend(f func(entry *domain.AuditEntry) (*domain.AuditEntry, errorUtils.EntityError)) {
+	m.append = f
+}
+
+func (m *AuditRepoMock) SetFind(f func(query *domain.AuditQuery) ([]domain.AuditEntry, uint64, errorUtils.EntityError)) {
+	m.find = f
+}
+
+func (m *AuditRepoMock) Append(entry *domain.AuditEntry) (*domain.AuditEntry, errorUtils.EntityError) {
+	return m.append(entry)
+}
+
+func (m *AuditRepoMock) Find(query *domain.AuditQuery) ([]domain.AuditEntry, uint64, errorUtils.EntityError) {
+	return m.find(query)
+}
+
+func (m *AuditRepoMock) Initialize(db *gorm.DB) {}
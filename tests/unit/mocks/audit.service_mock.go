@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"context"
+	"io"
+)
+
+type AuditServiceMockInterface interface {
+	SetRecord(func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError)
+	SetGetEntries(func(query *domain.AuditQuery) (*domain.AuditPage, errorUtils.EntityError))
+	SetExport(func(query *domain.AuditQuery, w io.Writer) errorUtils.EntityError)
+}
+
+type AuditServiceMock struct {
+	record     func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError
+	getEntries func(query *domain.AuditQuery) (*domain.AuditPage, errorUtils.EntityError)
+	export     func(query *domain.AuditQuery, w io.Writer) errorUtils.EntityError
+}
+
+func (m *AuditServiceMock) SetRecord(f func(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError) {
+	m.record = f
+}
+
+func (m *AuditServiceMock) Record(ctx context.Context, resource string, action string, entityId uint64, before interface{}, after interface{}) errorUtils.EntityError {
+	return m.record(ctx, resource, action, entityId, before, after)
+}
+
+func (m *AuditServiceMock) SetGetEntries(f func(query *domain.AuditQuery) (*domain.AuditPage, errorUtils.EntityError)) {
+	m.getEntries = f
+}
+
+func (m *AuditServiceMock) GetEntries(query *domain.AuditQuery) (*domain.AuditPage, errorUtils.EntityError) {
+	return m.getEntries(query)
+}
+
+func (m *AuditServiceMock) SetExport(f func(query *domain.AuditQuery, w io.Writer) errorUtils.EntityError) {
+	m.export = f
+}
+
+func (m *AuditServiceMock) Export(query *domain.AuditQuery, w io.Writer) errorUtils.EntityError {
+	return m.export(query, w)
+}
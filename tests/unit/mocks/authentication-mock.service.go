@@ -1,17 +1,32 @@
 package mocks
 
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+)
+
 type AuthenticationServiceMockInterface interface {
 	SetValidatePassword(f func(plainPassword []byte, hashedPassword string) (bool, error))
+	SetAuthenticate(f func(email string, password []byte, ip string) (*domain.User, errorUtils.EntityError))
 }
 
 type AuthenticationServiceMock struct {
 	validatePassword func(plainPassword []byte, hashedPassword string) (bool, error)
+	authenticate     func(email string, password []byte, ip string) (*domain.User, errorUtils.EntityError)
 }
 
 func (m *AuthenticationServiceMock) SetValidatePassword(f func(plainPassword []byte, hashedPassword string) (bool, error)) {
 	m.validatePassword = f
 }
 
+func (m *AuthenticationServiceMock) SetAuthenticate(f func(email string, password []byte, ip string) (*domain.User, errorUtils.EntityError)) {
+	m.authenticate = f
+}
+
 func (m *AuthenticationServiceMock) ValidatePassword(plainPassword []byte, hashedPassword string) (bool, error) {
 	return m.validatePassword(plainPassword, hashedPassword)
 }
+
+func (m *AuthenticationServiceMock) Authenticate(email string, password []byte, ip string) (*domain.User, errorUtils.EntityError) {
+	return m.authenticate(email, password, ip)
+}
@@ -2,16 +2,26 @@ package mocks
 
 import (
 	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
 	"context"
 	"net/url"
+	"time"
 )
 
 type AuthorizationServiceMockInterface interface {
 	SetAuthorize(f func(ctx context.Context, url *url.URL, role string, resource string, endpoint string) error)
+	SetAuthorizeRoles(f func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error))
+	SetExplain(f func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) *domain.RbacTrace)
+	SetReload(f func() error)
+	SetPolicyInfo(f func() services.RbacPolicyInfo)
 }
 
 type AuthorizationServiceMock struct {
-	authorize func(ctx context.Context, url *url.URL, role string, resource string, endpoint string) error
+	authorize      func(ctx context.Context, url *url.URL, role string, resource string, endpoint string) error
+	authorizeRoles func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error)
+	explain        func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) *domain.RbacTrace
+	reload         func() error
+	policyInfo     func() services.RbacPolicyInfo
 }
 
 func (s *AuthorizationServiceMock) GetRbac() domain.RBAC {
@@ -25,3 +35,39 @@ func (s *AuthorizationServiceMock) SetAuthorize(f func(ctx context.Context, url
 func (s *AuthorizationServiceMock) Authorize(ctx context.Context, url *url.URL, role string, resource string, endpoint string) error {
 	return s.authorize(ctx, url, role, resource, endpoint)
 }
+
+func (s *AuthorizationServiceMock) SetAuthorizeRoles(f func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error)) {
+	s.authorizeRoles = f
+}
+
+func (s *AuthorizationServiceMock) AuthorizeRoles(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) (*domain.RbacDecision, error) {
+	return s.authorizeRoles(ctx, url, roles, resource, endpoint)
+}
+
+func (s *AuthorizationServiceMock) SetExplain(f func(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) *domain.RbacTrace) {
+	s.explain = f
+}
+
+func (s *AuthorizationServiceMock) Explain(ctx context.Context, url *url.URL, roles []string, resource string, endpoint string) *domain.RbacTrace {
+	return s.explain(ctx, url, roles, resource, endpoint)
+}
+
+func (s *AuthorizationServiceMock) SetReload(f func() error) {
+	s.reload = f
+}
+
+func (s *AuthorizationServiceMock) Reload() error {
+	return s.reload()
+}
+
+func (s *AuthorizationServiceMock) SetPolicyInfo(f func() services.RbacPolicyInfo) {
+	s.policyInfo = f
+}
+
+func (s *AuthorizationServiceMock) PolicyInfo() services.RbacPolicyInfo {
+	return s.policyInfo()
+}
+
+func (s *AuthorizationServiceMock) Watch(time.Duration) (stop func()) {
+	return func() {}
+}
@@ -11,7 +11,7 @@ type GameRepoMockInterface interface {
 	SetCreateGameDomain(func(game *domain.Game) (*domain.Game, errorUtils.EntityError))
 	SetUpdateGameDomain(func(game *domain.Game) (*domain.Game, errorUtils.EntityError))
 	SetDeleteGameDomain(func(id uint64) errorUtils.EntityError)
-	SetGetAllGameDomain(func() ([]domain.Game, errorUtils.EntityError))
+	SetGetAllGameDomain(func(query *domain.GameQuery) ([]domain.Game, uint64, errorUtils.EntityError))
 }
 
 type GameRepoMock struct {
@@ -19,7 +19,7 @@ type GameRepoMock struct {
 	createGameDomain  func(game *domain.Game) (*domain.Game, errorUtils.EntityError)
 	updateGameDomain  func(game *domain.Game) (*domain.Game, errorUtils.EntityError)
 	deleteGameDomain  func(id uint64) errorUtils.EntityError
-	getAllGamesDomain func() ([]domain.Game, errorUtils.EntityError)
+	getAllGamesDomain func(query *domain.GameQuery) ([]domain.Game, uint64, errorUtils.EntityError)
 }
 
 //GameRepoMockInterface implementation, so we can swap the methods around and get the desired behavior from the repository
@@ -39,7 +39,7 @@ func (m *GameRepoMock) SetDeleteGameDomain(f func(id uint64) errorUtils.EntityEr
 	m.deleteGameDomain = f
 }
 
-func (m *GameRepoMock) SetGetAllGameDomain(f func() ([]domain.Game, errorUtils.EntityError)) {
+func (m *GameRepoMock) SetGetAllGameDomain(f func(query *domain.GameQuery) ([]domain.Game, uint64, errorUtils.EntityError)) {
 	m.getAllGamesDomain = f
 }
 
@@ -56,7 +56,7 @@ func (m *GameRepoMock) Update(msg *domain.Game) (*domain.Game, errorUtils.Entity
 func (m *GameRepoMock) Delete(id uint64) errorUtils.EntityError {
 	return m.deleteGameDomain(id)
 }
-func (m *GameRepoMock) GetAll() ([]domain.Game, errorUtils.EntityError) {
-	return m.getAllGamesDomain()
+func (m *GameRepoMock) GetAll(query *domain.GameQuery) ([]domain.Game, uint64, errorUtils.EntityError) {
+	return m.getAllGamesDomain(query)
 }
 func (m *GameRepoMock) Initialize(_ *gorm.DB) {}
@@ -10,7 +10,9 @@ type GameServiceMockInterface interface {
 	SetCreateGame(func(*domain.Game) (*domain.Game, errorUtils.EntityError))
 	SetUpdateGame(func(*domain.Game) (*domain.Game, errorUtils.EntityError))
 	SetDelete(func(uint64) errorUtils.EntityError)
-	SetGetAll(func() ([]domain.Game, errorUtils.EntityError))
+	SetGetAll(func(*domain.GameQuery) (*domain.GamePage, errorUtils.EntityError))
+	SetExistsWithSteamID(func(string) (bool, errorUtils.EntityError))
+	SetGetGameBySteamID(func(string) (*domain.Game, errorUtils.EntityError))
 }
 
 type GameServiceMock struct {
@@ -18,14 +20,19 @@ type GameServiceMock struct {
 	createGameService func(*domain.Game) (*domain.Game, errorUtils.EntityError)
 	updateGameService func(*domain.Game) (*domain.Game, errorUtils.EntityError)
 	deleteGameService func(uint64) errorUtils.EntityError
-	getAllGameService func() ([]domain.Game, errorUtils.EntityError)
+	getAllGameService func(*domain.GameQuery) (*domain.GamePage, errorUtils.EntityError)
 	existsWithSteamId func(string) (bool, errorUtils.EntityError)
+	getGameBySteamId  func(string) (*domain.Game, errorUtils.EntityError)
 }
 
 func (u *GameServiceMock) ExistsWithSteamID(id string) (bool, errorUtils.EntityError) {
 	return u.existsWithSteamId(id)
 }
 
+func (u *GameServiceMock) GetGameBySteamID(id string) (*domain.Game, errorUtils.EntityError) {
+	return u.getGameBySteamId(id)
+}
+
 func (u *GameServiceMock) GetGame(id uint64) (*domain.Game, errorUtils.EntityError) {
 	return u.getGameService(id)
 }
@@ -42,8 +49,8 @@ func (u *GameServiceMock) DeleteGame(id uint64) errorUtils.EntityError {
 	return u.deleteGameService(id)
 }
 
-func (u *GameServiceMock) GetAllGames() ([]domain.Game, errorUtils.EntityError) {
-	return u.getAllGameService()
+func (u *GameServiceMock) GetAllGames(query *domain.GameQuery) (*domain.GamePage, errorUtils.EntityError) {
+	return u.getAllGameService(query)
 }
 
 func (u *GameServiceMock) SetGetGame(f func(uint64) (*domain.Game, errorUtils.EntityError)) {
@@ -62,6 +69,14 @@ func (u *GameServiceMock) SetDelete(f func(uint64) errorUtils.EntityError) {
 	u.deleteGameService = f
 }
 
-func (u *GameServiceMock) SetGetAll(f func() ([]domain.Game, errorUtils.EntityError)) {
+func (u *GameServiceMock) SetGetAll(f func(*domain.GameQuery) (*domain.GamePage, errorUtils.EntityError)) {
 	u.getAllGameService = f
 }
+
+func (u *GameServiceMock) SetExistsWithSteamID(f func(string) (bool, errorUtils.EntityError)) {
+	u.existsWithSteamId = f
+}
+
+func (u *GameServiceMock) SetGetGameBySteamID(f func(string) (*domain.Game, errorUtils.EntityError)) {
+	u.getGameBySteamId = f
+}
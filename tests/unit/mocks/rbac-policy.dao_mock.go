@@ -0,0 +1,66 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+)
+
+type RbacPolicyRepoMockInterface interface {
+	SetGetRoles(func() ([]domain.RbacRole, errorUtils.EntityError))
+	SetGetLatestVersion(func() (*domain.RbacPolicyVersion, errorUtils.EntityError))
+	SetGetVersions(func() ([]domain.RbacPolicyVersion, errorUtils.EntityError))
+	SetGetVersion(func(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError))
+	SetSave(func(roles []domain.RbacRole, version *domain.RbacPolicyVersion) errorUtils.EntityError)
+}
+
+type RbacPolicyRepoMock struct {
+	getRoles         func() ([]domain.RbacRole, errorUtils.EntityError)
+	getLatestVersion func() (*domain.RbacPolicyVersion, errorUtils.EntityError)
+	getVersions      func() ([]domain.RbacPolicyVersion, errorUtils.EntityError)
+	getVersion       func(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError)
+	save             func(roles []domain.RbacRole, version *domain.RbacPolicyVersion) errorUtils.EntityError
+}
+
+func (m *RbacPolicyRepoMock) SetGetRoles(f func() ([]domain.RbacRole, errorUtils.EntityError)) {
+	m.getRoles = f
+}
+
+func (m *RbacPolicyRepoMock) SetGetLatestVersion(f func() (*domain.RbacPolicyVersion, errorUtils.EntityError)) {
+	m.getLatestVersion = f
+}
+
+func (m *RbacPolicyRepoMock) SetGetVersions(f func() ([]domain.RbacPolicyVersion, errorUtils.EntityError)) {
+	m.getVersions = f
+}
+
+func (m *RbacPolicyRepoMock) SetGetVersion(f func(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError)) {
+	m.getVersion = f
+}
+
+func (m *RbacPolicyRepoMock) SetSave(f func(roles []domain.RbacRole, version *domain.RbacPolicyVersion) errorUtils.EntityError) {
+	m.save = f
+}
+
+//RbacPolicyRepoInterface implementation (redirects all calls to the swappable methods)
+func (m *RbacPolicyRepoMock) GetRoles() ([]domain.RbacRole, errorUtils.EntityError) {
+	return m.getRoles()
+}
+
+func (m *RbacPolicyRepoMock) GetLatestVersion() (*domain.RbacPolicyVersion, errorUtils.EntityError) {
+	return m.getLatestVersion()
+}
+
+func (m *RbacPolicyRepoMock) GetVersions() ([]domain.RbacPolicyVersion, errorUtils.EntityError) {
+	return m.getVersions()
+}
+
+func (m *RbacPolicyRepoMock) GetVersion(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError) {
+	return m.getVersion(version)
+}
+
+func (m *RbacPolicyRepoMock) Save(roles []domain.RbacRole, version *domain.RbacPolicyVersion) errorUtils.EntityError {
+	return m.save(roles, version)
+}
+
+func (m *RbacPolicyRepoMock) Initialize(*gorm.DB) {}
@@ -0,0 +1,99 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+)
+
+type RbacPolicyServiceMockInterface interface {
+	SetGetPolicy(func() (domain.RbacPolicy, errorUtils.EntityError))
+	SetPutRole(func(name string, role domain.RbacPolicyRole, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError))
+	SetDeleteRole(func(name string, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError))
+	SetPutPermission(func(role string, resource string, action string, permission domain.Permission, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError))
+	SetDeletePermission(func(role string, resource string, action string, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError))
+	SetGetVersions(func() ([]domain.RbacPolicyVersion, errorUtils.EntityError))
+	SetGetVersion(func(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError))
+	SetRollback(func(version uint64, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError))
+}
+
+type RbacPolicyServiceMock struct {
+	getPolicy        func() (domain.RbacPolicy, errorUtils.EntityError)
+	putRole          func(name string, role domain.RbacPolicyRole, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)
+	deleteRole       func(name string, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)
+	putPermission    func(role string, resource string, action string, permission domain.Permission, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)
+	deletePermission func(role string, resource string, action string, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)
+	getVersions      func() ([]domain.RbacPolicyVersion, errorUtils.EntityError)
+	getVersion       func(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError)
+	rollback         func(version uint64, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)
+}
+
+func (m *RbacPolicyServiceMock) SetGetPolicy(f func() (domain.RbacPolicy, errorUtils.EntityError)) {
+	m.getPolicy = f
+}
+
+func (m *RbacPolicyServiceMock) SetPutRole(f func(name string, role domain.RbacPolicyRole, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)) {
+	m.putRole = f
+}
+
+func (m *RbacPolicyServiceMock) SetDeleteRole(f func(name string, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)) {
+	m.deleteRole = f
+}
+
+func (m *RbacPolicyServiceMock) SetPutPermission(f func(role string, resource string, action string, permission domain.Permission, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)) {
+	m.putPermission = f
+}
+
+func (m *RbacPolicyServiceMock) SetDeletePermission(f func(role string, resource string, action string, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)) {
+	m.deletePermission = f
+}
+
+func (m *RbacPolicyServiceMock) SetGetVersions(f func() ([]domain.RbacPolicyVersion, errorUtils.EntityError)) {
+	m.getVersions = f
+}
+
+func (m *RbacPolicyServiceMock) SetGetVersion(f func(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError)) {
+	m.getVersion = f
+}
+
+func (m *RbacPolicyServiceMock) SetRollback(f func(version uint64, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError)) {
+	m.rollback = f
+}
+
+//RbacPolicyServiceInterface implementation (redirects all calls to the swappable methods)
+func (m *RbacPolicyServiceMock) GetPolicy() (domain.RbacPolicy, errorUtils.EntityError) {
+	return m.getPolicy()
+}
+
+func (m *RbacPolicyServiceMock) PutRole(name string, role domain.RbacPolicyRole, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError) {
+	return m.putRole(name, role, authorId)
+}
+
+func (m *RbacPolicyServiceMock) DeleteRole(name string, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError) {
+	return m.deleteRole(name, authorId)
+}
+
+func (m *RbacPolicyServiceMock) PutPermission(role string, resource string, action string, permission domain.Permission, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError) {
+	return m.putPermission(role, resource, action, permission, authorId)
+}
+
+func (m *RbacPolicyServiceMock) DeletePermission(role string, resource string, action string, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError) {
+	return m.deletePermission(role, resource, action, authorId)
+}
+
+func (m *RbacPolicyServiceMock) GetVersions() ([]domain.RbacPolicyVersion, errorUtils.EntityError) {
+	return m.getVersions()
+}
+
+func (m *RbacPolicyServiceMock) GetVersion(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError) {
+	return m.getVersion(version)
+}
+
+func (m *RbacPolicyServiceMock) Rollback(version uint64, authorId uint64) (*services.RbacPolicyUpdate, errorUtils.EntityError) {
+	return m.rollback(version, authorId)
+}
+
+//the policy file is only imported at startup
+func (m *RbacPolicyServiceMock) Import(string) errorUtils.EntityError {
+	return nil
+}
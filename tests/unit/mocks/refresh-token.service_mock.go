@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+)
+
+type RefreshTokenServiceMockInterface interface {
+	SetIssueTokens(f func(userId uint64) (*domain.TokenPair, errorUtils.EntityError))
+	SetRefresh(f func(refreshToken string) (*domain.TokenPair, errorUtils.EntityError))
+	SetRevokeFamily(f func(familyId string) errorUtils.EntityError)
+	SetLogout(f func(userId uint64, accessToken string, refreshToken string) errorUtils.EntityError)
+}
+
+type RefreshTokenServiceMock struct {
+	issueTokens  func(userId uint64) (*domain.TokenPair, errorUtils.EntityError)
+	refresh      func(refreshToken string) (*domain.TokenPair, errorUtils.EntityError)
+	revokeFamily func(familyId string) errorUtils.EntityError
+	logout       func(userId uint64, accessToken string, refreshToken string) errorUtils.EntityError
+}
+
+func (m *RefreshTokenServiceMock) IssueTokens(userId uint64) (*domain.TokenPair, errorUtils.EntityError) {
+	return m.issueTokens(userId)
+}
+
+func (m *RefreshTokenServiceMock) Refresh(refreshToken string) (*domain.TokenPair, errorUtils.EntityError) {
+	return m.refresh(refreshToken)
+}
+
+func (m *RefreshTokenServiceMock) RevokeFamily(familyId string) errorUtils.EntityError {
+	return m.revokeFamily(familyId)
+}
+
+func (m *RefreshTokenServiceMock) SetIssueTokens(f func(userId uint64) (*domain.TokenPair, errorUtils.EntityError)) {
+	m.issueTokens = f
+}
+
+func (m *RefreshTokenServiceMock) SetRefresh(f func(refreshToken string) (*domain.TokenPair, errorUtils.EntityError)) {
+	m.refresh = f
+}
+
+func (m *RefreshTokenServiceMock) SetRevokeFamily(f func(familyId string) errorUtils.EntityError) {
+	m.revokeFamily = f
+}
+
+func (m *RefreshTokenServiceMock) Logout(userId uint64, accessToken string, refreshToken string) errorUtils.EntityError {
+	return m.logout(userId, accessToken, refreshToken)
+}
+
+func (m *RefreshTokenServiceMock) SetLogout(f func(userId uint64, accessToken string, refreshToken string) errorUtils.EntityError) {
+	m.logout = f
+}
@@ -1,36 +1,39 @@
 package mocks
 
-import "GamesAPI/src/domain"
+import (
+	"GamesAPI/src/External/Steam"
+	"GamesAPI/src/domain"
+	"context"
+)
 
 type SteamUserMockInterface interface{
 	SetGetUserID(func(string) (string, error))
-	SetGetUserOwnedGames(func(string) ([]string, error))
+	SetGetUserOwnedGames(func(string) ([]Steam.OwnedGame, error))
 	SetGetGameInfo(func(string)(domain.Game, error))
 }
 
 type SteamUserMock struct {
 	getUserID         func(string) (string, error)
-	getUserOwnedGames func(string) ([]string, error)
+	getUserOwnedGames func(string) ([]Steam.OwnedGame, error)
 	getGameInfo func(string) (domain.Game, error)
 }
 
-func (s *SteamUserMock) GetUserID(personalURL string) (string, error) {
+func (s *SteamUserMock) GetUserID(ctx context.Context, personalURL string) (string, error) {
 	return s.getUserID(personalURL)
 }
 
-func (s *SteamUserMock) GetUserOwnedGames(userID string) ([]string, error) {
+func (s *SteamUserMock) GetUserOwnedGames(ctx context.Context, userID string) ([]Steam.OwnedGame, error) {
 	return s.getUserOwnedGames(userID)
 }
 
-func (s *SteamUserMock) GetGameInfo(gameID string)(domain.Game, error){
+func (s *SteamUserMock) GetGameInfo(ctx context.Context, gameID string)(domain.Game, error){
 	return s.getGameInfo(gameID)
 }
-
 func (s *SteamUserMock) SetGetUserID(f func(string) (string, error)) {
 	s.getUserID = f
 }
 
-func (s *SteamUserMock) SetGetUserOwnedGames(f func(string) ([]string, error)) {
+func (s *SteamUserMock) SetGetUserOwnedGames(f func(string) ([]Steam.OwnedGame, error)) {
 	s.getUserOwnedGames = f
 }
 
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+)
+
+type SyncJobRepoMockInterface interface {
+	SetGet(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError))
+	SetGetByInstance(func(instance string, statuses ...string) ([]domain.SyncJob, errorUtils.EntityError))
+	SetCreate(func(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError))
+	SetUpdate(func(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError))
+	SetAddError(func(jobError *domain.SyncJobError) errorUtils.EntityError)
+}
+
+type SyncJobRepoMock struct {
+	get         func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError)
+	getByInstance func(instance string, statuses ...string) ([]domain.SyncJob, errorUtils.EntityError)
+	create        func(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError)
+	update        func(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError)
+	addError      func(jobError *domain.SyncJobError) errorUtils.EntityError
+}
+
+//SyncJobRepoMockInterface implementation, so we can swap the methods around and get the desired behavior from the repository
+func (m *SyncJobRepoMock) SetGet(f func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError)) {
+	m.get = f
+}
+
+func (m *SyncJobRepoMock) SetGetByInstance(f func(instance string, statuses ...string) ([]domain.SyncJob, errorUtils.EntityError)) {
+	m.getByInstance = f
+}
+
+func (m *SyncJobRepoMock) SetCreate(f func(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError)) {
+	m.create = f
+}
+
+func (m *SyncJobRepoMock) SetUpdate(f func(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError)) {
+	m.update = f
+}
+
+func (m *SyncJobRepoMock) SetAddError(f func(jobError *domain.SyncJobError) errorUtils.EntityError) {
+	m.addError = f
+}
+
+//SyncJobRepoInterface implementation (redirects all calls to the swappable methods)
+func (m *SyncJobRepoMock) Get(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+	return m.get(jobId)
+}
+func (m *SyncJobRepoMock) GetByInstance(instance string, statuses ...string) ([]domain.SyncJob, errorUtils.EntityError) {
+	return m.getByInstance(instance, statuses...)
+}
+func (m *SyncJobRepoMock) Create(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError) {
+	return m.create(job)
+}
+func (m *SyncJobRepoMock) Update(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError) {
+	return m.update(job)
+}
+func (m *SyncJobRepoMock) AddError(jobError *domain.SyncJobError) errorUtils.EntityError {
+	return m.addError(jobError)
+}
+func (m *SyncJobRepoMock) Initialize(db *gorm.DB) {}
@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+)
+
+type SyncJobsServiceMockInterface interface {
+	SetEnqueueSync(func(user *domain.User) (*domain.SyncJob, errorUtils.EntityError))
+	SetGetJob(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError))
+	SetCancelJob(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError))
+}
+
+type SyncJobsServiceMock struct {
+	enqueueSync func(user *domain.User) (*domain.SyncJob, errorUtils.EntityError)
+	getJob      func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError)
+	cancelJob   func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError)
+}
+
+func (m *SyncJobsServiceMock) Start(workers int) {}
+
+func (m *SyncJobsServiceMock) Stop() {}
+
+func (m *SyncJobsServiceMock) EnqueueSync(user *domain.User) (*domain.SyncJob, errorUtils.EntityError) {
+	return m.enqueueSync(user)
+}
+
+func (m *SyncJobsServiceMock) GetJob(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+	return m.getJob(jobId)
+}
+
+func (m *SyncJobsServiceMock) CancelJob(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+	return m.cancelJob(jobId)
+}
+
+func (m *SyncJobsServiceMock) SetEnqueueSync(f func(user *domain.User) (*domain.SyncJob, errorUtils.EntityError)) {
+	m.enqueueSync = f
+}
+
+func (m *SyncJobsServiceMock) SetGetJob(f func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError)) {
+	m.getJob = f
+}
+
+func (m *SyncJobsServiceMock) SetCancelJob(f func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError)) {
+	m.cancelJob = f
+}
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+)
+
+type UserGameRepoMockInterface interface {
+	SetGetByUserID(func(userId uint64) ([]domain.UserGame, errorUtils.EntityError))
+	SetGetByUserAndGame(func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError))
+	SetCreate(func(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError))
+	SetUpdate(func(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError))
+	SetDelete(func(id uint64) errorUtils.EntityError)
+}
+
+type UserGameRepoMock struct {
+	getByUserID      func(userId uint64) ([]domain.UserGame, errorUtils.EntityError)
+	getByUserAndGame func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError)
+	create           func(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError)
+	update           func(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError)
+	delete           func(id uint64) errorUtils.EntityError
+}
+
+//UserGameRepoMockInterface implementation, so we can swap the methods around and get the desired behavior from the repository
+func (m *UserGameRepoMock) SetGetByUserID(f func(userId uint64) ([]domain.UserGame, errorUtils.EntityError)) {
+	m.getByUserID = f
+}
+
+func (m *UserGameRepoMock) SetGetByUserAndGame(f func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError)) {
+	m.getByUserAndGame = f
+}
+
+func (m *UserGameRepoMock) SetCreate(f func(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError)) {
+	m.create = f
+}
+
+func (m *UserGameRepoMock) SetUpdate(f func(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError)) {
+	m.update = f
+}
+
+func (m *UserGameRepoMock) SetDelete(f func(id uint64) errorUtils.EntityError) {
+	m.delete = f
+}
+
+//UserGameRepoInterface implementation (redirects all calls to the swappable methods)
+func (m *UserGameRepoMock) GetByUserID(userId uint64) ([]domain.UserGame, errorUtils.EntityError) {
+	return m.getByUserID(userId)
+}
+func (m *UserGameRepoMock) GetByUserAndGame(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+	return m.getByUserAndGame(userId, gameId, source)
+}
+func (m *UserGameRepoMock) Create(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError) {
+	return m.create(userGame)
+}
+func (m *UserGameRepoMock) Update(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError) {
+	return m.update(userGame)
+}
+func (m *UserGameRepoMock) Delete(id uint64) errorUtils.EntityError {
+	return m.delete(id)
+}
+func (m *UserGameRepoMock) Initialize(_ *gorm.DB) {}
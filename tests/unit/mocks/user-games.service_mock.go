@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/utils/errorUtils"
+)
+
+type UserGamesServiceMockInterface interface {
+	SetGetLibrary(func(userId uint64) ([]domain.UserGame, errorUtils.EntityError))
+	SetGetLibraryEntry(func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError))
+	SetSyncLibraryEntry(func(entry *domain.UserGame) (*domain.UserGame, errorUtils.EntityError))
+	SetDeleteLibraryEntry(func(userId uint64, gameId uint64, source string) errorUtils.EntityError)
+}
+
+type UserGamesServiceMock struct {
+	getLibrary         func(userId uint64) ([]domain.UserGame, errorUtils.EntityError)
+	getLibraryEntry    func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError)
+	syncLibraryEntry   func(entry *domain.UserGame) (*domain.UserGame, errorUtils.EntityError)
+	deleteLibraryEntry func(userId uint64, gameId uint64, source string) errorUtils.EntityError
+}
+
+func (m *UserGamesServiceMock) GetLibrary(userId uint64) ([]domain.UserGame, errorUtils.EntityError) {
+	return m.getLibrary(userId)
+}
+
+func (m *UserGamesServiceMock) GetLibraryEntry(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+	return m.getLibraryEntry(userId, gameId, source)
+}
+
+func (m *UserGamesServiceMock) SyncLibraryEntry(entry *domain.UserGame) (*domain.UserGame, errorUtils.EntityError) {
+	return m.syncLibraryEntry(entry)
+}
+
+func (m *UserGamesServiceMock) DeleteLibraryEntry(userId uint64, gameId uint64, source string) errorUtils.EntityError {
+	return m.deleteLibraryEntry(userId, gameId, source)
+}
+
+func (m *UserGamesServiceMock) SetGetLibrary(f func(userId uint64) ([]domain.UserGame, errorUtils.EntityError)) {
+	m.getLibrary = f
+}
+
+func (m *UserGamesServiceMock) SetGetLibraryEntry(f func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError)) {
+	m.getLibraryEntry = f
+}
+
+func (m *UserGamesServiceMock) SetSyncLibraryEntry(f func(entry *domain.UserGame) (*domain.UserGame, errorUtils.EntityError)) {
+	m.syncLibraryEntry = f
+}
+
+func (m *UserGamesServiceMock) SetDeleteLibraryEntry(f func(userId uint64, gameId uint64, source string) errorUtils.EntityError) {
+	m.deleteLibraryEntry = f
+}
@@ -13,6 +13,10 @@ type UserRoleServiceMockInterface interface {
 	SetUpdateRole(f func(role *domain.UserRole) (*domain.UserRole, errorUtils.EntityError))
 	SetDeleteRole(f func(roleId uint64) errorUtils.EntityError)
 	SetGetAllRoles(f func() ([]domain.UserRole, errorUtils.EntityError))
+	SetPolicyRole(f func(name string) (string, errorUtils.EntityError))
+	SetAssignRole(f func(userId uint64, name string) (*domain.UserRole, errorUtils.EntityError))
+	SetChangeRole(f func(userRoleId uint64, name string) (*domain.UserRole, errorUtils.EntityError))
+	SetRevokeRole(f func(userRoleId uint64) errorUtils.EntityError)
 }
 
 type UserRoleMock struct {
@@ -23,6 +27,10 @@ type UserRoleMock struct {
 	updateRole         func(role *domain.UserRole) (*domain.UserRole, errorUtils.EntityError)
 	deleteRole         func(roleId uint64) errorUtils.EntityError
 	getAllRoles        func() ([]domain.UserRole, errorUtils.EntityError)
+	policyRole         func(name string) (string, errorUtils.EntityError)
+	assignRole         func(userId uint64, name string) (*domain.UserRole, errorUtils.EntityError)
+	changeRole         func(userRoleId uint64, name string) (*domain.UserRole, errorUtils.EntityError)
+	revokeRole         func(userRoleId uint64) errorUtils.EntityError
 }
 
 func (u *UserRoleMock) SetGetRole(f func(userRoleId uint64) (*domain.UserRole, errorUtils.EntityError)) {
@@ -53,6 +61,22 @@ func (u *UserRoleMock) SetGetAllRoles(f func() ([]domain.UserRole, errorUtils.En
 	u.getAllRoles = f
 }
 
+func (u *UserRoleMock) SetPolicyRole(f func(name string) (string, errorUtils.EntityError)) {
+	u.policyRole = f
+}
+
+func (u *UserRoleMock) SetAssignRole(f func(userId uint64, name string) (*domain.UserRole, errorUtils.EntityError)) {
+	u.assignRole = f
+}
+
+func (u *UserRoleMock) SetChangeRole(f func(userRoleId uint64, name string) (*domain.UserRole, errorUtils.EntityError)) {
+	u.changeRole = f
+}
+
+func (u *UserRoleMock) SetRevokeRole(f func(userRoleId uint64) errorUtils.EntityError) {
+	u.revokeRole = f
+}
+
 func (u *UserRoleMock) GetRole(userRoleId uint64) (*domain.UserRole, errorUtils.EntityError) {
 	return u.getRole(userRoleId)
 }
@@ -80,3 +104,19 @@ func (u *UserRoleMock) DeleteRole(roleId uint64) errorUtils.EntityError {
 func (u *UserRoleMock) GetAllRoles() ([]domain.UserRole, errorUtils.EntityError) {
 	return u.getAllRoles()
 }
+
+func (u *UserRoleMock) PolicyRole(name string) (string, errorUtils.EntityError) {
+	return u.policyRole(name)
+}
+
+func (u *UserRoleMock) AssignRole(userId uint64, name string) (*domain.UserRole, errorUtils.EntityError) {
+	return u.assignRole(userId, name)
+}
+
+func (u *UserRoleMock) ChangeRole(userRoleId uint64, name string) (*domain.UserRole, errorUtils.EntityError) {
+	return u.changeRole(userRoleId, name)
+}
+
+func (u *UserRoleMock) RevokeRole(userRoleId uint64) errorUtils.EntityError {
+	return u.revokeRole(userRoleId)
+}
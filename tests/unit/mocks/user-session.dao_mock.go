@@ -3,6 +3,8 @@ package mocks
 import (
 	"GamesAPI/src/domain"
 	"GamesAPI/src/utils/errorUtils"
+	"github.com/jinzhu/gorm"
+	"time"
 )
 
 type UserSessionRepoMockInterface interface {
@@ -10,13 +12,21 @@ type UserSessionRepoMockInterface interface {
 	SetCreate(func(key string, token *domain.UserSession) (*domain.UserSession, errorUtils.EntityError))
 	SetDelete(func(key string) errorUtils.EntityError)
 	SetExists(func(key string) bool)
+	SetDeleteExpired(func(now time.Time) (int64, errorUtils.EntityError))
+	SetGetByUserID(func(userId uint64) ([]domain.UserSession, errorUtils.EntityError))
+	SetDeleteByUserID(func(userId uint64) (int64, errorUtils.EntityError))
+	SetDeleteByFamily(func(familyId string) (int64, errorUtils.EntityError))
 }
 
 type UserSessionRepoMock struct {
-	get    func(key string) (*domain.UserSession, errorUtils.EntityError)
-	create func(key string, token *domain.UserSession) (*domain.UserSession, errorUtils.EntityError)
-	delete func(key string) errorUtils.EntityError
-	exists func(key string) bool
+	get            func(key string) (*domain.UserSession, errorUtils.EntityError)
+	create         func(key string, token *domain.UserSession) (*domain.UserSession, errorUtils.EntityError)
+	delete         func(key string) errorUtils.EntityError
+	exists         func(key string) bool
+	deleteExpired  func(now time.Time) (int64, errorUtils.EntityError)
+	getByUserID    func(userId uint64) ([]domain.UserSession, errorUtils.EntityError)
+	deleteByUserID func(userId uint64) (int64, errorUtils.EntityError)
+	deleteByFamily func(familyId string) (int64, errorUtils.EntityError)
 }
 
 func (m *UserSessionRepoMock) Get(key string) (*domain.UserSession, errorUtils.EntityError) {
@@ -35,6 +45,24 @@ func (m *UserSessionRepoMock) Exists(key string) bool {
 	return m.exists(key)
 }
 
+func (m *UserSessionRepoMock) DeleteExpired(now time.Time) (int64, errorUtils.EntityError) {
+	return m.deleteExpired(now)
+}
+
+func (m *UserSessionRepoMock) GetByUserID(userId uint64) ([]domain.UserSession, errorUtils.EntityError) {
+	return m.getByUserID(userId)
+}
+
+func (m *UserSessionRepoMock) DeleteByUserID(userId uint64) (int64, errorUtils.EntityError) {
+	return m.deleteByUserID(userId)
+}
+
+func (m *UserSessionRepoMock) DeleteByFamily(familyId string) (int64, errorUtils.EntityError) {
+	return m.deleteByFamily(familyId)
+}
+
+func (m *UserSessionRepoMock) Initialize(db *gorm.DB) {}
+
 func (m *UserSessionRepoMock) SetCreate(f func(key string, token *domain.UserSession) (*domain.UserSession, errorUtils.EntityError)) {
 	m.create = f
 }
@@ -50,3 +78,19 @@ func (m *UserSessionRepoMock) SetExists(f func(key string) bool) {
 func (m *UserSessionRepoMock) SetGet(f func(key string) (*domain.UserSession, errorUtils.EntityError)) {
 	m.get = f
 }
+
+func (m *UserSessionRepoMock) SetDeleteExpired(f func(now time.Time) (int64, errorUtils.EntityError)) {
+	m.deleteExpired = f
+}
+
+func (m *UserSessionRepoMock) SetGetByUserID(f func(userId uint64) ([]domain.UserSession, errorUtils.EntityError)) {
+	m.getByUserID = f
+}
+
+func (m *UserSessionRepoMock) SetDeleteByUserID(f func(userId uint64) (int64, errorUtils.EntityError)) {
+	m.deleteByUserID = f
+}
+
+func (m *UserSessionRepoMock) SetDeleteByFamily(f func(familyId string) (int64, errorUtils.EntityError)) {
+	m.deleteByFamily = f
+}
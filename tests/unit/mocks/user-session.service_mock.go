@@ -8,20 +8,26 @@ import (
 
 type UserSessionServiceMockInterface interface {
 	SetGetSession(f func(key string) (*domain.UserSession, errorUtils.EntityError))
-	SetGenerateSessionToken(f func(userId uint64, expireAt time.Time) (string, error))
+	SetGenerateSessionToken(f func() (string, error))
 	SetCreateSession(f func(token *domain.UserSession) (*domain.UserSession, errorUtils.EntityError))
 	SetIsSessionExpired(f func(key string, currentTime time.Time) (bool, errorUtils.EntityError))
 	SetExistsSession(f func(key string) bool)
 	SetDeleteSession(f func(key string) errorUtils.EntityError)
+	SetGetUserSessions(f func(userId uint64) ([]domain.UserSession, errorUtils.EntityError))
+	SetRevokeSession(f func(userId uint64, sessionId string) errorUtils.EntityError)
+	SetRevokeUserSessions(f func(userId uint64) (int64, errorUtils.EntityError))
 }
 
 type UserSessionServiceMock struct {
 	getSession           func(key string) (*domain.UserSession, errorUtils.EntityError)
-	generateSessionToken func(userId uint64, expireAt time.Time) (string, error)
+	generateSessionToken func() (string, error)
 	createSession        func(token *domain.UserSession) (*domain.UserSession, errorUtils.EntityError)
 	isSessionExpired     func(key string, currentTime time.Time) (bool, errorUtils.EntityError)
 	existsSession        func(key string) bool
 	deleteSession        func(key string) errorUtils.EntityError
+	getUserSessions      func(userId uint64) ([]domain.UserSession, errorUtils.EntityError)
+	revokeSession        func(userId uint64, sessionId string) errorUtils.EntityError
+	revokeUserSessions   func(userId uint64) (int64, errorUtils.EntityError)
 }
 
 func (m *UserSessionServiceMock) GetSession(key string) (*domain.UserSession, errorUtils.EntityError) {
@@ -44,8 +50,20 @@ func (m *UserSessionServiceMock) IsSessionExpired(key string, currentTime time.T
 	return m.isSessionExpired(key, currentTime)
 }
 
-func (m *UserSessionServiceMock) GenerateSessionToken(userId uint64, expireAt time.Time) (string, error) {
-	return m.generateSessionToken(userId, expireAt)
+func (m *UserSessionServiceMock) GenerateSessionToken() (string, error) {
+	return m.generateSessionToken()
+}
+
+func (m *UserSessionServiceMock) GetUserSessions(userId uint64) ([]domain.UserSession, errorUtils.EntityError) {
+	return m.getUserSessions(userId)
+}
+
+func (m *UserSessionServiceMock) RevokeSession(userId uint64, sessionId string) errorUtils.EntityError {
+	return m.revokeSession(userId, sessionId)
+}
+
+func (m *UserSessionServiceMock) RevokeUserSessions(userId uint64) (int64, errorUtils.EntityError) {
+	return m.revokeUserSessions(userId)
 }
 
 func (m *UserSessionServiceMock) SetGetSession(f func(key string) (*domain.UserSession, errorUtils.EntityError)) {
@@ -68,6 +86,18 @@ func (m *UserSessionServiceMock) SetDeleteSession(f func(key string) errorUtils.
 	m.deleteSession = f
 }
 
-func (m *UserSessionServiceMock) SetGenerateSessionToken(f func(userId uint64, expireAt time.Time) (string, error)) {
+func (m *UserSessionServiceMock) SetGenerateSessionToken(f func() (string, error)) {
 	m.generateSessionToken = f
 }
+
+func (m *UserSessionServiceMock) SetGetUserSessions(f func(userId uint64) ([]domain.UserSession, errorUtils.EntityError)) {
+	m.getUserSessions = f
+}
+
+func (m *UserSessionServiceMock) SetRevokeSession(f func(userId uint64, sessionId string) errorUtils.EntityError) {
+	m.revokeSession = f
+}
+
+func (m *UserSessionServiceMock) SetRevokeUserSessions(f func(userId uint64) (int64, errorUtils.EntityError)) {
+	m.revokeUserSessions = f
+}
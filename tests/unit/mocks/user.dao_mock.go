@@ -12,6 +12,7 @@ type UserRepoMockInterface interface {
 	SetUpdateUserDomain(func(user *domain.User) (*domain.User, errorUtils.EntityError))
 	SetDeleteUserDomain(func(id uint64) errorUtils.EntityError)
 	SetGetAllUserDomain(func() ([]domain.User, errorUtils.EntityError))
+	SetGetByEmailUserDomain(func(email string) (*domain.User, errorUtils.EntityError))
 }
 
 type UserRepoMock struct {
@@ -20,6 +21,7 @@ type UserRepoMock struct {
 	updateUserDomain  func(user *domain.User) (*domain.User, errorUtils.EntityError)
 	deleteUserDomain  func(id uint64) errorUtils.EntityError
 	getAllUsersDomain func() ([]domain.User, errorUtils.EntityError)
+	getByEmailDomain  func(email string) (*domain.User, errorUtils.EntityError)
 }
 
 //UserRepoMockInterface implementation, so we can swap the methods around and get the desired behavior from the repository
@@ -43,10 +45,17 @@ func (m *UserRepoMock) SetGetAllUserDomain(f func() ([]domain.User, errorUtils.E
 	m.getAllUsersDomain = f
 }
 
+func (m *UserRepoMock) SetGetByEmailUserDomain(f func(email string) (*domain.User, errorUtils.EntityError)) {
+	m.getByEmailDomain = f
+}
+
 //UserRepoInterface implementation (redirects all calls to the swappable methods)
 func (m *UserRepoMock) Get(id uint64) (*domain.User, errorUtils.EntityError) {
 	return m.getUserDomain(id)
 }
+func (m *UserRepoMock) GetByEmail(email string) (*domain.User, errorUtils.EntityError) {
+	return m.getByEmailDomain(email)
+}
 func (m *UserRepoMock) Create(msg *domain.User) (*domain.User, errorUtils.EntityError) {
 	return m.createUserDomain(msg)
 }
@@ -8,29 +8,35 @@ import (
 type UserServiceMockInterface interface {
 	SetGetUser(func(uint64) (*domain.User, errorUtils.EntityError))
 	SetCreateUser(func(*domain.User) (*domain.User, errorUtils.EntityError))
-	SetUpdateUser(func(*domain.User) (*domain.User, errorUtils.EntityError))
+	SetUpdateUser(func(*domain.User, ...string) (*domain.User, errorUtils.EntityError))
 	SetDelete(func(uint64) errorUtils.EntityError)
 	SetGetAll(func() ([]domain.User, errorUtils.EntityError))
+	SetGetUserByEmail(func(email string) (*domain.User, errorUtils.EntityError))
 }
 
 type UserServiceMock struct {
 	getUserService    func(uint64) (*domain.User, errorUtils.EntityError)
 	createUserService func(*domain.User) (*domain.User, errorUtils.EntityError)
-	updateUserService func(*domain.User) (*domain.User, errorUtils.EntityError)
+	updateUserService func(*domain.User, ...string) (*domain.User, errorUtils.EntityError)
 	deleteUserService func(uint64) errorUtils.EntityError
 	getAllUserService func() ([]domain.User, errorUtils.EntityError)
+	getByEmailService func(email string) (*domain.User, errorUtils.EntityError)
 }
 
 func (u *UserServiceMock) GetUser(id uint64) (*domain.User, errorUtils.EntityError) {
 	return u.getUserService(id)
 }
 
+func (u *UserServiceMock) GetUserByEmail(email string) (*domain.User, errorUtils.EntityError) {
+	return u.getByEmailService(email)
+}
+
 func (u *UserServiceMock) CreateUser(user *domain.User) (*domain.User, errorUtils.EntityError) {
 	return u.createUserService(user)
 }
 
-func (u *UserServiceMock) UpdateUser(user *domain.User) (*domain.User, errorUtils.EntityError) {
-	return u.updateUserService(user)
+func (u *UserServiceMock) UpdateUser(user *domain.User, fields ...string) (*domain.User, errorUtils.EntityError) {
+	return u.updateUserService(user, fields...)
 }
 
 func (u *UserServiceMock) DeleteUser(id uint64) errorUtils.EntityError {
@@ -49,7 +55,7 @@ func (u *UserServiceMock) SetCreateUser(f func(*domain.User) (*domain.User, erro
 	u.createUserService = f
 }
 
-func (u *UserServiceMock) SetUpdateUser(f func(*domain.User) (*domain.User, errorUtils.EntityError)) {
+func (u *UserServiceMock) SetUpdateUser(f func(*domain.User, ...string) (*domain.User, errorUtils.EntityError)) {
 	u.updateUserService = f
 }
 
@@ -60,3 +66,7 @@ func (u *UserServiceMock) SetDelete(f func(uint64) errorUtils.EntityError) {
 func (u *UserServiceMock) SetGetAll(f func() ([]domain.User, errorUtils.EntityError)) {
 	u.getAllUserService = f
 }
+
+func (u *UserServiceMock) SetGetUserByEmail(f func(email string) (*domain.User, errorUtils.EntityError)) {
+	u.getByEmailService = f
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ApiKeyServiceTestSuite struct {
+	suite.Suite
+	mockRepository     mocks.ApiKeyRepoMockInterface
+	mockUserRepository mocks.UserRepoMockInterface
+	//keys created through the mocked repository, by prefix
+	stored  map[string]*domain.ApiKey
+	touched int
+}
+
+func TestApiKeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ApiKeyServiceTestSuite))
+}
+
+func (s *ApiKeyServiceTestSuite) SetupSuite() {
+	mock := &mocks.ApiKeyRepoMock{}
+	userMock := &mocks.UserRepoMock{}
+	s.mockRepository = mock
+	s.mockUserRepository = userMock
+	domain.ApiKeyRepo = mock
+	domain.UserRepo = userMock
+}
+
+func (s *ApiKeyServiceTestSuite) BeforeTest(_, _ string) {
+	s.stored = map[string]*domain.ApiKey{}
+	s.touched = 0
+	s.mockUserRepository.SetGetUserDomain(func(id uint64) (*domain.User, errorUtils.EntityError) {
+		if id != testUserId {
+			return nil, errorUtils.NewNotFoundError("record not found")
+		}
+		return &domain.User{ID: id}, nil
+	})
+	s.mockRepository.SetCreate(func(key *domain.ApiKey) (*domain.ApiKey, errorUtils.EntityError) {
+		key.ID = uint64(len(s.stored) + 1)
+		stored := *key
+		s.stored[key.Prefix] = &stored
+		return key, nil
+	})
+	s.mockRepository.SetGetByPrefix(func(prefix string) (*domain.ApiKey, errorUtils.EntityError) {
+		key, exists := s.stored[prefix]
+		if !exists {
+			return nil, errorUtils.NewNotFoundError("record not found")
+		}
+		ret := *key
+		return &ret, nil
+	})
+	s.mockRepository.SetTouchLastUsed(func(id uint64, at time.Time) errorUtils.EntityError {
+		s.touched++
+		for _, key := range s.stored {
+			if key.ID == id {
+				key.LastUsedAt = &at
+			}
+		}
+		return nil
+	})
+}
+
+func (s *ApiKeyServiceTestSuite) issue(key *domain.ApiKey) string {
+	rawKey, _, err := services.ApiKeyService.IssueKey(key)
+	require.Nil(s.T(), err)
+	return rawKey
+}
+
+func (s *ApiKeyServiceTestSuite) TestIssueKey_StoresOnlyTheHash() {
+	rawKey, key, err := services.ApiKeyService.IssueKey(&domain.ApiKey{OwnerId: testUserId, Label: "mobile app", Scopes: domain.ApiScopes{"games"}})
+	t := s.T()
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(rawKey, services.ApiKeyTokenPrefix+"_"+key.Prefix+"_"))
+	secret := strings.TrimPrefix(rawKey, services.ApiKeyTokenPrefix+"_"+key.Prefix+"_")
+	assert.NotContains(t, key.SecretHash, secret)
+	assert.Len(t, key.Prefix, 12)
+}
+
+func (s *ApiKeyServiceTestSuite) TestIssueKey_UnknownOwner() {
+	_, _, err := services.ApiKeyService.IssueKey(&domain.ApiKey{OwnerId: testUserId + 1, Label: "mobile app"})
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusUnprocessableEntity, err.Status())
+}
+
+func (s *ApiKeyServiceTestSuite) TestIssueKey_Invalid() {
+	_, _, err := services.ApiKeyService.IssueKey(&domain.ApiKey{OwnerId: testUserId, Label: " "})
+	assert.EqualValues(s.T(), http.StatusUnprocessableEntity, err.Status())
+
+	_, _, err = services.ApiKeyService.IssueKey(&domain.ApiKey{OwnerId: testUserId, Label: "app", Scopes: domain.ApiScopes{"a,b"}})
+	assert.EqualValues(s.T(), http.StatusUnprocessableEntity, err.Status())
+}
+
+func (s *ApiKeyServiceTestSuite) TestAuthenticate_Success() {
+	rawKey := s.issue(&domain.ApiKey{OwnerId: testUserId, Label: "mobile app"})
+
+	key, err := services.ApiKeyService.Authenticate(rawKey)
+	t := s.T()
+	require.Nil(t, err)
+	assert.EqualValues(t, testUserId, key.OwnerId)
+	assert.NotNil(t, key.LastUsedAt)
+
+	//last use is not written again right away
+	_, err = services.ApiKeyService.Authenticate(rawKey)
+	require.Nil(t, err)
+	assert.EqualValues(t, 1, s.touched)
+}
+
+func (s *ApiKeyServiceTestSuite) TestAuthenticate_WrongSecret() {
+	rawKey := s.issue(&domain.ApiKey{OwnerId: testUserId, Label: "mobile app"})
+	parts := strings.SplitN(rawKey, "_", 3)
+
+	_, err := services.ApiKeyService.Authenticate(parts[0] + "_" + parts[1] + "_not-the-secret")
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusUnauthorized, err.Status())
+}
+
+func (s *ApiKeyServiceTestSuite) TestAuthenticate_Malformed() {
+	for _, rawKey := range []string{"212634", "gapi_", "gapi_abc", "other_abc_def"} {
+		_, err := services.ApiKeyService.Authenticate(rawKey)
+		require.NotNil(s.T(), err, rawKey)
+		assert.EqualValues(s.T(), http.StatusUnauthorized, err.Status())
+	}
+}
+
+func (s *ApiKeyServiceTestSuite) TestAuthenticate_RevokedOrExpired() {
+	revoked := s.issue(&domain.ApiKey{OwnerId: testUserId, Label: "revoked"})
+	for _, key := range s.stored {
+		key.Revoked = true
+	}
+	past := time.Now().Add(-time.Minute)
+	expired := s.issue(&domain.ApiKey{OwnerId: testUserId, Label: "expired", ExpiresAt: &past})
+
+	for _, rawKey := range []string{revoked, expired} {
+		_, err := services.ApiKeyService.Authenticate(rawKey)
+		require.NotNil(s.T(), err)
+		assert.EqualValues(s.T(), http.StatusUnauthorized, err.Status())
+	}
+}
+
+func (s *ApiKeyServiceTestSuite) TestAuthenticate_RepositoryError() {
+	s.mockRepository.SetGetByPrefix(func(prefix string) (*domain.ApiKey, errorUtils.EntityError) {
+		return nil, errorUtils.NewInternalServerError("database is down")
+	})
+	_, err := services.ApiKeyService.Authenticate("gapi_0a1b2c3d4e5f_secret")
+	assert.EqualValues(s.T(), http.StatusInternalServerError, err.Status())
+}
+
+func (s *ApiKeyServiceTestSuite) TestGetKeys() {
+	s.mockRepository.SetGetAll(func() ([]domain.ApiKey, errorUtils.EntityError) {
+		return []domain.ApiKey{{ID: 1}, {ID: 2}}, nil
+	})
+	s.mockRepository.SetGetByOwner(func(ownerId uint64) ([]domain.ApiKey, errorUtils.EntityError) {
+		assert.EqualValues(s.T(), testUserId, ownerId)
+		return []domain.ApiKey{{ID: 2}}, nil
+	})
+
+	all, _ := services.ApiKeyService.GetKeys(0)
+	owned, _ := services.ApiKeyService.GetKeys(testUserId)
+	assert.Len(s.T(), all, 2)
+	assert.Len(s.T(), owned, 1)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type AuditServiceTestSuite struct {
+	suite.Suite
+	mockRepository mocks.AuditRepoMockInterface
+}
+
+func TestAuditServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditServiceTestSuite))
+}
+
+func (s *AuditServiceTestSuite) SetupSuite() {
+	mock := &mocks.AuditRepoMock{}
+	s.mockRepository = mock
+	domain.AuditRepo = mock
+}
+
+func (s *AuditServiceTestSuite) TestRecord() {
+	var appended *domain.AuditEntry
+	s.mockRepository.SetAppend(func(entry *domain.AuditEntry) (*domain.AuditEntry, errorUtils.EntityError) {
+		appended = entry
+		return entry, nil
+	})
+	ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(1))
+	ctx = context.WithValue(ctx, domain.RequestIdKey(), "req-1")
+	ctx = context.WithValue(ctx, domain.ApiClientKey(), &domain.ApiClient{KeyId: 9, Prefix: "gk_ab12"})
+
+	before := &domain.Game{ID: 12, Title: "Portal"}
+	after := &domain.Game{ID: 12, Title: "Portal 2"}
+	err := services.AuditService.Record(ctx, "game", "update", 12, before, after)
+	require.Nil(s.T(), err)
+	require.NotNil(s.T(), appended)
+	assert.EqualValues(s.T(), domain.AuditEntry{
+		ActorId:      1,
+		ApiKeyId:     9,
+		ApiKeyPrefix: "gk_ab12",
+		Resource:     "game",
+		Action:       "update",
+		EntityId:     12,
+		Changes:      domain.AuditChanges{"title": {Before: "Portal", After: "Portal 2"}},
+		RequestId:    "req-1",
+	}, *appended)
+}
+
+func (s *AuditServiceTestSuite) TestGetEntries_Invalid() {
+	query := domain.NewAuditQuery()
+	query.PageSize = 0
+	page, err := services.AuditService.GetEntries(query)
+	assert.Nil(s.T(), page)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusBadRequest, err.Status())
+}
+
+func (s *AuditServiceTestSuite) TestExport() {
+	//a full batch, then the rest of the log
+	var beforeIds []uint64
+	s.mockRepository.SetFind(func(query *domain.AuditQuery) ([]domain.AuditEntry, uint64, errorUtils.EntityError) {
+		beforeIds = append(beforeIds, query.BeforeId)
+		assert.EqualValues(s.T(), "game", query.Resource)
+		assert.EqualValues(s.T(), 1, query.Page)
+		if query.BeforeId == 0 {
+			entries := make([]domain.AuditEntry, domain.MaxAuditPageSize)
+			for i := range entries {
+				entries[i] = domain.AuditEntry{ID: 1000 - uint64(i), Resource: "game"}
+			}
+			return entries, 502, nil
+		}
+		return []domain.AuditEntry{{ID: 2, Resource: "game"}, {ID: 1, Resource: "game"}}, 502, nil
+	})
+
+	query := domain.NewAuditQuery()
+	query.Resource, query.Page = "game", 3
+	var out bytes.Buffer
+	err := services.AuditService.Export(query, &out)
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), []uint64{0, 501}, beforeIds)
+
+	var lines []domain.AuditEntry
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var entry domain.AuditEntry
+		require.Nil(s.T(), json.Unmarshal(scanner.Bytes(), &entry))
+		lines = append(lines, entry)
+	}
+	require.Len(s.T(), lines, 502)
+	assert.EqualValues(s.T(), 1000, lines[0].ID)
+	assert.EqualValues(s.T(), 1, lines[501].ID)
+}
@@ -1,21 +1,60 @@
 package services
 
 import (
+	"GamesAPI/src/domain"
 	"GamesAPI/src/services"
 	"GamesAPI/src/utils/authUtils"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"net/http"
 	"testing"
+	"time"
+)
+
+const (
+	authTestEmail    = "dev@golang.com"
+	authTestPassword = "thisisanicep4ssw0rd"
+	authTestIP       = "192.0.2.1"
 )
 
 type AuthenticationServiceTestSuite struct {
 	suite.Suite
+	mockRepository mocks.UserRepoMockInterface
+	passwordHash   string
 }
 
 func TestAuthenticationServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthenticationServiceTestSuite))
 }
 
+func (s *AuthenticationServiceTestSuite) SetupSuite() {
+	mock := &mocks.UserRepoMock{}
+	s.mockRepository = mock
+	domain.UserRepo = mock
+	s.passwordHash, _ = authUtils.HashAndSalt([]byte(authTestPassword))
+}
+
+func (s *AuthenticationServiceTestSuite) BeforeTest(_, _ string) {
+	services.LoginAttemptService = services.NewLoginAttemptService(services.LoginAttemptConfig{
+		MaxPerAccount: 3,
+		MaxPerIP:      5,
+		Lockout:       time.Minute,
+	})
+	s.mockRepository.SetGetByEmailUserDomain(func(email string) (*domain.User, errorUtils.EntityError) {
+		if email != authTestEmail {
+			return nil, errorUtils.NewNotFoundError("record not found")
+		}
+		return &domain.User{ID: 1, Email: authTestEmail, PasswordHash: s.passwordHash}, nil
+	})
+}
+
+func (s *AuthenticationServiceTestSuite) TearDownSuite() {
+	services.LoginAttemptService = services.NewLoginAttemptService(services.LoginAttemptConfig{})
+}
+
 func (s *AuthenticationServiceTestSuite) TestValidatePassword_Success() {
 	strPlainPassword := "thisisanicep4ssw0rd"
 	hashed, err := authUtils.HashAndSalt([]byte(strPlainPassword))
@@ -44,3 +83,68 @@ func (s *AuthenticationServiceTestSuite) TestValidatePassword_Failure() {
 	assert.False(s.T(), validates)
 	assert.NotNil(s.T(), err)
 }
+
+func (s *AuthenticationServiceTestSuite) TestAuthenticate_Success() {
+	user, err := services.AuthenticationService.Authenticate(authTestEmail, []byte(authTestPassword), authTestIP)
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 1, user.ID)
+}
+
+func (s *AuthenticationServiceTestSuite) TestAuthenticate_UnknownEmailLooksLikeBadPassword() {
+	_, unknownErr := services.AuthenticationService.Authenticate("nobody@golang.com", []byte(authTestPassword), authTestIP)
+	_, badPasswordErr := services.AuthenticationService.Authenticate(authTestEmail, []byte("wrong"), authTestIP)
+
+	t := s.T()
+	require.NotNil(t, unknownErr)
+	require.NotNil(t, badPasswordErr)
+	assert.EqualValues(t, http.StatusUnauthorized, unknownErr.Status())
+	assert.EqualValues(t, badPasswordErr, unknownErr)
+}
+
+func (s *AuthenticationServiceTestSuite) TestAuthenticate_RepositoryError() {
+	s.mockRepository.SetGetByEmailUserDomain(func(email string) (*domain.User, errorUtils.EntityError) {
+		return nil, errorUtils.NewInternalServerError("database is down")
+	})
+	_, err := services.AuthenticationService.Authenticate(authTestEmail, []byte(authTestPassword), authTestIP)
+	assert.EqualValues(s.T(), http.StatusInternalServerError, err.Status())
+}
+
+func (s *AuthenticationServiceTestSuite) TestAuthenticate_AccountLockout() {
+	for i := 0; i < 3; i++ {
+		_, err := services.AuthenticationService.Authenticate(authTestEmail, []byte("wrong"), authTestIP)
+		assert.EqualValues(s.T(), http.StatusUnauthorized, err.Status())
+	}
+
+	//even the right password is refused while locked out, from any IP
+	_, err := services.AuthenticationService.Authenticate(authTestEmail, []byte(authTestPassword), "198.51.100.7")
+	t := s.T()
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusTooManyRequests, err.Status())
+
+	//unknown emails get locked out just the same
+	for i := 0; i < 3; i++ {
+		_, _ = services.AuthenticationService.Authenticate("nobody@golang.com", []byte("wrong"), "198.51.100.8")
+	}
+	_, err = services.AuthenticationService.Authenticate("nobody@golang.com", []byte("wrong"), "198.51.100.8")
+	assert.EqualValues(t, http.StatusTooManyRequests, err.Status())
+}
+
+func (s *AuthenticationServiceTestSuite) TestAuthenticate_IPLockout() {
+	for i := 0; i < 5; i++ {
+		_, _ = services.AuthenticationService.Authenticate("user"+string(rune('a'+i))+"@golang.com", []byte("wrong"), authTestIP)
+	}
+	_, err := services.AuthenticationService.Authenticate(authTestEmail, []byte(authTestPassword), authTestIP)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusTooManyRequests, err.Status())
+}
+
+func (s *AuthenticationServiceTestSuite) TestAuthenticate_SuccessResetsAccount() {
+	for i := 0; i < 2; i++ {
+		_, _ = services.AuthenticationService.Authenticate(authTestEmail, []byte("wrong"), authTestIP)
+	}
+	_, err := services.AuthenticationService.Authenticate(authTestEmail, []byte(authTestPassword), authTestIP)
+	require.Nil(s.T(), err)
+
+	_, err = services.AuthenticationService.Authenticate(authTestEmail, []byte("wrong"), authTestIP)
+	assert.EqualValues(s.T(), http.StatusUnauthorized, err.Status())
+}
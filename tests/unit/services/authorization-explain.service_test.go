@@ -0,0 +1,73 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+)
+
+func explain(strategy domain.RoleStrategy, roles []string, resource string, endpoint string, path string) *domain.RbacTrace {
+	service := services.NewAuthorizationServiceWithStrategy("../resources/rbac-roles-test.yml", strategy)
+	ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+	ctx = context.WithValue(ctx, domain.RbacRouteParams(), map[string]string{"id": path})
+	return service.Explain(ctx, &url.URL{Path: "/users/" + path + "/library"}, roles, resource, endpoint)
+}
+
+func TestExplain_ViolationThenGrant(t *testing.T) {
+	trace := explain(domain.RoleStrategyAnyAllow, []string{"user", "moderator", "admin"}, "library", "read", "4")
+
+	assert.True(t, trace.Allowed)
+	assert.EqualValues(t, "moderator", trace.Role)
+	assert.Empty(t, trace.Error)
+	assert.EqualValues(t, "library", trace.Resource)
+	assert.EqualValues(t, domain.RoleStrategyAnyAllow, trace.Strategy)
+	require.Len(t, trace.Roles, 3)
+
+	user := trace.Roles[0]
+	assert.EqualValues(t, domain.RbacOutcomeViolated, user.Outcome)
+	assert.EqualValues(t, "library.read", user.Entry)
+	assert.EqualValues(t, "path rule violation: ensure 'id' = '3', instead got: '4'", user.Error)
+	require.Len(t, user.Checks, 1)
+	assert.EqualValues(t, domain.RbacRuleCheck{
+		Kind: "ensure", Target: "path", Key: "id", Operator: "=", Value: "ctx.userId",
+		Expected: uint64(3), Actual: "4", Complies: false, Error: user.Error,
+	}, user.Checks[0])
+
+	assert.EqualValues(t, domain.RbacOutcomeGranted, trace.Roles[1].Outcome)
+	//any-allow stops at the first role granting access
+	assert.EqualValues(t, domain.RbacOutcomeSkipped, trace.Roles[2].Outcome)
+
+	//the rules of the granting role are enforced
+	require.Len(t, trace.Enforced, 1)
+	assert.EqualValues(t, "limit", trace.Enforced[0].Key)
+	assert.EqualValues(t, "10", trace.Enforced[0].Expected)
+	assert.EqualValues(t, "", trace.Enforced[0].Actual)
+}
+
+func TestExplain_Refused(t *testing.T) {
+	trace := explain(domain.RoleStrategyDenyOverrides, []string{"guest", "user", "moderator"}, "game", "delete", "3")
+
+	assert.False(t, trace.Allowed)
+	assert.Empty(t, trace.Role)
+	assert.EqualValues(t, errorUtils.ErrForbidden.Error(), trace.Error)
+	require.Len(t, trace.Roles, 3)
+	assert.EqualValues(t, domain.RbacOutcomeNoPermission, trace.Roles[0].Outcome)
+	assert.EqualValues(t, domain.RbacOutcomeDenied, trace.Roles[1].Outcome)
+	assert.EqualValues(t, "game.delete", trace.Roles[1].Entry)
+	//deny overrides stops at the first denial
+	assert.EqualValues(t, domain.RbacOutcomeSkipped, trace.Roles[2].Outcome)
+	assert.Empty(t, trace.Enforced)
+}
+
+func TestExplain_Wildcard(t *testing.T) {
+	trace := explain(domain.RoleStrategyAnyAllow, []string{"admin"}, "sync_job", "delete", "3")
+
+	assert.True(t, trace.Allowed)
+	assert.EqualValues(t, "*.*", trace.Roles[0].Entry)
+	assert.Empty(t, trace.Roles[0].Checks)
+}
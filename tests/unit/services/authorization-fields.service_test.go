@@ -0,0 +1,67 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"testing"
+)
+
+func authorizeBody(roles []string, endpoint string, body string) (*domain.RbacDecision, error) {
+	service := services.NewAuthorizationService("../resources/rbac-fields-test.yml")
+	ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+	ctx = context.WithValue(ctx, domain.RbacRequestBody(), []byte(body))
+	return service.AuthorizeRoles(ctx, &url.URL{Path: "/users/3"}, roles, "user", endpoint)
+}
+
+func TestAuthorizeRoles_WritableFields(t *testing.T) {
+	decision, err := authorizeBody([]string{"user"}, "update", `{"name": "me", "email": "me@example.com"}`)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "user", decision.Role)
+
+	decision, err = authorizeBody([]string{"user"}, "update", `{"name": "me", "password_hash": "x"}`)
+	require.NotNil(t, err)
+	assert.EqualValues(t, "field rule violation: 'password_hash' is not writable, expected one of [name, email]", err.Error())
+	assert.Empty(t, decision.Role)
+	assert.Nil(t, decision.Fields)
+
+	//another role may write the field
+	decision, err = authorizeBody([]string{"user", "admin"}, "update", `{"password_hash": "x"}`)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "admin", decision.Role)
+}
+
+func TestAuthorizeRoles_VisibleFields(t *testing.T) {
+	//the update permission restricts nothing visible, those of read apply
+	decision, err := authorizeBody([]string{"user"}, "update", "")
+	require.Nil(t, err)
+	require.NotNil(t, decision.Fields)
+	assert.EqualValues(t, []string{"id", "name"}, decision.Fields.Visible)
+	assert.EqualValues(t, []string{"email"}, decision.Fields.Own)
+
+	decision, err = authorizeBody([]string{"editor"}, "update", "")
+	require.Nil(t, err)
+	assert.EqualValues(t, []string{"id", "name", "email", "steam_user_id"}, decision.Fields.Visible)
+
+	decision, err = authorizeBody([]string{"editor"}, "read", "")
+	assert.Nil(t, err)
+	assert.Nil(t, decision.Fields)
+}
+
+func TestExplain_WritableFields(t *testing.T) {
+	service := services.NewAuthorizationService("../resources/rbac-fields-test.yml")
+	ctx := context.WithValue(context.Background(), domain.RbacRequestBody(), []byte(`{"id": 4, "name": "me"}`))
+	trace := service.Explain(ctx, &url.URL{Path: "/users/3"}, []string{"user"}, "user", "update")
+
+	assert.False(t, trace.Allowed)
+	require.Len(t, trace.Roles, 1)
+	assert.EqualValues(t, domain.RbacOutcomeViolated, trace.Roles[0].Outcome)
+	require.Len(t, trace.Roles[0].Checks, 1)
+	check := trace.Roles[0].Checks[0]
+	assert.EqualValues(t, "writable", check.Kind)
+	assert.EqualValues(t, "id", check.Actual)
+	assert.False(t, check.Complies)
+}
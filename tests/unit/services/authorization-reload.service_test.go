@@ -0,0 +1,275 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const (
+	policyV1 = `
+user:
+  game:
+    read:
+      allow: true
+`
+	policyV2 = `
+user:
+  game:
+    read:
+      allow: false
+`
+	//same size as policyV1, so only a SIGHUP notices it when the modification time is kept
+	policyV3 = `
+user:
+  user:
+    read:
+      allow: true
+`
+	invalidPolicy = `
+user:
+  game:
+    read:
+      allow: true
+      ensure:
+        query:
+          - key: userId
+            operator: "=~"
+            value: ctx.userId
+`
+)
+
+type AuthorizationReloadTestSuite struct {
+	suite.Suite
+	dir  string
+	path string
+}
+
+func TestAuthorizationReloadTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthorizationReloadTestSuite))
+}
+
+func (s *AuthorizationReloadTestSuite) BeforeTest(_, _ string) {
+	var err error
+	s.dir, err = ioutil.TempDir("", "rbac")
+	require.Nil(s.T(), err)
+	s.path = filepath.Join(s.dir, "rbac.yml")
+	s.write(policyV1)
+}
+
+func (s *AuthorizationReloadTestSuite) TearDownTest() {
+	_ = os.RemoveAll(s.dir)
+}
+
+func (s *AuthorizationReloadTestSuite) write(policy string) {
+	require.Nil(s.T(), ioutil.WriteFile(s.path, []byte(policy), 0644))
+}
+
+func (s *AuthorizationReloadTestSuite) gameReadAllowed(service services.AuthorizationServiceInterface) bool {
+	return service.GetRbac()["user"]["game"]["read"].Allow
+}
+
+func (s *AuthorizationReloadTestSuite) TestReload_NewPolicy() {
+	service := services.NewAuthorizationService(s.path)
+	first := service.PolicyInfo()
+	t := s.T()
+	assert.EqualValues(t, 1, first.Version)
+	assert.Len(t, first.Hash, 64)
+	assert.True(t, s.gameReadAllowed(service))
+
+	s.write(policyV2)
+	require.Nil(t, service.Reload())
+	second := service.PolicyInfo()
+	assert.EqualValues(t, 2, second.Version)
+	assert.NotEqual(t, first.Hash, second.Hash)
+	assert.False(t, s.gameReadAllowed(service))
+}
+
+func (s *AuthorizationReloadTestSuite) TestReload_Unchanged() {
+	service := services.NewAuthorizationService(s.path)
+	require.Nil(s.T(), service.Reload())
+	assert.EqualValues(s.T(), 1, service.PolicyInfo().Version)
+}
+
+func (s *AuthorizationReloadTestSuite) TestReload_KeepsLastGoodPolicy() {
+	service := services.NewAuthorizationService(s.path)
+	t := s.T()
+
+	for _, broken := range []string{invalidPolicy, "user: [not, a, policy]", "admin:\n  game:\n    read:\n      alow: true\n"} {
+		s.write(broken)
+		assert.NotNil(t, service.Reload(), broken)
+		info := service.PolicyInfo()
+		assert.EqualValues(t, 1, info.Version)
+		assert.NotEmpty(t, info.LastError)
+		assert.NotNil(t, info.LastErrorAt)
+		assert.True(t, s.gameReadAllowed(service))
+	}
+
+	require.Nil(t, os.Remove(s.path))
+	assert.NotNil(t, service.Reload())
+	assert.True(t, s.gameReadAllowed(service))
+
+	//a good policy clears the error
+	s.write(policyV2)
+	require.Nil(t, service.Reload())
+	assert.EqualValues(t, 2, service.PolicyInfo().Version)
+	assert.Empty(t, service.PolicyInfo().LastError)
+}
+
+func (s *AuthorizationReloadTestSuite) TestReload_RevertClearsError() {
+	service := services.NewAuthorizationService(s.path)
+	t := s.T()
+
+	s.write(invalidPolicy)
+	assert.NotNil(t, service.Reload())
+	assert.NotEmpty(t, service.PolicyInfo().LastError)
+
+	//the enforced policy is written back, nothing is reloaded
+	s.write(policyV1)
+	require.Nil(t, service.Reload())
+	info := service.PolicyInfo()
+	assert.EqualValues(t, 1, info.Version)
+	assert.Empty(t, info.LastError)
+	assert.Nil(t, info.LastErrorAt)
+}
+
+func (s *AuthorizationReloadTestSuite) TestReload_AgainstRoutes() {
+	surface := &domain.RbacSurface{
+		Resources:   map[string]map[string]bool{"game": {"read": true}, "sync_games": {"create": true}},
+		ContextKeys: map[string]bool{domain.RbacUserId(): true},
+	}
+	service := services.NewAuthorizationServiceFromSource(services.NewRbacFileSource(s.path), domain.DefaultRoleStrategy, surface)
+	t := s.T()
+
+	for _, policy := range []string{
+		policyV1 + "  sync_gamees:\n    create:\n      allow: true\n",
+		"user:\n  game:\n    read:\n      allow: true\n      ensure:\n        query:\n          - key: userId\n            operator: \"=\"\n            value: ctx.userIdd\n",
+	} {
+		s.write(policy)
+		assert.NotNil(t, service.Reload(), policy)
+		assert.EqualValues(t, 1, service.PolicyInfo().Version, policy)
+	}
+	assert.Contains(t, service.PolicyInfo().LastError, "ctx.userIdd")
+
+	s.write(policyV1 + "  sync_games:\n    create:\n      allow: true\n")
+	require.Nil(t, service.Reload())
+	assert.EqualValues(t, 2, service.PolicyInfo().Version)
+}
+
+func (s *AuthorizationReloadTestSuite) TestNewAuthorizationService_InvalidPolicy() {
+	s.write(invalidPolicy)
+	assert.Panics(s.T(), func() {
+		services.NewAuthorizationService(s.path)
+	})
+}
+
+func (s *AuthorizationReloadTestSuite) TestWatch_FileChange() {
+	service := services.NewAuthorizationService(s.path)
+	stop := service.Watch(10 * time.Millisecond)
+	defer stop()
+
+	s.write(policyV2)
+	//make sure the change is noticed even if the file system has a coarse modification time
+	later := time.Now().Add(time.Minute)
+	require.Nil(s.T(), os.Chtimes(s.path, later, later))
+	assert.Eventually(s.T(), func() bool {
+		return service.PolicyInfo().Version == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.False(s.T(), s.gameReadAllowed(service))
+}
+
+func (s *AuthorizationReloadTestSuite) TestWatch_Sighup() {
+	service := services.NewAuthorizationService(s.path)
+	stat, err := os.Stat(s.path)
+	require.Nil(s.T(), err)
+	stop := service.Watch(time.Hour)
+	defer stop()
+
+	s.write(policyV3)
+	require.Nil(s.T(), os.Chtimes(s.path, stat.ModTime(), stat.ModTime()))
+	require.Nil(s.T(), syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(s.T(), func() bool {
+		return service.PolicyInfo().Version == 2
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func (s *AuthorizationReloadTestSuite) TestAuthorize_HeaderAndPathRules() {
+	s.write(`
+user:
+  library:
+    read:
+      allow: true
+      ensure:
+        path:
+          - key: id
+            operator: "="
+            value: ctx.userId
+        header:
+          - key: x-client
+            operator: in
+            value: [web, mobile]
+      enforce:
+        header:
+          - key: x-owner
+            value: ctx.userId
+`)
+	service := services.NewAuthorizationService(s.path)
+	authorize := func(id string, client string) (http.Header, error) {
+		headers := http.Header{"X-Client": {client}}
+		ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+		ctx = context.WithValue(ctx, domain.RbacRouteParams(), map[string]string{"id": id})
+		ctx = context.WithValue(ctx, domain.RbacRequestHeaders(), headers)
+		req, _ := http.NewRequest(http.MethodGet, "/users/"+id+"/library", nil)
+		return headers, service.Authorize(ctx, req.URL, "user", "library", "read")
+	}
+
+	headers, err := authorize("3", "mobile")
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "3", headers.Get("X-Owner"))
+
+	_, err = authorize("4", "mobile")
+	assert.NotNil(t, err)
+	_, err = authorize("3", "cli")
+	assert.NotNil(t, err)
+}
+
+func (s *AuthorizationReloadTestSuite) TestAuthorize_BodyRules() {
+	s.write(`
+user:
+  library:
+    sync:
+      allow: true
+      ensure:
+        body:
+          - key: userid
+            operator: "="
+            value: ctx.userId
+`)
+	service := services.NewAuthorizationService(s.path)
+	authorize := func(body string) error {
+		ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+		ctx = context.WithValue(ctx, domain.RbacRequestBody(), []byte(body))
+		return service.Authorize(ctx, &url.URL{Path: "/SyncGames"}, "user", "library", "sync")
+	}
+
+	assert.Nil(s.T(), authorize(`{"userid": 3}`))
+	err := authorize(`{"userid": 4}`)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), "body rule violation: ensure 'userid' = '3', instead got: '4'", err.Error())
+	//the handler binds 5, the last of the two
+	err = authorize(`{"userid":3,"USERID":5}`)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), "body rule violation: ensure 'userid' = '3', instead got: '5'", err.Error())
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func authorizeRoles(strategy domain.RoleStrategy, roles []string, resource string, endpoint string, path string) (*domain.RbacDecision, *url.URL, error) {
+	service := services.NewAuthorizationServiceWithStrategy("../resources/rbac-roles-test.yml", strategy)
+	ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+	ctx = context.WithValue(ctx, domain.RbacRouteParams(), map[string]string{"id": path})
+	u := &url.URL{Path: "/users/" + path + "/library"}
+	decision, err := service.AuthorizeRoles(ctx, u, roles, resource, endpoint)
+	return decision, u, err
+}
+
+func TestAuthorizeRoles(t *testing.T) {
+	tests := []struct {
+		given    string
+		strategy domain.RoleStrategy
+		roles    []string
+		resource string
+		endpoint string
+		path     string
+		wantRole string
+		wantErr  error
+	}{{
+		given:    "One role granting access",
+		strategy: domain.RoleStrategyAnyAllow,
+		roles:    []string{"user"},
+		resource: "game", endpoint: "read",
+		wantRole: "user",
+	}, {
+		given:    "The first role denies, the second one grants",
+		strategy: domain.RoleStrategyAnyAllow,
+		roles:    []string{"user", "moderator"},
+		resource: "game", endpoint: "delete",
+		wantRole: "moderator",
+	}, {
+		given:    "The first role denies, the second one grants, deny overrides",
+		strategy: domain.RoleStrategyDenyOverrides,
+		roles:    []string{"user", "moderator"},
+		resource: "game", endpoint: "delete",
+		wantErr: errorUtils.ErrForbidden,
+	}, {
+		given:    "A role with no permission for the endpoint does not deny, deny overrides",
+		strategy: domain.RoleStrategyDenyOverrides,
+		roles:    []string{"moderator", "user"},
+		resource: "game", endpoint: "read",
+		wantRole: "user",
+	}, {
+		given:    "The first role granting access wins",
+		strategy: domain.RoleStrategyAnyAllow,
+		roles:    []string{"user", "moderator"},
+		resource: "library", endpoint: "read",
+		path:     "3",
+		wantRole: "user",
+	}, {
+		given:    "A role whose rules are violated leaves the decision to the next one",
+		strategy: domain.RoleStrategyAnyAllow,
+		roles:    []string{"user", "moderator"},
+		resource: "library", endpoint: "read",
+		path:     "4",
+		wantRole: "moderator",
+	}, {
+		given:    "A wildcard grants access to any resource",
+		strategy: domain.RoleStrategyAnyAllow,
+		roles:    []string{"user", "admin"},
+		resource: "sync_job", endpoint: "delete",
+		wantRole: "admin",
+	}, {
+		given:    "No role grants access",
+		strategy: domain.RoleStrategyAnyAllow,
+		roles:    []string{"user", "support"},
+		resource: "game", endpoint: "delete",
+		wantErr: errorUtils.ErrForbidden,
+	}, {
+		given:    "No role knows the endpoint",
+		strategy: domain.RoleStrategyAnyAllow,
+		roles:    []string{"support", "guest"},
+		resource: "game", endpoint: "read",
+		wantErr: errorUtils.ErrRoleUnknown,
+	}, {
+		given:    "No role at all",
+		strategy: domain.RoleStrategyAnyAllow,
+		resource: "game", endpoint: "read",
+		wantErr: errorUtils.ErrNoRole,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			decision, _, err := authorizeRoles(tt.strategy, tt.roles, tt.resource, tt.endpoint, tt.path)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantRole, decision.Role)
+			assert.Equal(t, tt.strategy, decision.Strategy)
+			assert.Equal(t, tt.roles, decision.Roles)
+		})
+	}
+}
+
+func TestAuthorizeRoles_ReportsRuleViolation(t *testing.T) {
+	decision, _, err := authorizeRoles(domain.RoleStrategyAnyAllow, []string{"support", "user"}, "library", "read", "4")
+
+	assert.EqualValues(t, "path rule violation: ensure 'id' = '3', instead got: '4'", err.Error())
+	assert.Empty(t, decision.Role)
+}
+
+func TestAuthorizeRoles_EnforcesGrantingRoleOnly(t *testing.T) {
+	_, u, err := authorizeRoles(domain.RoleStrategyAnyAllow, []string{"user", "moderator"}, "library", "read", "3")
+	assert.Nil(t, err)
+	assert.Empty(t, u.Query().Get("limit"))
+
+	_, u, err = authorizeRoles(domain.RoleStrategyAnyAllow, []string{"user", "moderator"}, "library", "read", "4")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "10", u.Query().Get("limit"))
+}
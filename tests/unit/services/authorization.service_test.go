@@ -338,3 +338,22 @@ func (s *AuthorizationServiceTestSuite) TestRBAC_Authorize() {
 		})
 	}
 }
+
+func TestRBAC_UserFollowsOwnSyncJobs(t *testing.T) {
+	service := services.NewAuthorizationService("../../../role-based-access.yml")
+	authorize := func(pathUserId string, endpoint string) error {
+		ctx := context.WithValue(context.Background(), domain.RbacUserId(), uint64(3))
+		ctx = context.WithValue(ctx, domain.RbacRouteParams(), map[string]string{"id": pathUserId, "jobId": "12"})
+		req, _ := http.NewRequest(http.MethodGet, "http://api.example.com/users/"+pathUserId+"/sync-jobs/12", nil)
+		_, err := service.AuthorizeRoles(ctx, req.URL, []string{"user"}, "user_sync_job", endpoint)
+		return err
+	}
+
+	for _, endpoint := range []string{"read", "delete"} {
+		assert.Nil(t, authorize("3", endpoint), endpoint)
+		assert.NotNil(t, authorize("4", endpoint), endpoint)
+	}
+	//any job, whoever it belongs to, is for admins
+	_, err := service.AuthorizeRoles(context.WithValue(context.Background(), domain.RbacUserId(), uint64(3)), nil, []string{"user"}, "sync_job", "read")
+	assert.NotNil(t, err)
+}
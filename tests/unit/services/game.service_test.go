@@ -213,7 +213,7 @@ func (s *GameServiceTestSuite) TestGamesService_DeleteGame_ErrorDeletingGame() {
 }
 
 func (s *GameServiceTestSuite) TestGamesService_GetAll_Success() {
-	s.mockRepository.SetGetAllGameDomain(func() ([]domain.Game, errorUtils.EntityError) {
+	s.mockRepository.SetGetAllGameDomain(func(query *domain.GameQuery) ([]domain.Game, uint64, errorUtils.EntityError) {
 		return []domain.Game{
 			{
 				ID:        1,
@@ -233,12 +233,17 @@ func (s *GameServiceTestSuite) TestGamesService_GetAll_Success() {
 				Developer: "Psyonix3",
 				Publisher: "Psyonix3",
 			},
-		}, nil
+		}, 3, nil
 	})
-	games, err := services.GamesService.GetAllGames()
+	page, err := services.GamesService.GetAllGames(domain.NewGameQuery())
 	t := s.T()
 	assert.Nil(t, err)
-	assert.NotNil(t, games)
+	assert.NotNil(t, page)
+	assert.EqualValues(t, 3, page.Total)
+	assert.EqualValues(t, 1, page.Page)
+	assert.False(t, page.HasNext())
+	assert.False(t, page.HasPrev())
+	games := page.Items
 	assert.EqualValues(t, games[0].ID, 1)
 	assert.EqualValues(t, games[0].Title, "Rocket League1")
 	assert.EqualValues(t, games[0].Developer, "Psyonix1")
@@ -255,13 +260,76 @@ func (s *GameServiceTestSuite) TestGamesService_GetAll_Success() {
 
 func (s *GameServiceTestSuite) TestGamesService_GetAllGames_ErrorGettingGames() {
 	expectedErr := errorUtils.NewInternalServerError("error getting games")
-	s.mockRepository.SetGetAllGameDomain(func() ([]domain.Game, errorUtils.EntityError) {
-		return nil, expectedErr
+	s.mockRepository.SetGetAllGameDomain(func(query *domain.GameQuery) ([]domain.Game, uint64, errorUtils.EntityError) {
+		return nil, 0, expectedErr
 	})
 
-	games, err := services.GamesService.GetAllGames()
+	games, err := services.GamesService.GetAllGames(domain.NewGameQuery())
 	t := s.T()
 	assert.NotNil(t, err)
 	assert.Nil(t, games)
 	assert.Equal(t, expectedErr, err)
 }
+
+func (s *GameServiceTestSuite) TestGamesService_GetAllGames_InvalidQuery() {
+	query := domain.NewGameQuery()
+	query.PageSize = domain.MaxGamePageSize + 1
+
+	games, err := services.GamesService.GetAllGames(query)
+	t := s.T()
+	assert.NotNil(t, err)
+	assert.Nil(t, games)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+}
+
+func (s *GameServiceTestSuite) TestGamesService_GetAllGames_InvalidSort() {
+	query := domain.NewGameQuery()
+	query.Sort = "developer"
+
+	games, err := services.GamesService.GetAllGames(query)
+	t := s.T()
+	assert.Nil(t, games)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, "sort should be one of id, title, releaseDate, created_at", err.Message())
+}
+
+func (s *GameServiceTestSuite) TestGamesService_GetAllGames_PageTooFar() {
+	query := domain.NewGameQuery()
+	query.Page = domain.MaxGamePage + 1
+
+	games, err := services.GamesService.GetAllGames(query)
+	t := s.T()
+	assert.Nil(t, games)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, "page should be between 1 and 1000000", err.Message())
+}
+
+func (s *GameServiceTestSuite) TestGamesService_GetAllGames_PageLinks() {
+	s.mockRepository.SetGetAllGameDomain(func(query *domain.GameQuery) ([]domain.Game, uint64, errorUtils.EntityError) {
+		return []domain.Game{{ID: 11}, {ID: 12}}, 30, nil
+	})
+	query := domain.NewGameQuery()
+	query.Page = 2
+	query.PageSize = 10
+
+	page, err := services.GamesService.GetAllGames(query)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.True(t, page.HasNext())
+	assert.True(t, page.HasPrev())
+}
+
+func (s *GameServiceTestSuite) TestGamesService_ExistsWithSteamID() {
+	var received *domain.GameQuery
+	s.mockRepository.SetGetAllGameDomain(func(query *domain.GameQuery) ([]domain.Game, uint64, errorUtils.EntityError) {
+		received = query
+		return []domain.Game{{ID: 1, SteamId: "440"}}, 1, nil
+	})
+
+	exists, err := services.GamesService.ExistsWithSteamID("440")
+	t := s.T()
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	assert.EqualValues(t, "440", received.SteamId)
+	assert.EqualValues(t, 1, received.PageSize)
+}
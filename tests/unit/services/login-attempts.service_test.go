@@ -0,0 +1,61 @@
+package services
+
+import (
+	"GamesAPI/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type LoginAttemptServiceTestSuite struct {
+	suite.Suite
+	service services.LoginAttemptServiceInterface
+	now     time.Time
+}
+
+func TestLoginAttemptServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginAttemptServiceTestSuite))
+}
+
+func (s *LoginAttemptServiceTestSuite) BeforeTest(_, _ string) {
+	s.service = services.NewLoginAttemptService(services.LoginAttemptConfig{
+		MaxPerAccount: 2,
+		MaxPerIP:      10,
+		Window:        time.Minute,
+		Lockout:       5 * time.Minute,
+	})
+	s.now = time.Now()
+}
+
+func (s *LoginAttemptServiceTestSuite) TestLockout_ExpiresAfterLockoutDuration() {
+	s.service.RecordFailure("dev@golang.com", "192.0.2.1", s.now)
+	assert.EqualValues(s.T(), 0, s.service.LockedFor("dev@golang.com", "192.0.2.1", s.now))
+
+	s.service.RecordFailure("dev@golang.com", "192.0.2.1", s.now)
+	assert.EqualValues(s.T(), 5*time.Minute, s.service.LockedFor("dev@golang.com", "192.0.2.1", s.now))
+	assert.EqualValues(s.T(), 0, s.service.LockedFor("dev@golang.com", "192.0.2.1", s.now.Add(5*time.Minute)))
+}
+
+func (s *LoginAttemptServiceTestSuite) TestLockout_EmailIsNormalized() {
+	s.service.RecordFailure("Dev@Golang.com", "192.0.2.1", s.now)
+	s.service.RecordFailure(" dev@golang.com", "192.0.2.2", s.now)
+	assert.True(s.T(), s.service.LockedFor("dev@golang.com", "192.0.2.3", s.now) > 0)
+}
+
+func (s *LoginAttemptServiceTestSuite) TestLockout_FailuresOutsideWindowAreForgotten() {
+	s.service.RecordFailure("dev@golang.com", "192.0.2.1", s.now)
+	s.service.RecordFailure("dev@golang.com", "192.0.2.1", s.now.Add(2*time.Minute))
+	assert.EqualValues(s.T(), 0, s.service.LockedFor("dev@golang.com", "192.0.2.1", s.now.Add(2*time.Minute)))
+}
+
+func (s *LoginAttemptServiceTestSuite) TestDeleteExpired() {
+	s.service.RecordFailure("dev@golang.com", "192.0.2.1", s.now)
+	s.service.RecordFailure("dev@golang.com", "192.0.2.1", s.now)
+
+	//still locked out
+	assert.EqualValues(s.T(), 1, s.service.DeleteExpired(s.now.Add(2*time.Minute)))
+	assert.True(s.T(), s.service.LockedFor("dev@golang.com", "", s.now.Add(2*time.Minute)) > 0)
+
+	assert.EqualValues(s.T(), 1, s.service.DeleteExpired(s.now.Add(10*time.Minute)))
+}
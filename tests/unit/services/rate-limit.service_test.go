@@ -0,0 +1,94 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var testRateLimits = domain.RateLimits{
+	"external": {Requests: 2, Period: time.Minute, Burst: 3},
+}
+
+func TestRateLimit_BurstThenRefill(t *testing.T) {
+	service := services.NewRateLimitService(testRateLimits, services.NewMemoryRateLimitStore())
+	now := time.Now()
+
+	for i := 2; i >= 0; i-- {
+		result, ok, err := service.Allow("external", []string{"key:1"}, now)
+		require.Nil(t, err)
+		require.True(t, ok)
+		assert.True(t, result.Allowed)
+		assert.EqualValues(t, 3, result.Limit)
+		assert.EqualValues(t, i, result.Remaining)
+	}
+
+	result, _, _ := service.Allow("external", []string{"key:1"}, now)
+	assert.False(t, result.Allowed)
+	assert.EqualValues(t, 30*time.Second, result.RetryAfter)
+	assert.EqualValues(t, 90*time.Second, result.Reset)
+
+	//2 requests per minute, one is back after 30 seconds
+	result, _, _ = service.Allow("external", []string{"key:1"}, now.Add(30*time.Second))
+	assert.True(t, result.Allowed)
+	assert.EqualValues(t, 0, result.Remaining)
+}
+
+func TestRateLimit_ClientsAndGroupsAreSeparate(t *testing.T) {
+	limits := domain.RateLimits{
+		"external":                  {Requests: 1, Period: time.Minute},
+		domain.DefaultRateLimitGroup: {Requests: 1, Period: time.Minute},
+	}
+	service := services.NewRateLimitService(limits, services.NewMemoryRateLimitStore())
+	now := time.Now()
+
+	first, _, _ := service.Allow("external", []string{"key:1"}, now)
+	otherClient, _, _ := service.Allow("external", []string{"key:2"}, now)
+	otherGroup, _, _ := service.Allow("core", []string{"key:1"}, now)
+	again, _, _ := service.Allow("external", []string{"key:1"}, now)
+
+	assert.True(t, first.Allowed)
+	assert.True(t, otherClient.Allowed)
+	assert.True(t, otherGroup.Allowed)
+	assert.False(t, again.Allowed)
+}
+
+func TestRateLimit_UnthrottledGroup(t *testing.T) {
+	service := services.NewRateLimitService(testRateLimits, services.NewMemoryRateLimitStore())
+	_, ok, err := service.Allow("core", []string{"key:1"}, time.Now())
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, ok, _ = services.NewRateLimitService(nil, services.NewMemoryRateLimitStore()).Allow("external", []string{"key:1"}, time.Now())
+	assert.False(t, ok)
+}
+
+func TestRateLimit_DeleteExpired(t *testing.T) {
+	service := services.NewRateLimitService(testRateLimits, services.NewMemoryRateLimitStore())
+	now := time.Now()
+	_, _, _ = service.Allow("external", []string{"key:1"}, now)
+	_, _, _ = service.Allow("external", []string{"key:2"}, now)
+
+	assert.EqualValues(t, 0, service.DeleteExpired(now))
+	//both buckets are full again after half a minute
+	assert.EqualValues(t, 2, service.DeleteExpired(now.Add(30*time.Second)))
+}
+
+//a request refused by the user bucket must not eat into the bucket of the key, and the other way around
+func TestRateLimit_RefusedTakesNothing(t *testing.T) {
+	limits := domain.RateLimits{"external": {Requests: 1, Period: time.Minute}}
+	service := services.NewRateLimitService(limits, services.NewMemoryRateLimitStore())
+	now := time.Now()
+
+	first, _, _ := service.Allow("external", []string{"key:1", "user:1"}, now)
+	assert.True(t, first.Allowed)
+	//user 1 is out, key 2 is left untouched
+	refused, _, _ := service.Allow("external", []string{"key:2", "user:1"}, now)
+	assert.False(t, refused.Allowed)
+	assert.EqualValues(t, 60*time.Second, refused.RetryAfter)
+	other, _, _ := service.Allow("external", []string{"key:2", "user:2"}, now)
+	assert.True(t, other.Allowed)
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+const rbacPolicyTestFile = "../resources/rbac-roles-test.yml"
+
+type RbacPolicyServiceTestSuite struct {
+	suite.Suite
+	mockRepository    mocks.RbacPolicyRepoMockInterface
+	mockAuthorization mocks.AuthorizationServiceMockInterface
+	service           services.RbacPolicyServiceInterface
+	//what the mocked repository holds
+	roles    []domain.RbacRole
+	versions []domain.RbacPolicyVersion
+	reloads  int
+}
+
+func TestRbacPolicyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(RbacPolicyServiceTestSuite))
+}
+
+func (s *RbacPolicyServiceTestSuite) SetupSuite() {
+	mock := &mocks.RbacPolicyRepoMock{}
+	authorizationMock := &mocks.AuthorizationServiceMock{}
+	s.mockRepository = mock
+	s.mockAuthorization = authorizationMock
+	domain.RbacPolicyRepo = mock
+	services.AuthorizationService = authorizationMock
+}
+
+func (s *RbacPolicyServiceTestSuite) BeforeTest(_, _ string) {
+	s.roles, s.versions, s.reloads = nil, nil, 0
+	s.mockRepository.SetGetRoles(func() ([]domain.RbacRole, errorUtils.EntityError) {
+		return s.roles, nil
+	})
+	s.mockRepository.SetGetLatestVersion(func() (*domain.RbacPolicyVersion, errorUtils.EntityError) {
+		if len(s.versions) == 0 {
+			return nil, errorUtils.NewNotFoundError("record not found")
+		}
+		latest := s.versions[len(s.versions)-1]
+		return &latest, nil
+	})
+	s.mockRepository.SetGetVersion(func(version uint64) (*domain.RbacPolicyVersion, errorUtils.EntityError) {
+		if version < 1 || version > uint64(len(s.versions)) {
+			return nil, errorUtils.NewNotFoundError("record not found")
+		}
+		return &s.versions[version-1], nil
+	})
+	s.mockRepository.SetSave(func(roles []domain.RbacRole, version *domain.RbacPolicyVersion) errorUtils.EntityError {
+		if version.Version != uint64(len(s.versions)+1) {
+			return errorUtils.NewConflictError("rbac policy was changed meanwhile")
+		}
+		s.roles, s.versions = roles, append(s.versions, *version)
+		return nil
+	})
+	s.mockAuthorization.SetReload(func() error {
+		s.reloads++
+		return nil
+	})
+	s.service = services.NewRbacPolicyService(nil)
+	require.Nil(s.T(), s.service.Import(rbacPolicyTestFile))
+}
+
+func (s *RbacPolicyServiceTestSuite) TestImport() {
+	t := s.T()
+	require.Len(t, s.versions, 1)
+	assert.EqualValues(t, 1, s.versions[0].Version)
+	assert.EqualValues(t, 0, s.versions[0].AuthorId)
+	assert.EqualValues(t, "imported from "+rbacPolicyTestFile, s.versions[0].Comment)
+	assert.NotEmpty(t, s.versions[0].Hash)
+	//the authorization service reads the database once the import is done
+	assert.EqualValues(t, 0, s.reloads)
+
+	policy, err := s.service.GetPolicy()
+	require.Nil(t, err)
+	assert.Contains(t, policy, "moderator")
+
+	//the database already holds a policy
+	assert.Nil(t, s.service.Import(rbacPolicyTestFile))
+	assert.Len(t, s.versions, 1)
+}
+
+func (s *RbacPolicyServiceTestSuite) TestPutRole() {
+	t := s.T()
+	role := domain.RbacPolicyRole{
+		Inherits:  []string{"user"},
+		Resources: domain.Resource{"game": domain.Endpoint{domain.RbacWildcard: domain.Permission{Allow: true}}},
+	}
+	update, err := s.service.PutRole("editor", role, 7)
+	require.Nil(t, err)
+	assert.EqualValues(t, 2, update.Version.Version)
+	assert.EqualValues(t, 7, update.Version.AuthorId)
+	assert.EqualValues(t, "put role editor", update.Version.Comment)
+	//user denies game.delete, which is more specific than game.*
+	assert.Contains(t, update.Warnings, "editor.game.delete: inherited from user, takes precedence over editor.game.*")
+	assert.EqualValues(t, 1, s.reloads)
+
+	policy, _ := s.service.GetPolicy()
+	assert.EqualValues(t, []string{"user"}, policy["editor"].Inherits)
+	rbac, resolveErr := policy.Resolve()
+	require.Nil(t, resolveErr)
+	permission, _ := rbac.Permission("editor", "game", "update")
+	assert.True(t, permission.Allow)
+	//the snapshot is the policy the authorization service reads back
+	snapshot, _ := domain.ParseRbacPolicy([]byte(update.Version.Content))
+	assert.EqualValues(t, policy, snapshot)
+}
+
+func (s *RbacPolicyServiceTestSuite) TestPutRole_Refused() {
+	tests := []struct {
+		given string
+		name  string
+		role  domain.RbacPolicyRole
+	}{{
+		given: "A role name the lowercased roles of users never match",
+		name:  "Editor",
+		role:  domain.RbacPolicyRole{Resources: domain.Resource{"game": domain.Endpoint{"read": {Allow: true}}}},
+	}, {
+		given: "A malformed resource name",
+		name:  "editor",
+		role:  domain.RbacPolicyRole{Resources: domain.Resource{"game.read": domain.Endpoint{"read": {Allow: true}}}},
+	}, {
+		given: "An unknown parent",
+		name:  "editor",
+		role:  domain.RbacPolicyRole{Inherits: []string{"ghost"}, Resources: domain.Resource{"game": domain.Endpoint{"read": {Allow: true}}}},
+	}, {
+		given: "A role with no resources",
+		name:  "editor",
+		role:  domain.RbacPolicyRole{},
+	}}
+	for _, tt := range tests {
+		s.Run(tt.given, func() {
+			_, err := s.service.PutRole(tt.name, tt.role, 7)
+			require.NotNil(s.T(), err)
+			assert.EqualValues(s.T(), http.StatusUnprocessableEntity, err.Status())
+			assert.Len(s.T(), s.versions, 1)
+			assert.EqualValues(s.T(), 0, s.reloads)
+		})
+	}
+}
+
+func (s *RbacPolicyServiceTestSuite) TestDeleteRole() {
+	t := s.T()
+	_, err := s.service.DeleteRole("ghost", 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+
+	update, err := s.service.DeleteRole("support", 7)
+	require.Nil(t, err)
+	assert.EqualValues(t, 2, update.Version.Version)
+	policy, _ := s.service.GetPolicy()
+	assert.NotContains(t, policy, "support")
+
+	//a role others inherit from cannot be deleted
+	_, err = s.service.PutRole("editor", domain.RbacPolicyRole{Inherits: []string{"moderator"}, Resources: domain.Resource{"game": domain.Endpoint{"read": {Allow: true}}}}, 7)
+	require.Nil(t, err)
+	_, err = s.service.DeleteRole("moderator", 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, err.Status())
+	assert.Contains(t, err.Message(), "inherits unknown role 'moderator'")
+}
+
+func (s *RbacPolicyServiceTestSuite) TestPermissions() {
+	t := s.T()
+	update, err := s.service.PutPermission("support", "game", "read", domain.Permission{Allow: true}, 7)
+	require.Nil(t, err)
+	assert.EqualValues(t, "put permission support.game.read", update.Version.Comment)
+
+	//an unknown operator is a lint error
+	badRule := domain.Permission{Allow: true, Ensure: domain.Ensurer{Query: []domain.Rule{{Key: "id", Operator: "=~", Value: "1"}}}}
+	_, err = s.service.PutPermission("support", "game", "read", badRule, 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, err.Status())
+	assert.Contains(t, err.Message(), "unknown operator")
+
+	_, err = s.service.PutPermission("ghost", "game", "read", domain.Permission{Allow: true}, 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+
+	_, err = s.service.DeletePermission("support", "game", "update", 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+
+	_, err = s.service.DeletePermission("support", "game", "read", 7)
+	require.Nil(t, err)
+	policy, _ := s.service.GetPolicy()
+	assert.NotContains(t, policy["support"].Resources, "game")
+	assert.Len(t, s.versions, 3)
+}
+
+func (s *RbacPolicyServiceTestSuite) TestAdminKeepsPolicy() {
+	t := s.T()
+	_, err := s.service.DeleteRole(domain.AdminRoleName, 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+	assert.EqualValues(t, "the admin role would lose rbac.read, rbac.update, rbac.rollback, and nobody could manage the rbac policy anymore", err.Message())
+
+	_, err = s.service.PutPermission(domain.AdminRoleName, domain.RbacWildcard, domain.RbacWildcard, domain.Permission{Allow: false}, 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+	_, err = s.service.PutPermission(domain.AdminRoleName, "rbac", "rollback", domain.Permission{Allow: false}, 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, "the admin role would lose rbac.rollback, and nobody could manage the rbac policy anymore", err.Message())
+	assert.Len(t, s.versions, 1)
+	assert.EqualValues(t, 0, s.reloads)
+
+	//other resources are the admins' call
+	_, err = s.service.PutPermission(domain.AdminRoleName, "game", "delete", domain.Permission{Allow: false}, 7)
+	assert.Nil(t, err)
+}
+
+func (s *RbacPolicyServiceTestSuite) TestAdminKeepsPolicy_Routes() {
+	surface := &domain.RbacSurface{
+		Resources: map[string]map[string]bool{
+			"game":    {"read": true, "delete": true},
+			"library": {"read": true},
+			"rbac":    {"read": true, "explain": true},
+		},
+		ContextKeys: map[string]bool{domain.RbacUserId(): true},
+	}
+	service := services.NewRbacPolicyService(surface)
+
+	//the routes say which actions there are
+	_, err := service.PutPermission(domain.AdminRoleName, "rbac", "explain", domain.Permission{Allow: false}, 7)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), "the admin role would lose rbac.explain, and nobody could manage the rbac policy anymore", err.Message())
+	_, err = service.PutPermission(domain.AdminRoleName, "rbac", "update", domain.Permission{Allow: false}, 7)
+	assert.Nil(s.T(), err)
+}
+
+func (s *RbacPolicyServiceTestSuite) TestRollback() {
+	t := s.T()
+	_, err := s.service.DeleteRole("support", 7)
+	require.Nil(t, err)
+
+	update, err := s.service.Rollback(1, 7)
+	require.Nil(t, err)
+	assert.EqualValues(t, 3, update.Version.Version)
+	assert.EqualValues(t, "rollback to version 1", update.Version.Comment)
+	assert.EqualValues(t, s.versions[0].Hash, update.Version.Hash)
+	policy, _ := s.service.GetPolicy()
+	assert.Contains(t, policy, "support")
+
+	_, err = s.service.Rollback(9, 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func (s *RbacPolicyServiceTestSuite) TestChangedMeanwhile() {
+	s.mockRepository.SetSave(func([]domain.RbacRole, *domain.RbacPolicyVersion) errorUtils.EntityError {
+		return errorUtils.NewConflictError("rbac policy is at version 2, it was changed meanwhile")
+	})
+	_, err := s.service.DeleteRole("support", 7)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusConflict, err.Status())
+	assert.EqualValues(s.T(), 0, s.reloads)
+}
+
+func (s *RbacPolicyServiceTestSuite) TestReloadFailed() {
+	s.mockAuthorization.SetReload(func() error {
+		return errors.New("database is down")
+	})
+	_, err := s.service.DeleteRole("support", 7)
+	require.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusInternalServerError, err.Status())
+	assert.Contains(s.T(), err.Message(), "version 2 was stored")
+}
+
+func TestRbacPolicyService_ReadFromFile(t *testing.T) {
+	//until RBAC_SOURCE is database, the policy is changed in its file
+	_, err := services.RbacPolicyService.GetPolicy()
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+	assert.Contains(t, err.Message(), "RBAC_SOURCE=database")
+
+	_, err = services.RbacPolicyService.PutRole("editor", domain.RbacPolicyRole{}, 7)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/authUtils"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type RefreshTokenServiceTestSuite struct {
+	suite.Suite
+	service services.RefreshTokenServiceInterface
+}
+
+func TestRefreshTokenServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenServiceTestSuite))
+}
+
+//the flow goes through the session service, so we use the real in-memory stores
+func (s *RefreshTokenServiceTestSuite) BeforeTest(_, _ string) {
+	domain.UserSessionRepo = domain.NewUserAuthTokenRepository()
+	domain.RefreshTokenRepo = domain.NewRefreshTokenRepository()
+	s.service = services.NewRefreshTokenService(time.Minute, time.Hour)
+}
+
+func (s *RefreshTokenServiceTestSuite) TestIssueTokens() {
+	tokens, err := s.service.IssueTokens(testUserId)
+	t := s.T()
+	require.Nil(t, err)
+	assert.NotEqual(t, tokens.AccessToken, tokens.RefreshToken)
+	assert.True(t, services.UserSessionService.ExistsSession(tokens.AccessToken))
+	assert.True(t, tokens.AccessExpiresAt < tokens.RefreshExpiresAt)
+
+	//the access token follows the configured lifetime
+	lifetime := time.Duration(tokens.AccessExpiresAt - time.Now().UnixNano())
+	assert.True(t, lifetime <= time.Minute && lifetime > 50*time.Second)
+}
+
+func (s *RefreshTokenServiceTestSuite) TestRefresh_Rotates() {
+	first, _ := s.service.IssueTokens(testUserId)
+
+	second, err := s.service.Refresh(first.RefreshToken)
+	t := s.T()
+	require.Nil(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	assert.NotEqual(t, first.AccessToken, second.AccessToken)
+	assert.True(t, services.UserSessionService.ExistsSession(second.AccessToken))
+
+	session, _ := services.UserSessionService.GetSession(second.AccessToken)
+	assert.EqualValues(t, testUserId, session.UserId)
+}
+
+func (s *RefreshTokenServiceTestSuite) TestRefresh_ReuseRevokesFamily() {
+	first, _ := s.service.IssueTokens(testUserId)
+	second, _ := s.service.Refresh(first.RefreshToken)
+	//another login of the same user must survive
+	other, _ := s.service.IssueTokens(testUserId)
+
+	_, err := s.service.Refresh(first.RefreshToken)
+	t := s.T()
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+
+	assert.False(t, services.UserSessionService.ExistsSession(first.AccessToken))
+	assert.False(t, services.UserSessionService.ExistsSession(second.AccessToken))
+	_, err = s.service.Refresh(second.RefreshToken)
+	assert.NotNil(t, err)
+
+	assert.True(t, services.UserSessionService.ExistsSession(other.AccessToken))
+	_, err = s.service.Refresh(other.RefreshToken)
+	assert.Nil(t, err)
+}
+
+func (s *RefreshTokenServiceTestSuite) TestRefresh_Unknown() {
+	_, err := s.service.Refresh("not-a-token")
+	t := s.T()
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+}
+
+func (s *RefreshTokenServiceTestSuite) TestRefresh_Expired() {
+	s.service = services.NewRefreshTokenService(time.Minute, -time.Second)
+	tokens, _ := s.service.IssueTokens(testUserId)
+
+	_, err := s.service.Refresh(tokens.RefreshToken)
+	t := s.T()
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+}
+
+func (s *RefreshTokenServiceTestSuite) TestLogoutEverywhere_RevokesRefreshTokens() {
+	tokens, _ := s.service.IssueTokens(testUserId)
+	_, _ = services.UserSessionService.RevokeUserSessions(testUserId)
+
+	_, err := s.service.Refresh(tokens.RefreshToken)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *RefreshTokenServiceTestSuite) TestIssueTokens_Jwt() {
+	key, _ := authUtils.NewJwtKey(authUtils.JwtAlgorithmHS256, "test", []byte("a-test-secret-that-is-long-enough-for-hs256"))
+	keys, _ := authUtils.NewJwtKeySet("test", key)
+	roleService := &mocks.UserRoleMock{}
+	roleService.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{UserID: userId, Name: "user"}}, nil
+	})
+	previousRoles, previousJwt := services.UserRoleService, services.JwtService
+	services.UserRoleService, services.JwtService = roleService, services.NewJwtService(keys)
+	defer func() {
+		services.UserRoleService, services.JwtService = previousRoles, previousJwt
+	}()
+
+	tokens, err := s.service.IssueTokens(testUserId)
+	t := s.T()
+	require.Nil(t, err)
+	//a JWT is not stored anywhere, but the refresh token is
+	assert.False(t, services.UserSessionService.ExistsSession(tokens.AccessToken))
+	claims, err := services.JwtService.Verify(tokens.AccessToken)
+	require.Nil(t, err)
+	assert.EqualValues(t, testUserId, claims.UserId)
+	assert.EqualValues(t, []string{"user"}, claims.Roles)
+
+	refreshed, err := s.service.Refresh(tokens.RefreshToken)
+	require.Nil(t, err)
+	_, err = services.JwtService.Verify(refreshed.AccessToken)
+	assert.Nil(t, err)
+}
+
+func (s *RefreshTokenServiceTestSuite) TestLogout_RevokesSessionAndRefreshToken() {
+	tokens, _ := s.service.IssueTokens(testUserId)
+	other, _ := s.service.IssueTokens(testUserId)
+
+	err := s.service.Logout(testUserId, tokens.AccessToken, "")
+	t := s.T()
+	require.Nil(t, err)
+	assert.False(t, services.UserSessionService.ExistsSession(tokens.AccessToken))
+	_, err = s.service.Refresh(tokens.RefreshToken)
+	assert.NotNil(t, err)
+
+	//other logins are left alone
+	assert.True(t, services.UserSessionService.ExistsSession(other.AccessToken))
+}
+
+func (s *RefreshTokenServiceTestSuite) TestLogout_RefreshTokenOfAnotherUser() {
+	mine, _ := s.service.IssueTokens(testUserId)
+	theirs, _ := s.service.IssueTokens(testUserId + 1)
+
+	err := s.service.Logout(testUserId, mine.AccessToken, theirs.RefreshToken)
+	t := s.T()
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+	_, err = s.service.Refresh(theirs.RefreshToken)
+	assert.Nil(t, err)
+}
+
+func (s *RefreshTokenServiceTestSuite) TestLogout_UnknownSession() {
+	err := s.service.Logout(testUserId, "not-a-session", "")
+	assert.EqualValues(s.T(), http.StatusNotFound, err.Status())
+}
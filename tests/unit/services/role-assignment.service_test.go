@@ -0,0 +1,177 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type RoleAssignmentServiceTestSuite struct {
+	suite.Suite
+	mockRepository     mocks.UserRoleRepoMockInterface
+	mockUserRepository mocks.UserRepoMockInterface
+	//roles held through the mocked repository, by id
+	stored map[uint64]*domain.UserRole
+}
+
+func TestRoleAssignmentServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleAssignmentServiceTestSuite))
+}
+
+func (s *RoleAssignmentServiceTestSuite) SetupSuite() {
+	mock := &mocks.UserRoleRepoMock{}
+	userMock := &mocks.UserRepoMock{}
+	s.mockRepository = mock
+	s.mockUserRepository = userMock
+	domain.UserRoleRepo = mock
+	domain.UserRepo = userMock
+	//roles user, moderator, support and admin
+	services.AuthorizationService = services.NewAuthorizationService("../resources/rbac-roles-test.yml")
+}
+
+func (s *RoleAssignmentServiceTestSuite) BeforeTest(_, _ string) {
+	s.stored = map[uint64]*domain.UserRole{
+		1: {ID: 1, UserID: 1, Name: "admin"},
+		2: {ID: 2, UserID: 2, Name: "user"},
+	}
+	s.mockUserRepository.SetGetUserDomain(func(id uint64) (*domain.User, errorUtils.EntityError) {
+		if id > 3 {
+			return nil, errorUtils.NewNotFoundError("record not found")
+		}
+		return &domain.User{ID: id}, nil
+	})
+	s.mockRepository.SetGetRole(func(id uint64) (*domain.UserRole, errorUtils.EntityError) {
+		role, exists := s.stored[id]
+		if !exists {
+			return nil, errorUtils.NewNotFoundError("record not found")
+		}
+		ret := *role
+		return &ret, nil
+	})
+	find := func(keep func(domain.UserRole) bool) []domain.UserRole {
+		var roles []domain.UserRole
+		for _, role := range s.stored {
+			if keep(*role) {
+				roles = append(roles, *role)
+			}
+		}
+		return roles
+	}
+	s.mockRepository.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return find(func(role domain.UserRole) bool { return role.UserID == userId }), nil
+	})
+	s.mockRepository.SetGetRolesByRoleName(func(name string) ([]domain.UserRole, errorUtils.EntityError) {
+		return find(func(role domain.UserRole) bool { return role.Name == name }), nil
+	})
+	s.mockRepository.SetCreateRole(func(role *domain.UserRole) (*domain.UserRole, errorUtils.EntityError) {
+		role.ID = uint64(len(s.stored) + 1)
+		stored := *role
+		s.stored[role.ID] = &stored
+		return role, nil
+	})
+	s.mockRepository.SetUpdateRole(func(role *domain.UserRole) (*domain.UserRole, errorUtils.EntityError) {
+		stored := *role
+		s.stored[role.ID] = &stored
+		return role, nil
+	})
+	s.mockRepository.SetDeleteRole(func(id uint64) errorUtils.EntityError {
+		delete(s.stored, id)
+		return nil
+	})
+}
+
+func (s *RoleAssignmentServiceTestSuite) TestAssignRole() {
+	t := s.T()
+	role, err := services.UserRoleService.AssignRole(2, " Moderator ")
+	require.Nil(t, err)
+	//users' roles are lowercased when checked
+	assert.EqualValues(t, "moderator", role.Name)
+	assert.EqualValues(t, 2, role.UserID)
+	assert.Len(t, s.stored, 3)
+}
+
+func (s *RoleAssignmentServiceTestSuite) TestAssignRole_Refused() {
+	tests := []struct {
+		given      string
+		userId     uint64
+		name       string
+		wantStatus int
+	}{{
+		given:      "A role the policy does not have",
+		userId:     2,
+		name:       "superuser",
+		wantStatus: http.StatusUnprocessableEntity,
+	}, {
+		given:      "No role name",
+		userId:     2,
+		name:       "",
+		wantStatus: http.StatusUnprocessableEntity,
+	}, {
+		given:      "A role the user already has",
+		userId:     2,
+		name:       "USER",
+		wantStatus: http.StatusConflict,
+	}, {
+		given:      "A user that does not exist",
+		userId:     9,
+		name:       "user",
+		wantStatus: http.StatusNotFound,
+	}}
+	for _, tt := range tests {
+		s.Run(tt.given, func() {
+			_, err := services.UserRoleService.AssignRole(tt.userId, tt.name)
+			require.NotNil(s.T(), err)
+			assert.EqualValues(s.T(), tt.wantStatus, err.Status())
+			assert.Len(s.T(), s.stored, 2)
+		})
+	}
+	_, err := services.UserRoleService.AssignRole(2, "superuser")
+	assert.EqualValues(s.T(), "role superuser is not in the rbac policy, expected one of admin, moderator, support, user", err.Message())
+}
+
+func (s *RoleAssignmentServiceTestSuite) TestChangeRole() {
+	t := s.T()
+	role, err := services.UserRoleService.ChangeRole(2, "support")
+	require.Nil(t, err)
+	assert.EqualValues(t, "support", role.Name)
+	assert.EqualValues(t, "support", s.stored[2].Name)
+
+	_, err = services.UserRoleService.ChangeRole(2, "superuser")
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, err.Status())
+
+	_, err = services.UserRoleService.ChangeRole(7, "user")
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func (s *RoleAssignmentServiceTestSuite) TestLastAdmin() {
+	t := s.T()
+	err := services.UserRoleService.RevokeRole(1)
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+	assert.EqualValues(t, "user 1 is the last admin, give the role to someone else first", err.Message())
+
+	_, err = services.UserRoleService.ChangeRole(1, "user")
+	require.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+
+	//a second role does not make the same user a second admin
+	_, err = services.UserRoleService.AssignRole(1, "user")
+	require.Nil(t, err)
+	assert.NotNil(t, services.UserRoleService.RevokeRole(1))
+
+	_, err = services.UserRoleService.AssignRole(2, "admin")
+	require.Nil(t, err)
+	assert.Nil(t, services.UserRoleService.RevokeRole(1))
+	assert.NotContains(t, s.stored, uint64(1))
+
+	//other roles are revoked freely
+	assert.Nil(t, services.UserRoleService.RevokeRole(2))
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"GamesAPI/src/External/Steam"
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+type SyncJobsServiceTestSuite struct {
+	suite.Suite
+	mockRepository  mocks.SyncJobRepoMockInterface
+	mockSteam       mocks.SteamUserMockInterface
+	mockGames       mocks.GameServiceMockInterface
+	mockLibrary     mocks.UserGamesServiceMockInterface
+	previousGames   services.GamesServiceInterface
+	previousLibrary services.UserGamesServiceInterface
+	previousSteam   Steam.ExternalSteamUserServiceInterface
+	mutex           sync.Mutex
+	jobs            map[uint64]domain.SyncJob
+	jobErrors       []domain.SyncJobError
+	syncService     services.SyncJobsServiceInterface
+}
+
+func TestSyncJobsServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncJobsServiceTestSuite))
+}
+
+func (s *SyncJobsServiceTestSuite) SetupSuite() {
+	repo := &mocks.SyncJobRepoMock{}
+	s.mockRepository = repo
+	domain.SyncJobRepo = repo
+
+	s.previousGames = services.GamesService
+	s.previousLibrary = services.UserGamesService
+	s.previousSteam = Steam.ExternalSteamUserService
+
+	games := &mocks.GameServiceMock{}
+	s.mockGames = games
+	services.GamesService = games
+
+	library := &mocks.UserGamesServiceMock{}
+	s.mockLibrary = library
+	services.UserGamesService = library
+
+	steam := &mocks.SteamUserMock{}
+	s.mockSteam = steam
+	Steam.ExternalSteamUserService = steam
+}
+
+func (s *SyncJobsServiceTestSuite) TearDownSuite() {
+	services.GamesService = s.previousGames
+	services.UserGamesService = s.previousLibrary
+	Steam.ExternalSteamUserService = s.previousSteam
+}
+
+// every test gets an in-memory job table, since the workers write to it concurrently
+func (s *SyncJobsServiceTestSuite) BeforeTest(_, _ string) {
+	s.jobs = map[uint64]domain.SyncJob{}
+	s.jobErrors = nil
+	s.mockRepository.SetGetByInstance(func(instance string, statuses ...string) ([]domain.SyncJob, errorUtils.EntityError) {
+		return nil, nil
+	})
+	s.mockRepository.SetCreate(func(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		job.ID = uint64(len(s.jobs) + 1)
+		s.jobs[job.ID] = *job
+		return job, nil
+	})
+	s.mockRepository.SetGet(func(jobId uint64) (*domain.SyncJob, errorUtils.EntityError) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		job, exists := s.jobs[jobId]
+		if !exists {
+			return nil, errorUtils.NewNotFoundError("record not found")
+		}
+		return &job, nil
+	})
+	s.mockRepository.SetUpdate(func(job *domain.SyncJob) (*domain.SyncJob, errorUtils.EntityError) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.jobs[job.ID] = *job
+		return job, nil
+	})
+	s.mockRepository.SetAddError(func(jobError *domain.SyncJobError) errorUtils.EntityError {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.jobErrors = append(s.jobErrors, *jobError)
+		return nil
+	})
+	s.mockLibrary.SetSyncLibraryEntry(func(entry *domain.UserGame) (*domain.UserGame, errorUtils.EntityError) {
+		return entry, nil
+	})
+	s.syncService = services.NewSyncJobsService(10, "api-1")
+}
+
+func (s *SyncJobsServiceTestSuite) AfterTest(_, _ string) {
+	s.syncService.Stop()
+}
+
+// waitForJob polls the job until a worker has finished it
+func (s *SyncJobsServiceTestSuite) waitForJob(jobId uint64) domain.SyncJob {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, _ := s.syncService.GetJob(jobId)
+		if job != nil && job.IsFinished() {
+			return *job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	s.T().Fatalf("sync job %d never finished", jobId)
+	return domain.SyncJob{}
+}
+
+func (s *SyncJobsServiceTestSuite) TestSync_CountsEveryOutcome() {
+	s.mockSteam.SetGetUserOwnedGames(func(userID string) ([]Steam.OwnedGame, error) {
+		return []Steam.OwnedGame{{AppId: "10"}, {AppId: "20"}, {AppId: "30"}}, nil
+	})
+	s.mockGames.SetGetGameBySteamID(func(id string) (*domain.Game, errorUtils.EntityError) {
+		if id == "10" {
+			return &domain.Game{ID: 1, SteamId: id}, nil
+		}
+		return nil, errorUtils.NewNotFoundError("no game with steam id " + id)
+	})
+	s.mockSteam.SetGetGameInfo(func(id string) (domain.Game, error) {
+		if id == "30" {
+			return domain.Game{}, errors.New("steam store unavailable")
+		}
+		return domain.Game{Title: "Portal", SteamId: id}, nil
+	})
+	s.mockGames.SetCreateGame(func(game *domain.Game) (*domain.Game, errorUtils.EntityError) {
+		game.ID = 2
+		return game, nil
+	})
+
+	s.syncService.Start(1)
+	job, err := s.syncService.EnqueueSync(&domain.User{ID: 3, SteamUserId: "steam-3"})
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, domain.SyncJobQueued, job.Status)
+
+	finished := s.waitForJob(job.ID)
+	assert.EqualValues(t, domain.SyncJobSucceeded, finished.Status)
+	assert.EqualValues(t, 3, finished.Total)
+	assert.EqualValues(t, 1, finished.Inserted)
+	assert.EqualValues(t, 1, finished.Skipped)
+	assert.EqualValues(t, 1, finished.Errored)
+	assert.NotNil(t, finished.StartedAt)
+	assert.NotNil(t, finished.FinishedAt)
+	assert.Len(t, s.jobErrors, 1)
+	assert.EqualValues(t, "30", s.jobErrors[0].AppId)
+}
+
+func (s *SyncJobsServiceTestSuite) TestSync_SteamFailure() {
+	s.mockSteam.SetGetUserOwnedGames(func(userID string) ([]Steam.OwnedGame, error) {
+		return nil, errors.New("steam api unavailable")
+	})
+
+	s.syncService.Start(1)
+	job, _ := s.syncService.EnqueueSync(&domain.User{ID: 3, SteamUserId: "steam-3"})
+
+	finished := s.waitForJob(job.ID)
+	t := s.T()
+	assert.EqualValues(t, domain.SyncJobFailed, finished.Status)
+	assert.EqualValues(t, "steam api unavailable", finished.Error)
+}
+
+func (s *SyncJobsServiceTestSuite) TestSync_CancelWhileQueued() {
+	//no worker is started, so the job stays in the queue until we cancel it
+	job, _ := s.syncService.EnqueueSync(&domain.User{ID: 3, SteamUserId: "steam-3"})
+	cancelled, err := s.syncService.CancelJob(job.ID)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, job.ID, cancelled.ID)
+
+	s.mockSteam.SetGetUserOwnedGames(func(userID string) ([]Steam.OwnedGame, error) {
+		t.Error("a cancelled job should not reach Steam")
+		return nil, nil
+	})
+	s.syncService.Start(1)
+	finished := s.waitForJob(job.ID)
+	assert.EqualValues(t, domain.SyncJobCancelled, finished.Status)
+}
+
+func (s *SyncJobsServiceTestSuite) TestSync_CancelFinishedJob() {
+	s.jobs[1] = domain.SyncJob{ID: 1, UserID: 3, Status: domain.SyncJobSucceeded}
+
+	job, err := s.syncService.CancelJob(1)
+	t := s.T()
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+}
+
+func (s *SyncJobsServiceTestSuite) TestSync_CancelUnknownJob() {
+	job, err := s.syncService.CancelJob(42)
+	t := s.T()
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func (s *SyncJobsServiceTestSuite) TestSync_QueueFull() {
+	s.syncService = services.NewSyncJobsService(1, "api-1")
+	_, err := s.syncService.EnqueueSync(&domain.User{ID: 3, SteamUserId: "steam-3"})
+	t := s.T()
+	assert.Nil(t, err)
+
+	job, err := s.syncService.EnqueueSync(&domain.User{ID: 3, SteamUserId: "steam-3"})
+	assert.Nil(t, job)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusServiceUnavailable, err.Status())
+	assert.EqualValues(t, domain.SyncJobFailed, s.jobs[2].Status)
+}
+
+func (s *SyncJobsServiceTestSuite) TestStart_FailsInterruptedJobs() {
+	//enqueued by a previous run of this server, as no worker was started
+	job, _ := s.syncService.EnqueueSync(&domain.User{ID: 3, SteamUserId: "steam-3"})
+	s.jobs[2] = domain.SyncJob{ID: 2, UserID: 4, Status: domain.SyncJobRunning, Instance: "other-replica"}
+	s.mockRepository.SetGetByInstance(func(instance string, statuses ...string) ([]domain.SyncJob, errorUtils.EntityError) {
+		assert.EqualValues(s.T(), []string{domain.SyncJobQueued, domain.SyncJobRunning}, statuses)
+		var jobs []domain.SyncJob
+		for _, job := range s.jobs {
+			if job.Instance == instance {
+				jobs = append(jobs, job)
+			}
+		}
+		return jobs, nil
+	})
+
+	s.syncService = services.NewSyncJobsService(10, "api-1")
+	s.syncService.Start(0)
+	t := s.T()
+	assert.EqualValues(t, domain.SyncJobFailed, s.jobs[job.ID].Status)
+	assert.NotEmpty(t, s.jobs[job.ID].Error)
+	//still running on the other replica
+	assert.EqualValues(t, domain.SyncJobRunning, s.jobs[2].Status)
+}
+
+//blockingSteam holds the owned games until the job's context is done
+type blockingSteam struct {
+	*mocks.SteamUserMock
+	started chan struct{}
+}
+
+func (b *blockingSteam) GetUserOwnedGames(ctx context.Context, userID string) ([]Steam.OwnedGame, error) {
+	close(b.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *SyncJobsServiceTestSuite) TestStop_InterruptsSteamCalls() {
+	steam := &blockingSteam{SteamUserMock: &mocks.SteamUserMock{}, started: make(chan struct{})}
+	previous := Steam.ExternalSteamUserService
+	Steam.ExternalSteamUserService = steam
+	defer func() { Steam.ExternalSteamUserService = previous }()
+
+	s.syncService.Start(1)
+	job, _ := s.syncService.EnqueueSync(&domain.User{ID: 3, SteamUserId: "steam-3"})
+	<-steam.started
+
+	stopped := make(chan struct{})
+	go func() {
+		s.syncService.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		s.T().Fatal("Stop waited for the Steam call")
+	}
+	t := s.T()
+	assert.EqualValues(t, domain.SyncJobFailed, s.jobs[job.ID].Status)
+	assert.EqualValues(t, "interrupted by a server shutdown", s.jobs[job.ID].Error)
+}
+
+func TestNewSyncJobsServiceFromEnv_RequiresInstance(t *testing.T) {
+	previous, wasSet := os.LookupEnv("SYNC_INSTANCE")
+	defer func() {
+		if wasSet {
+			_ = os.Setenv("SYNC_INSTANCE", previous)
+		} else {
+			_ = os.Unsetenv("SYNC_INSTANCE")
+		}
+	}()
+
+	_ = os.Setenv("SYNC_INSTANCE", " ")
+	service, err := services.NewSyncJobsServiceFromEnv()
+	assert.Nil(t, service)
+	assert.NotNil(t, err)
+
+	_ = os.Setenv("SYNC_INSTANCE", "api-1")
+	service, err = services.NewSyncJobsServiceFromEnv()
+	assert.Nil(t, err)
+	assert.NotNil(t, service)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"GamesAPI/src/domain"
+	"GamesAPI/src/services"
+	"GamesAPI/src/utils/errorUtils"
+	"GamesAPI/tests/unit/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type UserGamesServiceTestSuite struct {
+	suite.Suite
+	mockRepository mocks.UserGameRepoMockInterface
+}
+
+func TestUserGamesServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(UserGamesServiceTestSuite))
+}
+
+func (s *UserGamesServiceTestSuite) SetupSuite() {
+	mock := &mocks.UserGameRepoMock{}
+	s.mockRepository = mock
+	domain.UserGameRepo = mock
+}
+
+func (s *UserGamesServiceTestSuite) TestGetLibrary_Success() {
+	s.mockRepository.SetGetByUserID(func(userId uint64) ([]domain.UserGame, errorUtils.EntityError) {
+		return []domain.UserGame{
+			{ID: 1, UserID: userId, GameID: 10, Source: domain.UserGameSourceSteam, PlaytimeForever: 42},
+		}, nil
+	})
+
+	library, err := services.UserGamesService.GetLibrary(3)
+	t := s.T()
+	assert.Nil(t, err)
+	assert.Len(t, library, 1)
+	assert.EqualValues(t, 3, library[0].UserID)
+	assert.EqualValues(t, 42, library[0].PlaytimeForever)
+}
+
+func (s *UserGamesServiceTestSuite) TestSyncLibraryEntry_Invalid() {
+	entry, err := services.UserGamesService.SyncLibraryEntry(&domain.UserGame{UserID: 1, Source: domain.UserGameSourceSteam})
+	t := s.T()
+	assert.Nil(t, entry)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, err.Status())
+}
+
+func (s *UserGamesServiceTestSuite) TestSyncLibraryEntry_CreatesMissingEntry() {
+	s.mockRepository.SetGetByUserAndGame(func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+		return nil, errorUtils.NewNotFoundError("record not found")
+	})
+	created := false
+	s.mockRepository.SetCreate(func(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError) {
+		created = true
+		userGame.ID = 7
+		return userGame, nil
+	})
+
+	entry, err := services.UserGamesService.SyncLibraryEntry(&domain.UserGame{
+		UserID: 1, GameID: 10, Source: domain.UserGameSourceSteam, PlaytimeForever: 60,
+	})
+	t := s.T()
+	assert.Nil(t, err)
+	assert.True(t, created)
+	assert.EqualValues(t, 7, entry.ID)
+	assert.NotNil(t, entry.LastSyncedAt)
+}
+
+func (s *UserGamesServiceTestSuite) TestSyncLibraryEntry_LookupFails() {
+	s.mockRepository.SetGetByUserAndGame(func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+		return nil, errorUtils.NewInternalServerError("connection reset")
+	})
+	created := false
+	s.mockRepository.SetCreate(func(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError) {
+		created = true
+		return userGame, nil
+	})
+
+	entry, err := services.UserGamesService.SyncLibraryEntry(&domain.UserGame{
+		UserID: 1, GameID: 10, Source: domain.UserGameSourceSteam, PlaytimeForever: 60,
+	})
+	t := s.T()
+	assert.Nil(t, entry)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.Status())
+	assert.False(t, created)
+}
+
+func (s *UserGamesServiceTestSuite) TestSyncLibraryEntry_UpdatesPlaytime() {
+	s.mockRepository.SetGetByUserAndGame(func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+		return &domain.UserGame{ID: 7, UserID: userId, GameID: gameId, Source: source, PlaytimeForever: 10}, nil
+	})
+	s.mockRepository.SetUpdate(func(userGame *domain.UserGame) (*domain.UserGame, errorUtils.EntityError) {
+		return userGame, nil
+	})
+
+	entry, err := services.UserGamesService.SyncLibraryEntry(&domain.UserGame{
+		UserID: 1, GameID: 10, Source: domain.UserGameSourceSteam, PlaytimeForever: 60, PlaytimeLinuxForever: 5,
+	})
+	t := s.T()
+	assert.Nil(t, err)
+	assert.EqualValues(t, 7, entry.ID)
+	assert.EqualValues(t, 60, entry.PlaytimeForever)
+	assert.EqualValues(t, 5, entry.PlaytimeLinuxForever)
+	assert.NotNil(t, entry.LastSyncedAt)
+}
+
+func (s *UserGamesServiceTestSuite) TestDeleteLibraryEntry_NotFound() {
+	expected := errorUtils.NewNotFoundError("record not found")
+	s.mockRepository.SetGetByUserAndGame(func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+		return nil, expected
+	})
+
+	err := services.UserGamesService.DeleteLibraryEntry(1, 10, domain.UserGameSourceSteam)
+	assert.Equal(s.T(), expected, err)
+}
+
+func (s *UserGamesServiceTestSuite) TestDeleteLibraryEntry_Success() {
+	s.mockRepository.SetGetByUserAndGame(func(userId uint64, gameId uint64, source string) (*domain.UserGame, errorUtils.EntityError) {
+		return &domain.UserGame{ID: 7, UserID: userId, GameID: gameId, Source: source}, nil
+	})
+	var deleted uint64
+	s.mockRepository.SetDelete(func(id uint64) errorUtils.EntityError {
+		deleted = id
+		return nil
+	})
+
+	err := services.UserGamesService.DeleteLibraryEntry(1, 10, domain.UserGameSourceSteam)
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 7, deleted)
+}
@@ -3,9 +3,11 @@ package services
 import (
 	"GamesAPI/src/domain"
 	"GamesAPI/src/services"
+	"GamesAPI/src/utils/authUtils"
 	"GamesAPI/src/utils/errorUtils"
 	"GamesAPI/tests/unit/mocks"
-	"fmt"
+	"encoding/base64"
+	"net/http"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -36,10 +38,43 @@ var (
 )
 
 func (s *UserSessionServiceTestSuite) TestGenerateSessionToken_Success() {
-	token, err := services.UserSessionService.GenerateSessionToken(testUserId, testTimeNow)
+	token, err := services.UserSessionService.GenerateSessionToken()
 	assert.NotNil(s.T(), token)
 	assert.Nil(s.T(), err)
 
+	//256 random bits, base64 encoded
+	raw, decodeErr := base64.RawURLEncoding.DecodeString(token)
+	assert.Nil(s.T(), decodeErr)
+	assert.Len(s.T(), raw, 32)
+}
+
+func (s *UserSessionServiceTestSuite) TestGenerateSessionToken_Unique() {
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		token, err := services.UserSessionService.GenerateSessionToken()
+		assert.Nil(s.T(), err)
+		assert.False(s.T(), seen[token])
+		seen[token] = true
+	}
+}
+
+func (s *UserSessionServiceTestSuite) TestCreateSession_StoresHash() {
+	s.mockRepo.SetExists(func(key string) bool {
+		return false
+	})
+	var storedKey string
+	s.mockRepo.SetCreate(func(key string, token *domain.UserSession) (*domain.UserSession, errorUtils.EntityError) {
+		storedKey = key
+		return token, nil
+	})
+
+	sesh, err := services.UserSessionService.CreateSession(testSession)
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), authUtils.HashSessionToken(testToken), storedKey)
+	assert.EqualValues(s.T(), storedKey, sesh.Token)
+	assert.NotEqual(s.T(), testToken, storedKey)
+	//the caller's session still holds the plain token
+	assert.EqualValues(s.T(), testToken, testSession.Token)
 }
 
 func (s *UserSessionServiceTestSuite) TestCreateSession_Success() {
@@ -62,7 +97,7 @@ func (s *UserSessionServiceTestSuite) TestCreateSession_Failure_InvalidToken() {
 }
 
 func (s *UserSessionServiceTestSuite) TestCreateSession_Failure_TokenAlreadyExists() {
-	expected := errorUtils.NewUnprocessableEntityError(fmt.Sprintf("token with key %s already exists", testToken))
+	expected := errorUtils.NewUnprocessableEntityError("a session already exists for this token")
 	s.mockRepo.SetExists(func(key string) bool {
 		return true
 	})
@@ -97,7 +132,7 @@ func (s *UserSessionServiceTestSuite) TestDeleteSession_Success() {
 }
 
 func (s *UserSessionServiceTestSuite) TestDeleteSession_Failure_NotFound() {
-	expected := errorUtils.NewNotFoundError(fmt.Sprintf("token with key %s does not exist", testToken))
+	expected := errorUtils.NewNotFoundError("session does not exist for given token")
 	s.mockRepo.SetExists(func(key string) bool {
 		return false
 	})
@@ -158,7 +193,7 @@ func (s *UserSessionServiceTestSuite) TestSessionExpired_Failure_ErrorGettingSes
 }
 
 func (s *UserSessionServiceTestSuite) TestSessionExpired_Failure_SessionNotFound() {
-	expected := errorUtils.NewNotFoundError(fmt.Sprintf("token with key %s does not exist", testToken))
+	expected := errorUtils.NewNotFoundError("session does not exist for given token")
 	s.mockRepo.SetExists(func(key string) bool {
 		return false
 	})
@@ -169,3 +204,97 @@ func (s *UserSessionServiceTestSuite) TestSessionExpired_Failure_SessionNotFound
 	assert.True(s.T(), expired)
 	assert.Equal(s.T(), expected, err)
 }
+
+func (s *UserSessionServiceTestSuite) TestSessionReaper_DeletesExpired() {
+	reaped := make(chan time.Time, 1)
+	s.mockRepo.SetDeleteExpired(func(now time.Time) (int64, errorUtils.EntityError) {
+		select {
+		case reaped <- now:
+		default:
+		}
+		return 1, nil
+	})
+
+	stop := services.StartSessionReaper(time.Millisecond)
+	defer stop()
+
+	select {
+	case now := <-reaped:
+		assert.False(s.T(), now.IsZero())
+	case <-time.After(time.Second):
+		s.T().Fatal("the reaper never ran")
+	}
+}
+
+//the sessions store failing does not keep the expired refresh tokens around
+func (s *UserSessionServiceTestSuite) TestSessionReaper_KeepsGoingOnFailure() {
+	refreshTokenRepo := domain.RefreshTokenRepo
+	defer func() { domain.RefreshTokenRepo = refreshTokenRepo }()
+	domain.RefreshTokenRepo = domain.NewRefreshTokenRepository()
+	_, err := domain.RefreshTokenRepo.Create(&domain.RefreshToken{Token: "expired", FamilyId: "f", UserId: testUserId, ExpiresAt: 1})
+	assert.Nil(s.T(), err)
+	s.mockRepo.SetDeleteExpired(func(now time.Time) (int64, errorUtils.EntityError) {
+		return 0, errorUtils.NewInternalServerError("connection reset")
+	})
+
+	stop := services.StartSessionReaper(time.Millisecond)
+	defer stop()
+
+	assert.Eventually(s.T(), func() bool {
+		_, err := domain.RefreshTokenRepo.Get("expired")
+		return err != nil
+	}, time.Second, time.Millisecond)
+}
+
+func (s *UserSessionServiceTestSuite) TestGetUserSessions_SkipsExpired() {
+	s.mockRepo.SetGetByUserID(func(userId uint64) ([]domain.UserSession, errorUtils.EntityError) {
+		return []domain.UserSession{
+			{Token: "a", UserId: userId, ExpiresAt: time.Now().Add(time.Hour).UnixNano()},
+			{Token: "b", UserId: userId, ExpiresAt: time.Now().Add(-time.Hour).UnixNano()},
+		}, nil
+	})
+
+	sessions, err := services.UserSessionService.GetUserSessions(testUserId)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), sessions, 1)
+	assert.EqualValues(s.T(), "a", sessions[0].Token)
+}
+
+func (s *UserSessionServiceTestSuite) TestRevokeSession_Success() {
+	s.mockRepo.SetGet(func(key string) (*domain.UserSession, errorUtils.EntityError) {
+		return &domain.UserSession{Token: key, UserId: testUserId}, nil
+	})
+	deleted := ""
+	s.mockRepo.SetDelete(func(key string) errorUtils.EntityError {
+		deleted = key
+		return nil
+	})
+
+	err := services.UserSessionService.RevokeSession(testUserId, "abc")
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), "abc", deleted)
+}
+
+func (s *UserSessionServiceTestSuite) TestRevokeSession_OtherUser() {
+	s.mockRepo.SetGet(func(key string) (*domain.UserSession, errorUtils.EntityError) {
+		return &domain.UserSession{Token: key, UserId: testUserId + 1}, nil
+	})
+	s.mockRepo.SetDelete(func(key string) errorUtils.EntityError {
+		s.T().Error("another user's session should not be deleted")
+		return nil
+	})
+
+	err := services.UserSessionService.RevokeSession(testUserId, "abc")
+	assert.NotNil(s.T(), err)
+	assert.EqualValues(s.T(), http.StatusNotFound, err.Status())
+}
+
+func (s *UserSessionServiceTestSuite) TestRevokeUserSessions_Success() {
+	s.mockRepo.SetDeleteByUserID(func(userId uint64) (int64, errorUtils.EntityError) {
+		return 3, nil
+	})
+
+	revoked, err := services.UserSessionService.RevokeUserSessions(testUserId)
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 3, revoked)
+}
@@ -18,7 +18,8 @@ var (
 
 type UserServiceTestSuite struct {
 	suite.Suite
-	mockRepository mocks.UserRepoMockInterface
+	mockRepository     mocks.UserRepoMockInterface
+	mockRoleRepository mocks.UserRoleRepoMockInterface
 }
 
 func TestUserServiceTestSuite(t *testing.T) {
@@ -30,6 +31,16 @@ func (s *UserServiceTestSuite) SetupSuite() {
 
 	s.mockRepository = mock //set this so we can swap the methods
 	domain.UserRepo = mock  //set this so the tested code calls the swapped methods
+
+	roleMock := &mocks.UserRoleRepoMock{}
+	s.mockRoleRepository = roleMock
+	domain.UserRoleRepo = roleMock
+}
+
+func (s *UserServiceTestSuite) BeforeTest(_, _ string) {
+	s.mockRoleRepository.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return nil, nil
+	})
 }
 
 func (s *UserServiceTestSuite) TestUsersService_GetUser_Success() {
@@ -159,12 +170,49 @@ func (s *UserServiceTestSuite) TestUsersService_UpdateUser_Success() {
 
 	request := expectedAfter
 
-	user, err := services.UsersService.UpdateUser(request)
+	user, err := services.UsersService.UpdateUser(request, "name", "email")
 	assert.Nil(s.T(), err)
 	assert.NotNil(s.T(), user)
 	assert.Equal(s.T(), expectedAfter, user)
 }
 
+func (s *UserServiceTestSuite) TestUsersService_UpdateUser_OnlySentFields() {
+	s.mockRepository.SetGetUserDomain(func(u uint64) (*domain.User, errorUtils.EntityError) {
+		return &domain.User{ID: 1, Name: "devBefore", Email: "devBefore@test.com", SteamUserId: "765"}, nil
+	})
+	var updated *domain.User
+	s.mockRepository.SetUpdateUserDomain(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+		updated = user
+		return user, nil
+	})
+
+	_, err := services.UsersService.UpdateUser(&domain.User{ID: 1, Name: "devAfter"}, "NAME")
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), "devAfter", updated.Name)
+	assert.EqualValues(s.T(), "devBefore@test.com", updated.Email)
+	assert.EqualValues(s.T(), "765", updated.SteamUserId)
+
+	_, err = services.UsersService.UpdateUser(&domain.User{ID: 1}, "steam_user_id")
+	assert.Nil(s.T(), err)
+	assert.EqualValues(s.T(), "", updated.SteamUserId)
+}
+
+func (s *UserServiceTestSuite) TestUsersService_UpdateUser_NotWritable() {
+	s.mockRepository.SetGetUserDomain(func(u uint64) (*domain.User, errorUtils.EntityError) {
+		return &domain.User{ID: 1, Name: "dev", Email: "dev@test.com", PasswordHash: "hash"}, nil
+	})
+	s.mockRepository.SetUpdateUserDomain(func(user *domain.User) (*domain.User, errorUtils.EntityError) {
+		assert.Fail(s.T(), "a user should not be updated with a field which is not writable")
+		return user, nil
+	})
+
+	for _, field := range []string{"password_hash", "id", "created_at"} {
+		user, err := services.UsersService.UpdateUser(&domain.User{ID: 1, Name: "devAfter", PasswordHash: "other"}, "name", field)
+		assert.Nil(s.T(), user, field)
+		assert.Equal(s.T(), errorUtils.NewUnprocessableEntityError("User field '"+field+"' cannot be updated"), err, field)
+	}
+}
+
 func (s *UserServiceTestSuite) TestUsersService_UpdateUser_InvalidRequest() {
 	tests := []struct {
 		request       *domain.User
@@ -195,8 +243,11 @@ func (s *UserServiceTestSuite) TestUsersService_UpdateUser_InvalidRequest() {
 			expectedError: errorUtils.NewUnprocessableEntityError("User email is not formatted correctly"),
 		},
 	}
+	s.mockRepository.SetGetUserDomain(func(u uint64) (*domain.User, errorUtils.EntityError) {
+		return &domain.User{ID: 1, Name: "dev", Email: "dev@test.com"}, nil
+	})
 	for _, tt := range tests {
-		msg, err := services.UsersService.UpdateUser(tt.request)
+		msg, err := services.UsersService.UpdateUser(tt.request, "name", "email")
 		assert.Nil(s.T(), msg)
 		assert.NotNil(s.T(), err)
 		assert.Equal(s.T(), tt.expectedError, err)
@@ -211,7 +262,7 @@ func (s *UserServiceTestSuite) TestUsersService_UpdateUser_FailureGettingFormerU
 		Name:  "dev",
 		Email: "dev@test.com",
 	}
-	msg, err := services.UsersService.UpdateUser(request)
+	msg, err := services.UsersService.UpdateUser(request, "name", "email")
 	t := s.T()
 	assert.Nil(t, msg)
 	assert.NotNil(t, err)
@@ -237,7 +288,7 @@ func (s *UserServiceTestSuite) TestUsersService_UpdateUser_FailureUpdatingUser()
 		Name:  "devAAA",
 		Email: "devAAA@test.com",
 	}
-	msg, err := services.UsersService.UpdateUser(request)
+	msg, err := services.UsersService.UpdateUser(request, "name", "email")
 	t := s.T()
 	assert.Nil(t, msg)
 	assert.NotNil(t, err)
@@ -262,6 +313,55 @@ func (s *UserServiceTestSuite) TestUsersService_DeleteUser_Success() {
 	assert.Nil(s.T(), err)
 }
 
+func (s *UserServiceTestSuite) TestUsersService_DeleteUser_LastAdmin() {
+	s.mockRepository.SetGetUserDomain(func(u uint64) (*domain.User, errorUtils.EntityError) {
+		return &domain.User{ID: 1, Name: "dev", Email: "dev@test.com"}, nil
+	})
+	s.mockRoleRepository.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 4, UserID: userId, Name: "user"}, {ID: 5, UserID: userId, Name: domain.AdminRoleName}}, nil
+	})
+	s.mockRoleRepository.SetGetRolesByRoleName(func(roleName string) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 5, UserID: 1, Name: domain.AdminRoleName}}, nil
+	})
+	deleted := false
+	s.mockRepository.SetDeleteUserDomain(func(_ uint64) errorUtils.EntityError {
+		deleted = true
+		return nil
+	})
+
+	err := services.UsersService.DeleteUser(1)
+	t := s.T()
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusConflict, err.Status())
+	assert.EqualValues(t, "user 1 is the last admin, give the role to someone else first", err.Message())
+	assert.False(t, deleted)
+}
+
+func (s *UserServiceTestSuite) TestUsersService_DeleteUser_Admin() {
+	s.mockRepository.SetGetUserDomain(func(u uint64) (*domain.User, errorUtils.EntityError) {
+		return &domain.User{ID: 1, Name: "dev", Email: "dev@test.com"}, nil
+	})
+	s.mockRoleRepository.SetGetRolesByUserID(func(userId uint64) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 5, UserID: userId, Name: domain.AdminRoleName}}, nil
+	})
+	s.mockRoleRepository.SetGetRolesByRoleName(func(roleName string) ([]domain.UserRole, errorUtils.EntityError) {
+		return []domain.UserRole{{ID: 5, UserID: 1, Name: domain.AdminRoleName}, {ID: 6, UserID: 2, Name: domain.AdminRoleName}}, nil
+	})
+	s.mockRepository.SetDeleteUserDomain(func(_ uint64) errorUtils.EntityError {
+		return nil
+	})
+	var deletedRoles []uint64
+	s.mockRoleRepository.SetDeleteRole(func(roleId uint64) errorUtils.EntityError {
+		deletedRoles = append(deletedRoles, roleId)
+		return nil
+	})
+
+	err := services.UsersService.DeleteUser(1)
+	assert.Nil(s.T(), err)
+	//so user 2 is the last admin from now on
+	assert.EqualValues(s.T(), []uint64{5}, deletedRoles)
+}
+
 func (s *UserServiceTestSuite) TestUsersService_DeleteUser_ErrorGettingUser() {
 	expectedError := errorUtils.NewInternalServerError("Something went wrong fetching user")
 	s.mockRepository.SetGetUserDomain(func(u uint64) (*domain.User, errorUtils.EntityError) {
@@ -2,6 +2,12 @@ package utils
 
 import (
 	"GamesAPI/src/utils/authUtils"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -9,6 +15,8 @@ import (
 	"time"
 )
 
+const testJwtSecret = "a-test-secret-that-is-long-enough-for-hs256"
+
 type AuthUtilsTestSuite struct {
 	suite.Suite
 }
@@ -17,19 +25,139 @@ func TestAuthUtilsTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthUtilsTestSuite))
 }
 
+func (s *AuthUtilsTestSuite) SetupSuite() {
+	key, err := authUtils.NewJwtKey(authUtils.JwtAlgorithmHS256, "test", []byte(testJwtSecret))
+	require.Nil(s.T(), err)
+	authUtils.JwtKeys, err = authUtils.NewJwtKeySet("test", key)
+	require.Nil(s.T(), err)
+}
+
+func (s *AuthUtilsTestSuite) TearDownSuite() {
+	authUtils.JwtKeys = nil
+}
+
 func (s *AuthUtilsTestSuite) TestJwtSymmetric() {
 	var userId uint64 = 1
-	expiresAt := time.Now().Unix()
+	expiresAt := time.Now().Add(time.Minute).Unix()
 	jwtToken, errCreate := authUtils.JwtCreate(userId, expiresAt)
 	assert.Nil(s.T(), errCreate)
 
 	deserializedJwtToken, errDecode := authUtils.JwtDecode(jwtToken)
+	require.Nil(s.T(), errDecode)
 	claims := deserializedJwtToken.Claims.(*authUtils.UserClaims)
-	assert.Nil(s.T(), errDecode)
 	assert.Equal(s.T(), userId, claims.UserId)
 	assert.Equal(s.T(), expiresAt, claims.ExpiresAt)
 }
 
+func (s *AuthUtilsTestSuite) TestJwt_RS256RoundTrip() {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(s.T(), err)
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	key, err := authUtils.NewJwtKey(authUtils.JwtAlgorithmRS256, "rsa", keyPem)
+	require.Nil(s.T(), err)
+	set, err := authUtils.NewJwtKeySet("rsa", key)
+	require.Nil(s.T(), err)
+
+	token, err := set.Sign(userClaims(4, "admin"))
+	require.Nil(s.T(), err)
+	claims, err := set.Parse(token)
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 4, claims.UserId)
+	assert.EqualValues(s.T(), []string{"admin"}, claims.Roles)
+
+	jwks := set.JWKS()
+	require.Len(s.T(), jwks.Keys, 1)
+	assert.EqualValues(s.T(), "RSA", jwks.Keys[0].Kty)
+	assert.EqualValues(s.T(), "rsa", jwks.Keys[0].Kid)
+	assert.EqualValues(s.T(), "AQAB", jwks.Keys[0].E)
+}
+
+func (s *AuthUtilsTestSuite) TestJwt_EdDSARoundTrip() {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(s.T(), err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.Nil(s.T(), err)
+
+	key, err := authUtils.NewJwtKey(authUtils.JwtAlgorithmEdDSA, "ed", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.Nil(s.T(), err)
+	set, err := authUtils.NewJwtKeySet("ed", key)
+	require.Nil(s.T(), err)
+
+	token, err := set.Sign(userClaims(7, "user"))
+	require.Nil(s.T(), err)
+	claims, err := set.Parse(token)
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 7, claims.UserId)
+
+	jwks := set.JWKS()
+	require.Len(s.T(), jwks.Keys, 1)
+	assert.EqualValues(s.T(), "OKP", jwks.Keys[0].Kty)
+	assert.EqualValues(s.T(), "Ed25519", jwks.Keys[0].Crv)
+}
+
+func (s *AuthUtilsTestSuite) TestJwt_KeyRotation() {
+	oldPublic, oldPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	_, newPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	oldSigner := &authUtils.JwtKey{Kid: "old", Method: authUtils.SigningMethodEdDSA, SignKey: oldPrivate, VerifyKey: oldPublic}
+	oldSet, err := authUtils.NewJwtKeySet("old", oldSigner)
+	require.Nil(s.T(), err)
+	oldToken, err := oldSet.Sign(userClaims(1, "user"))
+	require.Nil(s.T(), err)
+
+	//after the rotation, only the public part of the old key is kept
+	publicDer, _ := x509.MarshalPKIXPublicKey(oldPublic)
+	oldVerifier, err := authUtils.NewJwtKey(authUtils.JwtAlgorithmEdDSA, "old", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}))
+	require.Nil(s.T(), err)
+	newSigner := &authUtils.JwtKey{Kid: "new", Method: authUtils.SigningMethodEdDSA, SignKey: newPrivate, VerifyKey: newPrivate.Public()}
+	set, err := authUtils.NewJwtKeySet("new", newSigner, oldVerifier)
+	require.Nil(s.T(), err)
+
+	_, err = set.Parse(oldToken)
+	assert.Nil(s.T(), err)
+	assert.Len(s.T(), set.JWKS().Keys, 2)
+
+	//a verify-only key cannot become the active one
+	_, err = authUtils.NewJwtKeySet("old", newSigner, oldVerifier)
+	assert.NotNil(s.T(), err)
+
+	//once the old key is dropped, its tokens are rejected
+	set, _ = authUtils.NewJwtKeySet("new", newSigner)
+	_, err = set.Parse(oldToken)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *AuthUtilsTestSuite) TestJwt_RejectsOtherAlgorithm() {
+	//a token signed with HS256 using the secret must not pass for an EdDSA key
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	set, _ := authUtils.NewJwtKeySet("k", &authUtils.JwtKey{Kid: "k", Method: authUtils.SigningMethodEdDSA, SignKey: privateKey, VerifyKey: privateKey.Public()})
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, userClaims(1, "admin"))
+	token.Header["kid"] = "k"
+	forged, err := token.SignedString([]byte(testJwtSecret))
+	require.Nil(s.T(), err)
+
+	_, err = set.Parse(forged)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *AuthUtilsTestSuite) TestJwt_RejectsExpiredToken() {
+	claims := userClaims(1, "user")
+	claims.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	token, err := authUtils.JwtKeys.Sign(claims)
+	require.Nil(s.T(), err)
+
+	_, err = authUtils.JwtKeys.Parse(token)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *AuthUtilsTestSuite) TestJwt_ShortSecret() {
+	_, err := authUtils.NewJwtKey(authUtils.JwtAlgorithmHS256, "k", []byte("short"))
+	assert.NotNil(s.T(), err)
+	//shared secrets are never published
+	assert.Len(s.T(), authUtils.JwtKeys.JWKS().Keys, 0)
+}
+
 func (s *AuthUtilsTestSuite) TestPasswordHashSymmetric() {
 	password := "this is the best password"
 	pwdBytes := []byte(password)
@@ -38,3 +166,11 @@ func (s *AuthUtilsTestSuite) TestPasswordHashSymmetric() {
 	areEqual, _ := authUtils.CompareStrings(hash, pwdBytes)
 	require.True(s.T(), areEqual)
 }
+
+func userClaims(userId uint64, roles ...string) *authUtils.UserClaims {
+	return &authUtils.UserClaims{
+		UserId:         userId,
+		Roles:          roles,
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Minute).Unix()},
+	}
+}